@@ -8,6 +8,8 @@ import (
 	"net/http"
 	"os"
 	"reflect"
+	"strconv"
+	"strings"
 
 	chaincode "github.com/direnbharwani/evote-capstone/chaincode/src"
 )
@@ -84,36 +86,54 @@ func ChaincodeQuery[T chaincode.ITYPES](signer, authToken, key string) (T, error
 	return chaincodeResponseBody.Result, nil
 }
 
-// Queries a range of objects from the blockchain's world state
+// Returns true if err is the chaincode's error for querying a key that is not in the world state.
+// Other errors, e.g. failing to reach the REST API Gateway, mean that the key may still exist.
+func IsChaincodeNotFound(err error, key string) bool {
+	notFound := &chaincode.WorldStateReadFailureError{Key: key}
+	return err != nil && strings.Contains(err.Error(), notFound.Error())
+}
+
+// Queries all objects of a type from the blockchain's world state, reading them a page at a time
 // Chaincode name, channel, and init are hardcoded
 func ChaincodeQueryAll[T chaincode.ITYPES](signer, authToken string) ([]T, error) {
-	var emptyObject T
+	return collectPages(NewChaincodeQueryAllIterator[T](signer, authToken, DefaultPageSize))
+}
 
-	function := fmt.Sprintf("QueryAll%ss", reflect.TypeOf(emptyObject).Name())
+// Queries the objects of an election from the blockchain's world state, i.e. Ballots or Candidates,
+// reading them a page at a time
+// Chaincode name, channel, and init are hardcoded
+func ChaincodeQueryByElection[T chaincode.ITYPES](signer, authToken, electionID string) ([]T, error) {
+	return collectPages(NewChaincodeQueryByElectionIterator[T](signer, authToken, electionID, DefaultPageSize))
+}
 
-	chaincodeResponse, err := invokeChaincode(Query, signer, authToken, function, []string{})
-	if err != nil {
-		return []T{}, fmt.Errorf("%v", err)
-	}
+// The voter is the signer, which must be the identity the ballot was issued to
+func ChaincodeCastVote(signer, authToken, ballotID, candidateID string) error {
+	function := "CastVote"
+	args := []string{ballotID, candidateID}
 
-	// Temporary struct to convert the type accordingly
-	type ChaincodeQueryRespondeBody struct {
-		Headers map[string]interface{} `json:"headers"`
-		Result  []T                    `json:"result"`
+	if _, err := invokeChaincode(Transaction, signer, authToken, function, args); err != nil {
+		return err
 	}
 
-	var chaincodeResponseBody ChaincodeQueryRespondeBody
-	err = json.Unmarshal(chaincodeResponse, &chaincodeResponseBody)
+	return nil
+}
+
+// Casts a vote with counts encrypted by the client, as returned by chaincode.Ballot.EncryptVote.
+// The voter is the signer, which must be the identity the ballot was issued to.
+func ChaincodeCastEncryptedVote(signer, authToken, ballotID string, encryptedCounters, proofs []string) error {
+	function := "CastEncryptedVote"
+
+	encryptedCountersData, err := json.Marshal(encryptedCounters)
 	if err != nil {
-		return []T{}, fmt.Errorf("error parsing chaincode response: %v", err)
+		return err
 	}
 
-	return chaincodeResponseBody.Result, nil
-}
+	proofsData, err := json.Marshal(proofs)
+	if err != nil {
+		return err
+	}
 
-func ChaincodeCastVote(signer, authToken, ballotID, candidateID string) error {
-	function := "CastVote"
-	args := []string{signer, ballotID, candidateID}
+	args := []string{ballotID, string(encryptedCountersData), string(proofsData)}
 
 	if _, err := invokeChaincode(Transaction, signer, authToken, function, args); err != nil {
 		return err
@@ -133,6 +153,148 @@ func ChaincodeSync(signer, authToken, electionID string) error {
 	return nil
 }
 
+func ChaincodeOpenElection(signer, authToken, electionID string) error {
+	function := "OpenElection"
+	args := []string{electionID}
+
+	if _, err := invokeChaincode(Transaction, signer, authToken, function, args); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Computes the encrypted tally of a Closed election on the ledger, which can then be queried with ChaincodeQuery
+func ChaincodeComputeTally(signer, authToken, electionID string) error {
+	function := "ComputeTally"
+	args := []string{electionID}
+
+	if _, err := invokeChaincode(Transaction, signer, authToken, function, args); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// =============================================================================
+// Pagination
+// =============================================================================
+
+// Number of objects read per page by ChaincodeQueryAll & ChaincodeQueryByElection,
+// which keeps each response within the size limit of the REST API Gateway
+const DefaultPageSize = 100
+
+// Iterates over the pages of a paginated query, following the bookmark of each page.
+//
+//	pages := common.NewChaincodeQueryAllIterator[chaincode.Ballot](signer, authToken, common.DefaultPageSize)
+//	for pages.Next() {
+//		count(pages.Page())
+//	}
+//	if err := pages.Err(); err != nil {
+//		return err
+//	}
+type ChaincodePageIterator[T chaincode.ITYPES] struct {
+	signer    string
+	authToken string
+	function  string
+	args      []string
+	pageSize  int32
+
+	bookmark string
+	done     bool
+	err      error
+	page     []T
+}
+
+// Iterates over all objects of a type with QueryAll<Type>sWithPagination
+func NewChaincodeQueryAllIterator[T chaincode.ITYPES](signer, authToken string, pageSize int32) *ChaincodePageIterator[T] {
+	var emptyObject T
+
+	return &ChaincodePageIterator[T]{
+		signer:    signer,
+		authToken: authToken,
+		function:  fmt.Sprintf("QueryAll%ssWithPagination", reflect.TypeOf(emptyObject).Name()),
+		args:      []string{},
+		pageSize:  pageSize,
+	}
+}
+
+// Iterates over the objects of an election with Query<Type>sByElectionWithPagination
+func NewChaincodeQueryByElectionIterator[T chaincode.ITYPES](signer, authToken, electionID string, pageSize int32) *ChaincodePageIterator[T] {
+	var emptyObject T
+
+	return &ChaincodePageIterator[T]{
+		signer:    signer,
+		authToken: authToken,
+		function:  fmt.Sprintf("Query%ssByElectionWithPagination", reflect.TypeOf(emptyObject).Name()),
+		args:      []string{electionID},
+		pageSize:  pageSize,
+	}
+}
+
+// Reads the next page. Returns false once every page has been read or if the query fails, which is returned by Err.
+func (p *ChaincodePageIterator[T]) Next() bool {
+	if p.done || p.err != nil {
+		return false
+	}
+
+	args := append(append([]string{}, p.args...), strconv.Itoa(int(p.pageSize)), p.bookmark)
+
+	chaincodeResponse, err := invokeChaincode(Query, p.signer, p.authToken, p.function, args)
+	if err != nil {
+		p.err = fmt.Errorf("%v", err)
+		return false
+	}
+
+	// Temporary struct to convert the type accordingly
+	type ChaincodeQueryRespondeBody struct {
+		Headers map[string]interface{} `json:"headers"`
+		Result  struct {
+			Bookmark string `json:"Bookmark"`
+			Count    int32  `json:"Count"`
+			Results  []T    `json:"Results"`
+		} `json:"result"`
+	}
+
+	var chaincodeResponseBody ChaincodeQueryRespondeBody
+	if err = json.Unmarshal(chaincodeResponse, &chaincodeResponseBody); err != nil {
+		p.err = fmt.Errorf("error parsing chaincode response: %v", err)
+		return false
+	}
+
+	p.page = chaincodeResponseBody.Result.Results
+	p.bookmark = chaincodeResponseBody.Result.Bookmark
+
+	// The last page has fewer objects than the page size, or no bookmark to follow
+	p.done = chaincodeResponseBody.Result.Count < p.pageSize || p.bookmark == ""
+
+	return true
+}
+
+// Returns the objects of the page read by the last call to Next
+func (p *ChaincodePageIterator[T]) Page() []T {
+	return p.page
+}
+
+// Returns the error that stopped the iteration, if any
+func (p *ChaincodePageIterator[T]) Err() error {
+	return p.err
+}
+
+// Reads every page into a single slice
+func collectPages[T chaincode.ITYPES](pages *ChaincodePageIterator[T]) ([]T, error) {
+	results := []T{}
+	for pages.Next() {
+		results = append(results, pages.Page()...)
+	}
+
+	if err := pages.Err(); err != nil {
+		return []T{}, err
+	}
+
+	return results, nil
+}
+
 // =============================================================================
 // Helpers
 // =============================================================================
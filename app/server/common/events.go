@@ -0,0 +1,154 @@
+package common
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	chaincode "github.com/direnbharwani/evote-capstone/chaincode/src"
+)
+
+// =============================================================================
+// Event Types
+// =============================================================================
+
+// An event emitted by the chaincode, as delivered by an event stream of the REST API Gateway.
+// Payload is the JSON payload of the event, which is a string if the subscription delivers the payload as bytes
+// (base64) or as a string.
+type ChaincodeEvent struct {
+	BlockNumber   uint64          `json:"blockNumber"`
+	ChaincodeID   string          `json:"chaincodeId"`
+	EventName     string          `json:"eventName"`
+	Payload       json.RawMessage `json:"payload"`
+	TransactionID string          `json:"transactionId"`
+}
+
+// Called with every event received by a ChaincodeEventListener, and its payload decoded by Decode
+type ChaincodeEventHandler func(event ChaincodeEvent, payload interface{}) error
+
+// =============================================================================
+// Decoding
+// =============================================================================
+
+// Decodes the payload of the event into the event type of its name, e.g. chaincode.VoteCastEvent for VoteCast
+func (e ChaincodeEvent) Decode() (interface{}, error) {
+	switch chaincode.EventName(e.EventName) {
+	case chaincode.EventElectionCreated:
+		return decodeEventPayload[chaincode.ElectionCreatedEvent](e)
+	case chaincode.EventCandidateAdded:
+		return decodeEventPayload[chaincode.CandidateAddedEvent](e)
+	case chaincode.EventBallotIssued:
+		return decodeEventPayload[chaincode.BallotIssuedEvent](e)
+	case chaincode.EventVoteCast:
+		return decodeEventPayload[chaincode.VoteCastEvent](e)
+	case chaincode.EventElectionClosed:
+		return decodeEventPayload[chaincode.ElectionClosedEvent](e)
+	case chaincode.EventTallyPublished:
+		return decodeEventPayload[chaincode.TallyPublishedEvent](e)
+	}
+
+	return nil, fmt.Errorf("unknown chaincode event %q in transaction %s", e.EventName, e.TransactionID)
+}
+
+// Decodes the body of an event stream delivery, which holds a single event or a batch of events
+func DecodeChaincodeEvents(body []byte) ([]ChaincodeEvent, error) {
+	body = bytes.TrimSpace(body)
+
+	if len(body) > 0 && body[0] == '{' {
+		var event ChaincodeEvent
+		if err := json.Unmarshal(body, &event); err != nil {
+			return nil, fmt.Errorf("failed to parse chaincode event: %v", err)
+		}
+
+		return []ChaincodeEvent{event}, nil
+	}
+
+	var events []ChaincodeEvent
+	if err := json.Unmarshal(body, &events); err != nil {
+		return nil, fmt.Errorf("failed to parse chaincode events: %v", err)
+	}
+
+	return events, nil
+}
+
+func decodeEventPayload[T any](event ChaincodeEvent) (T, error) {
+	var payload T
+
+	data := []byte(event.Payload)
+
+	// Payloads delivered as bytes are a base64 string & payloads delivered as strings are the JSON as a string
+	var encoded string
+	if err := json.Unmarshal(data, &encoded); err == nil {
+		if decoded, err := base64.StdEncoding.DecodeString(encoded); err == nil {
+			data = decoded
+		} else {
+			data = []byte(encoded)
+		}
+	}
+
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return payload, fmt.Errorf("failed to parse payload of %s event: %v", event.EventName, err)
+	}
+
+	return payload, nil
+}
+
+// =============================================================================
+// Listener
+// =============================================================================
+
+// Receives the events of an event stream of the REST API Gateway, which posts them to the URL the listener is
+// served at, e.g. with http.ListenAndServe. Events are passed to Handle in the order they were delivered.
+// Deliveries with an event that fails to be decoded or handled are answered with an error, so that they are retried.
+type ChaincodeEventListener struct {
+	Handle ChaincodeEventHandler
+}
+
+func NewChaincodeEventListener(handle ChaincodeEventHandler) *ChaincodeEventListener {
+	return &ChaincodeEventListener{Handle: handle}
+}
+
+func (l *ChaincodeEventListener) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, fmt.Sprintf("method %s not allowed", r.Method), http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("error reading chaincode events: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	if err = l.HandleEvents(body); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// Decodes & handles the events of a delivery, stopping at the first event that fails.
+// Lambdas behind API Gateway call this with the request body instead of serving the listener.
+func (l *ChaincodeEventListener) HandleEvents(body []byte) error {
+	events, err := DecodeChaincodeEvents(body)
+	if err != nil {
+		return err
+	}
+
+	for _, event := range events {
+		payload, err := event.Decode()
+		if err != nil {
+			return err
+		}
+
+		if err = l.Handle(event, payload); err != nil {
+			return fmt.Errorf("failed to handle %s event in transaction %s: %v", event.EventName, event.TransactionID, err)
+		}
+	}
+
+	return nil
+}
@@ -3,6 +3,7 @@ package common
 import (
 	"encoding/json"
 	"fmt"
+	"os"
 
 	paillier "github.com/direnbharwani/evote-capstone/paillier"
 )
@@ -29,5 +30,72 @@ func DecodeKeys(publicKeyBase64, privateKeyBase64 string) (*paillier.PublicKey,
 		return nil, nil, fmt.Errorf("error decoding private key: %v", err)
 	}
 
+	if err = paillier.ValidatePrivateKey(publicKey, privateKey); err != nil {
+		return nil, nil, fmt.Errorf("invalid key pair: %v", err)
+	}
+
 	return publicKey, privateKey, nil
 }
+
+// Decodes the public key and loads the matching private key from the lambda's environment.
+// If PAILLIER_PRIVATE_KEYSTORE is set, the private key is unlocked from the base64 encoded keystore
+// with PAILLIER_KEYSTORE_PASSPHRASE. Otherwise, the raw base64 PAILLIER_PRIVATE_KEY is used.
+func LoadKeys(publicKeyBase64 string) (*paillier.PublicKey, *paillier.PrivateKey, error) {
+	keystoreBase64 := os.Getenv("PAILLIER_PRIVATE_KEYSTORE")
+	if keystoreBase64 == "" {
+		return DecodeKeys(publicKeyBase64, os.Getenv("PAILLIER_PRIVATE_KEY"))
+	}
+
+	publicKey, err := paillier.Base64Decode[paillier.PublicKey](publicKeyBase64)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error decoding public key: %v", err)
+	}
+
+	keystore, err := paillier.Base64Decode[paillier.Keystore](keystoreBase64)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error decoding keystore: %v", err)
+	}
+
+	if keystore.PublicKeyFingerprint != publicKey.Fingerprint() {
+		return nil, nil, fmt.Errorf("keystore does not match public key %s", publicKey.Fingerprint())
+	}
+
+	privateKey, err := paillier.UnlockPrivateKey(keystore, []byte(os.Getenv("PAILLIER_KEYSTORE_PASSPHRASE")))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err = paillier.ValidatePrivateKey(publicKey, privateKey); err != nil {
+		return nil, nil, fmt.Errorf("invalid key pair: %v", err)
+	}
+
+	return publicKey, privateKey, nil
+}
+
+func DecodeThresholdKey(thresholdKeyBase64 string) (*paillier.ThresholdPublicKey, error) {
+	thresholdKey, err := paillier.Base64Decode[paillier.ThresholdPublicKey](thresholdKeyBase64)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding threshold public key: %v", err)
+	}
+
+	if err = paillier.ValidateThresholdPublicKey(thresholdKey); err != nil {
+		return nil, fmt.Errorf("invalid threshold public key: %v", err)
+	}
+
+	return thresholdKey, nil
+}
+
+func DecodePartialDecryptions(partialsBase64 []string) ([]*paillier.PartialDecryption, error) {
+	partials := []*paillier.PartialDecryption{}
+
+	for i := range partialsBase64 {
+		partial, err := paillier.Base64Decode[paillier.PartialDecryption](partialsBase64[i])
+		if err != nil {
+			return nil, fmt.Errorf("error decoding partial decryption: %v", err)
+		}
+
+		partials = append(partials, partial)
+	}
+
+	return partials, nil
+}
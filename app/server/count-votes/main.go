@@ -7,6 +7,8 @@ import (
 	"math/big"
 	"net/http"
 	"os"
+	"runtime"
+	"strconv"
 	"time"
 
 	"github.com/aws/aws-lambda-go/events"
@@ -26,25 +28,24 @@ func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 
 	startTime := time.Now()
 
-	ballots, err := common.ChaincodeQueryAll[chaincode.Ballot](requestBody.SignerID, os.Getenv("KALEIDO_AUTH_TOKEN"))
-	if err != nil {
-		errorResponse := common.GenerateErrorResponse(http.StatusBadRequest, fmt.Sprintf("%v", err))
+	// Elections tallied on the ledger are decrypted from their tally, so that everyone decrypts the same counts.
+	// Otherwise, the ballots are downloaded & added by this lambda. Ballots are only counted if the election has no
+	// tally, as any other error may hide a tally whose counts differ from the ballots this lambda can read.
+	tally, err := common.ChaincodeQuery[chaincode.Tally](requestBody.SignerID, os.Getenv("KALEIDO_AUTH_TOKEN"), requestBody.ElectionID)
+	if err != nil && !common.IsChaincodeNotFound(err, requestBody.ElectionID) {
+		errorResponse := common.GenerateErrorResponse(http.StatusBadRequest, fmt.Sprintf("failed to query tally of election %s: %v", requestBody.ElectionID, err))
 		return errorResponse, nil
 	}
+	if err == nil && tally.Asset.ID != "" {
+		return countTally(startTime, tally, requestBody.PartialDecryptions), nil
+	}
 
-	if len(ballots) == 0 {
-		errorResponse := common.GenerateErrorResponse(http.StatusBadRequest, "no ballots to count")
+	ballotsToCount, err := common.ChaincodeQueryByElection[chaincode.Ballot](requestBody.SignerID, os.Getenv("KALEIDO_AUTH_TOKEN"), requestBody.ElectionID)
+	if err != nil {
+		errorResponse := common.GenerateErrorResponse(http.StatusBadRequest, fmt.Sprintf("%v", err))
 		return errorResponse, nil
 	}
 
-	// Filter ballots that are part of specified election
-	ballotsToCount := []chaincode.Ballot{}
-
-	for i := range ballots {
-		if ballots[i].ElectionID == requestBody.ElectionID {
-			ballotsToCount = append(ballotsToCount, ballots[i])
-		}
-	}
 	if len(ballotsToCount) == 0 {
 		errorResponse := common.GenerateErrorResponse(http.StatusBadRequest, "no ballots to count")
 		return errorResponse, nil
@@ -54,9 +55,27 @@ func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 		return errorResponse, nil
 	}
 
-	// First publicKey is used for comparison against other public keys as a control measure
-	// Privat key will be used at the end for decypting the final count
-	publicKey, privateKey, err := common.DecodeKeys(ballotsToCount[0].Candidates[0].PublicKey, os.Getenv("PAILLIER_PRIVATE_KEY"))
+	// Ballots with packed counts are added once per ballot instead of once per candidate
+	if ballotsToCount[0].PackingBase != "" {
+		return countPackedBallots(startTime, ballotsToCount, requestBody.PartialDecryptions), nil
+	}
+
+	// Elections with a threshold key cannot be decrypted by this lambda alone.
+	// The trustees' partial decryptions of each encrypted count must be supplied instead
+	thresholdKeyBase64 := os.Getenv("PAILLIER_THRESHOLD_PUBLIC_KEY")
+	if thresholdKeyBase64 != "" {
+		results, err := countBallotsWithThresholdKey(ballotsToCount, thresholdKeyBase64, requestBody.PartialDecryptions)
+		if err != nil {
+			errorResponse := common.GenerateErrorResponse(http.StatusBadRequest, fmt.Sprintf("%v", err))
+			return errorResponse, nil
+		}
+
+		return generateResponse(startTime, ballotsToCount[0].Candidates[0].PublicKey, results, nil), nil
+	}
+
+	// First publicKey is used for counting. Ballots encrypted with other public keys fail to be counted
+	// Private key will be used at the end for decypting the final count
+	publicKey, privateKey, err := common.LoadKeys(ballotsToCount[0].Candidates[0].PublicKey)
 	if err != nil {
 		errorResponse := common.GenerateErrorResponse(http.StatusBadRequest, fmt.Sprintf("%v", err))
 		return errorResponse, nil
@@ -69,34 +88,163 @@ func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 		return errorResponse, nil
 	}
 
-	// Decrypt final count and prepare response
+	// Decrypt final count with a proof of correct decryption and prepare response
 	for i := range results {
-		results[i].NumVotes, err = paillier.Decrypt(publicKey, privateKey, results[i].NumVotes)
+		numVotes, proof, err := paillier.ProveDecryption(publicKey, privateKey, results[i].EncryptedVotes)
 		if err != nil {
 			errorResponse := common.GenerateErrorResponse(http.StatusBadRequest, fmt.Sprintf("error decrypting final count: %v", err))
 			return errorResponse, nil
 		}
+
+		proofBase64, err := paillier.Base64Encode(proof)
+		if err != nil {
+			errorResponse := common.GenerateErrorResponse(http.StatusBadRequest, fmt.Sprintf("error encoding decryption proof: %v", err))
+			return errorResponse, nil
+		}
+
+		results[i].NumVotes = numVotes
+		results[i].Proof = proofBase64
 	}
 
-	endTime := time.Now()
+	return generateResponse(startTime, ballotsToCount[0].Candidates[0].PublicKey, results, nil), nil
+}
 
-	duration := endTime.Sub(startTime)
+// Counts ballots that store the counts of all candidates in a single packed ciphertext.
+// The packed tally is decrypted once and unpacked into the count of each candidate.
+// With a threshold key, the trustees' partial decryptions of the packed tally must be keyed by PackedCountKey.
+func countPackedBallots(startTime time.Time, ballotsToCount []chaincode.Ballot, partialDecryptions map[string][]string) events.APIGatewayProxyResponse {
+	publicKeyBase64 := ballotsToCount[0].Candidates[0].PublicKey
 
-	minutes := int(duration.Minutes())
-	seconds := duration.Seconds() - float64(minutes)*60.0
+	thresholdKeyBase64 := os.Getenv("PAILLIER_THRESHOLD_PUBLIC_KEY")
+	if thresholdKeyBase64 != "" {
+		thresholdKey, err := common.DecodeThresholdKey(thresholdKeyBase64)
+		if err != nil {
+			return common.GenerateErrorResponse(http.StatusBadRequest, fmt.Sprintf("%v", err))
+		}
 
-	responseBody := LambdaResponseBody{
-		Duration: fmt.Sprintf("%d min %.4f sec", minutes, seconds),
-		Results:  results,
+		packed, packing, err := addPackedCounts(ballotsToCount, &thresholdKey.PublicKey)
+		if err != nil {
+			return common.GenerateErrorResponse(http.StatusBadRequest, fmt.Sprintf("%v", err))
+		}
+
+		results := unpackedCandidates(ballotsToCount[0])
+		if len(partialDecryptions) == 0 {
+			return generateResponse(startTime, publicKeyBase64, results, packed)
+		}
+
+		partials, err := common.DecodePartialDecryptions(partialDecryptions[PackedCountKey])
+		if err != nil {
+			return common.GenerateErrorResponse(http.StatusBadRequest, fmt.Sprintf("%v", err))
+		}
+
+		packed.NumVotes, err = paillier.CombinePartialDecryptions(thresholdKey, packed.EncryptedVotes, partials)
+		if err != nil {
+			return common.GenerateErrorResponse(http.StatusBadRequest, fmt.Sprintf("error decrypting final count: %v", err))
+		}
+		packed.PartialDecryptions = partialDecryptions[PackedCountKey]
+
+		if err = unpackCounts(packing, packed.NumVotes, results); err != nil {
+			return common.GenerateErrorResponse(http.StatusBadRequest, fmt.Sprintf("%v", err))
+		}
+
+		return generateResponse(startTime, publicKeyBase64, results, packed)
 	}
 
-	lambdaResponseBodyData, err := json.Marshal(responseBody)
+	publicKey, privateKey, err := common.LoadKeys(publicKeyBase64)
 	if err != nil {
-		errorResponse := common.GenerateErrorResponse(http.StatusBadRequest, fmt.Sprintf("error stringifying response body: %v", err))
-		return errorResponse, nil
+		return common.GenerateErrorResponse(http.StatusBadRequest, fmt.Sprintf("%v", err))
+	}
+
+	packed, packing, err := addPackedCounts(ballotsToCount, publicKey)
+	if err != nil {
+		return common.GenerateErrorResponse(http.StatusBadRequest, fmt.Sprintf("%v", err))
+	}
+
+	// Decrypt packed tally with a proof of correct decryption
+	numVotes, proof, err := paillier.ProveDecryption(publicKey, privateKey, packed.EncryptedVotes)
+	if err != nil {
+		return common.GenerateErrorResponse(http.StatusBadRequest, fmt.Sprintf("error decrypting final count: %v", err))
+	}
+
+	if packed.Proof, err = paillier.Base64Encode(proof); err != nil {
+		return common.GenerateErrorResponse(http.StatusBadRequest, fmt.Sprintf("error encoding decryption proof: %v", err))
+	}
+	packed.NumVotes = numVotes
+
+	results := unpackedCandidates(ballotsToCount[0])
+	if err = unpackCounts(packing, numVotes, results); err != nil {
+		return common.GenerateErrorResponse(http.StatusBadRequest, fmt.Sprintf("%v", err))
+	}
+
+	return generateResponse(startTime, publicKeyBase64, results, packed)
+}
+
+// Decrypts the tally computed on the ledger by ComputeTally.
+// With a threshold key, only the encrypted counts are returned until the trustees' partial decryptions are provided.
+func countTally(startTime time.Time, tally chaincode.Tally, partialDecryptions map[string][]string) events.APIGatewayProxyResponse {
+	results := []LambdaResponseCandidate{}
+	for _, count := range tally.Counts {
+		result := LambdaResponseCandidate{
+			CandidateID: count.CandidateID,
+			Name:        count.Name,
+		}
+
+		if tally.PackingBase == "" {
+			encryptedVotes, ok := new(big.Int).SetString(count.Count, 10)
+			if !ok {
+				return common.GenerateErrorResponse(http.StatusBadRequest, fmt.Sprintf("error parsing tally count for %s", count.CandidateID))
+			}
+
+			result.EncryptedVotes = encryptedVotes
+		}
+
+		results = append(results, result)
+	}
+
+	decrypt, err := tallyDecrypter(tally, partialDecryptions)
+	if err != nil {
+		return common.GenerateErrorResponse(http.StatusBadRequest, fmt.Sprintf("%v", err))
+	}
+
+	if tally.PackingBase == "" {
+		if decrypt == nil {
+			return generateResponse(startTime, tally.PublicKey, results, nil)
+		}
+
+		for i := range results {
+			if results[i].NumVotes, results[i].Proof, results[i].PartialDecryptions, err = decrypt(results[i].CandidateID, results[i].EncryptedVotes); err != nil {
+				return common.GenerateErrorResponse(http.StatusBadRequest, fmt.Sprintf("%v", err))
+			}
+		}
+
+		return generateResponse(startTime, tally.PublicKey, results, nil)
+	}
+
+	base, ok := new(big.Int).SetString(tally.PackingBase, 10)
+	if !ok {
+		return common.GenerateErrorResponse(http.StatusBadRequest, "error parsing packing base of tally")
+	}
+
+	encryptedVotes, ok := new(big.Int).SetString(tally.PackedCount, 10)
+	if !ok {
+		return common.GenerateErrorResponse(http.StatusBadRequest, "error parsing packed count of tally")
+	}
+
+	packed := &LambdaResponsePacked{PackingBase: base, EncryptedVotes: encryptedVotes}
+	if decrypt == nil {
+		return generateResponse(startTime, tally.PublicKey, results, packed)
+	}
+
+	if packed.NumVotes, packed.Proof, packed.PartialDecryptions, err = decrypt(PackedCountKey, packed.EncryptedVotes); err != nil {
+		return common.GenerateErrorResponse(http.StatusBadRequest, fmt.Sprintf("%v", err))
 	}
 
-	return common.GenerateSuccessResponse(string(lambdaResponseBodyData)), nil
+	packing := &paillier.Packing{Base: base, NumSlots: len(results)}
+	if err = unpackCounts(packing, packed.NumVotes, results); err != nil {
+		return common.GenerateErrorResponse(http.StatusBadRequest, fmt.Sprintf("%v", err))
+	}
+
+	return generateResponse(startTime, tally.PublicKey, results, packed)
 }
 
 func main() {
@@ -107,64 +255,303 @@ func main() {
 // Helper Methods
 // ======================================================================================
 
-func countBallots(ballotsToCount []chaincode.Ballot, publicKey *paillier.PublicKey) ([]LambdaResponseCandidate, error) {
-	// Create set of candidates to count in a map
-	// We use a map since access is faster to update: O(1)
-	candidateMap := map[string]LambdaResponseCandidate{}
+func generateResponse(startTime time.Time, publicKey string, results []LambdaResponseCandidate, packed *LambdaResponsePacked) events.APIGatewayProxyResponse {
+	endTime := time.Now()
 
-	// We take the first ballot's candidates
-	// This will be used as a control measure to ensure all ballots have at least one of these candidates
-	// The onus of ensuring ballots have all the candidates (and no more) is not within the scope of this lambda
-	zeroCount, err := paillier.Encrypt(publicKey, big.NewInt(0))
+	duration := endTime.Sub(startTime)
+
+	minutes := int(duration.Minutes())
+	seconds := duration.Seconds() - float64(minutes)*60.0
+
+	responseBody := LambdaResponseBody{
+		Duration:  fmt.Sprintf("%d min %.4f sec", minutes, seconds),
+		PublicKey: publicKey,
+		Results:   results,
+		Packed:    packed,
+	}
+
+	lambdaResponseBodyData, err := json.Marshal(responseBody)
+	if err != nil {
+		return common.GenerateErrorResponse(http.StatusBadRequest, fmt.Sprintf("error stringifying response body: %v", err))
+	}
+
+	return common.GenerateSuccessResponse(string(lambdaResponseBodyData))
+}
+
+func countBallots(ballotsToCount []chaincode.Ballot, publicKey *paillier.PublicKey) ([]LambdaResponseCandidate, error) {
+	accumulator, err := paillier.NewAccumulator(publicKey, numWorkers())
 	if err != nil {
 		return []LambdaResponseCandidate{}, err
 	}
 
+	// Ensure the accumulator's workers are stopped on every return
+	defer accumulator.Result()
+
+	// We take the first ballot's candidates
+	// All ballots must only have these candidates
+	// The onus of ensuring ballots have all the candidates is not within the scope of this lambda
+	results := []LambdaResponseCandidate{}
+	candidateIDs := map[string]bool{}
+
 	for i := range ballotsToCount[0].Candidates {
 		candidate := ballotsToCount[0].Candidates[i]
 
-		candidateMap[candidate.Asset.ID] = LambdaResponseCandidate{
+		results = append(results, LambdaResponseCandidate{
 			CandidateID: candidate.Asset.ID,
 			Name:        candidate.Name,
-			NumVotes:    zeroCount,
-		}
+		})
+		candidateIDs[candidate.Asset.ID] = true
 	}
 
-	// Go through all candidates in each ballot and add the count to the candidate in the map
-	// Unavoidable nested loop: O(nc) where n is number of ballots, c is number of candidates
+	// Each count is bound to the fingerprint of the candidate's own public key,
+	// such that adding counts encrypted with a different key fails.
+	// Fingerprints are cached as every ballot has the same set of public keys
+	fingerprints := map[string]string{}
+
+	// Go through all candidates in each ballot and add the count to the candidate's total
+	// The counts are multiplied by the accumulator's workers while the ballots are parsed
 	for i := range ballotsToCount {
 		ballot := ballotsToCount[i]
 
 		for j := range ballot.Candidates {
 			candidate := ballot.Candidates[j]
 
-			if c, found := candidateMap[candidate.Asset.ID]; found {
-				candidateCount, ok := new(big.Int).SetString(candidate.Count, 10)
-				if !ok {
-					return []LambdaResponseCandidate{}, fmt.Errorf("error parsing candidate count: %v", err)
-				}
+			if !candidateIDs[candidate.Asset.ID] {
+				return []LambdaResponseCandidate{}, fmt.Errorf("extra candidate found in ballot %s", ballot.Asset.ID)
+			}
 
-				// Replace candiadte in map
-				candidateMap[candidate.Asset.ID] = LambdaResponseCandidate{
-					CandidateID: candidate.Asset.ID,
-					Name:        candidate.Name,
-					NumVotes:    paillier.AddEncrypted(publicKey, c.NumVotes, candidateCount),
+			fingerprint, found := fingerprints[candidate.PublicKey]
+			if !found {
+				candidatePublicKey, err := paillier.Base64Decode[paillier.PublicKey](candidate.PublicKey)
+				if err != nil {
+					return []LambdaResponseCandidate{}, fmt.Errorf("error decoding public key for %s: %v", candidate.Asset.ID, err)
 				}
-			} else {
-				return []LambdaResponseCandidate{}, fmt.Errorf("extra candidate found in ballot %s", ballot.Asset.ID)
+
+				fingerprint = candidatePublicKey.Fingerprint()
+				fingerprints[candidate.PublicKey] = fingerprint
+			}
+
+			candidateCount, ok := new(big.Int).SetString(candidate.Count, 10)
+			if !ok {
+				return []LambdaResponseCandidate{}, fmt.Errorf("error parsing candidate count for %s", candidate.Asset.ID)
+			}
+
+			if err = accumulator.Add(candidate.Asset.ID, &paillier.Ciphertext{KeyID: fingerprint, Value: candidateCount}); err != nil {
+				return []LambdaResponseCandidate{}, fmt.Errorf("error counting ballot %s: %v", ballot.Asset.ID, err)
 			}
 		}
 	}
 
-	// Create final array with candidates from the map
-	results := []LambdaResponseCandidate{}
-	for _, c := range candidateMap {
-		results = append(results, c)
+	// Candidates without any counts have a total of 1, the encryption of 0 without randomness,
+	// so that the encrypted count is the same on every invocation. Trustees rely on this to submit their partial decryptions.
+	totals := accumulator.Result()
+	for i := range results {
+		results[i].EncryptedVotes = big.NewInt(1)
+		if total, found := totals[results[i].CandidateID]; found {
+			results[i].EncryptedVotes = total.Value
+		}
+	}
+
+	return results, nil
+}
+
+// Counts the ballots of an election that was encrypted with a threshold key.
+// If no partial decryptions are provided, only the encrypted counts are returned so that trustees can
+// compute their partial decryptions of each count. Otherwise, the partial decryptions are verified & combined,
+// and returned as the proof of the decryption.
+func countBallotsWithThresholdKey(ballotsToCount []chaincode.Ballot, thresholdKeyBase64 string, partialDecryptions map[string][]string) ([]LambdaResponseCandidate, error) {
+	thresholdKey, err := common.DecodeThresholdKey(thresholdKeyBase64)
+	if err != nil {
+		return []LambdaResponseCandidate{}, err
+	}
+
+	// Ballots that were not encrypted with the threshold key fail to be counted
+	results, err := countBallots(ballotsToCount, &thresholdKey.PublicKey)
+	if err != nil {
+		return []LambdaResponseCandidate{}, err
+	}
+
+	if len(partialDecryptions) == 0 {
+		return results, nil
+	}
+
+	for i := range results {
+		partials, err := common.DecodePartialDecryptions(partialDecryptions[results[i].CandidateID])
+		if err != nil {
+			return []LambdaResponseCandidate{}, err
+		}
+
+		results[i].NumVotes, err = paillier.CombinePartialDecryptions(thresholdKey, results[i].EncryptedVotes, partials)
+		if err != nil {
+			return []LambdaResponseCandidate{}, fmt.Errorf("error decrypting final count for %s: %v", results[i].CandidateID, err)
+		}
+		results[i].PartialDecryptions = partialDecryptions[results[i].CandidateID]
 	}
 
 	return results, nil
 }
 
+// Adds the packed counts of all ballots.
+// All ballots must have the same candidates in the same order & the same packing base as the first ballot.
+func addPackedCounts(ballotsToCount []chaincode.Ballot, publicKey *paillier.PublicKey) (*LambdaResponsePacked, *paillier.Packing, error) {
+	first := ballotsToCount[0]
+
+	base, ok := new(big.Int).SetString(first.PackingBase, 10)
+	if !ok {
+		return nil, nil, fmt.Errorf("error parsing packing base of ballot %s", first.Asset.ID)
+	}
+
+	packing := &paillier.Packing{Base: base, NumSlots: len(first.Candidates)}
+	if err := packing.Validate(publicKey); err != nil {
+		return nil, nil, err
+	}
+
+	accumulator, err := paillier.NewAccumulator(publicKey, numWorkers())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Ensure the accumulator's workers are stopped on every return
+	defer accumulator.Result()
+
+	fingerprint := publicKey.Fingerprint()
+
+	for i := range ballotsToCount {
+		ballot := ballotsToCount[i]
+
+		if ballot.PackingBase != first.PackingBase || len(ballot.Candidates) != len(first.Candidates) {
+			return nil, nil, fmt.Errorf("ballot %s is packed differently from ballot %s", ballot.Asset.ID, first.Asset.ID)
+		}
+
+		for j := range ballot.Candidates {
+			if ballot.Candidates[j].Asset.ID != first.Candidates[j].Asset.ID {
+				return nil, nil, fmt.Errorf("ballot %s has different candidates from ballot %s", ballot.Asset.ID, first.Asset.ID)
+			}
+
+			if ballot.Candidates[j].PublicKey != first.Candidates[0].PublicKey {
+				return nil, nil, fmt.Errorf("candidate %s in ballot %s has a different public key", ballot.Candidates[j].Asset.ID, ballot.Asset.ID)
+			}
+		}
+
+		count, ok := new(big.Int).SetString(ballot.PackedCount, 10)
+		if !ok {
+			return nil, nil, fmt.Errorf("error parsing packed count of ballot %s", ballot.Asset.ID)
+		}
+
+		if err = accumulator.Add(PackedCountKey, &paillier.Ciphertext{KeyID: fingerprint, Value: count}); err != nil {
+			return nil, nil, fmt.Errorf("error counting ballot %s: %v", ballot.Asset.ID, err)
+		}
+	}
+
+	// Counting starts from 1, the encryption of 0 without randomness, so that the encrypted count
+	// is the same on every invocation
+	total := big.NewInt(1)
+	if packedTotal, found := accumulator.Result()[PackedCountKey]; found {
+		total = packedTotal.Value
+	}
+
+	return &LambdaResponsePacked{PackingBase: base, EncryptedVotes: total}, packing, nil
+}
+
+// Returns a function that decrypts the encrypted count keyed by key in the request's partial decryptions.
+// With a private key, counts are decrypted with a base64 encoded proof of correct decryption.
+// With a threshold key, the trustees' partial decryptions are verified & combined instead, and returned as the proof.
+// nil is returned if no partial decryptions were provided.
+// The tally must be encrypted with the configured key, so that counts are never decrypted with the wrong key.
+func tallyDecrypter(tally chaincode.Tally, partialDecryptions map[string][]string) (func(key string, encryptedVotes *big.Int) (*big.Int, string, []string, error), error) {
+	tallyKey, err := paillier.Base64Decode[paillier.PublicKey](tally.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding public key of tally: %v", err)
+	}
+
+	thresholdKeyBase64 := os.Getenv("PAILLIER_THRESHOLD_PUBLIC_KEY")
+	if thresholdKeyBase64 != "" {
+		thresholdKey, err := common.DecodeThresholdKey(thresholdKeyBase64)
+		if err != nil {
+			return nil, err
+		}
+
+		if tallyKey.Fingerprint() != thresholdKey.PublicKey.Fingerprint() {
+			return nil, fmt.Errorf("tally of election %s is encrypted with public key %s but the threshold key is %s", tally.ElectionID, tallyKey.Fingerprint(), thresholdKey.PublicKey.Fingerprint())
+		}
+
+		if len(partialDecryptions) == 0 {
+			return nil, nil
+		}
+
+		return func(key string, encryptedVotes *big.Int) (*big.Int, string, []string, error) {
+			partials, err := common.DecodePartialDecryptions(partialDecryptions[key])
+			if err != nil {
+				return nil, "", nil, err
+			}
+
+			numVotes, err := paillier.CombinePartialDecryptions(thresholdKey, encryptedVotes, partials)
+			if err != nil {
+				return nil, "", nil, fmt.Errorf("error decrypting final count for %s: %v", key, err)
+			}
+
+			return numVotes, "", partialDecryptions[key], nil
+		}, nil
+	}
+
+	// The private key is checked against the tally's public key when it is loaded
+	publicKey, privateKey, err := common.LoadKeys(tally.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decrypt tally of election %s encrypted with public key %s: %v", tally.ElectionID, tallyKey.Fingerprint(), err)
+	}
+
+	return func(key string, encryptedVotes *big.Int) (*big.Int, string, []string, error) {
+		numVotes, proof, err := paillier.ProveDecryption(publicKey, privateKey, encryptedVotes)
+		if err != nil {
+			return nil, "", nil, fmt.Errorf("error decrypting final count for %s: %v", key, err)
+		}
+
+		proofBase64, err := paillier.Base64Encode(proof)
+		if err != nil {
+			return nil, "", nil, fmt.Errorf("error encoding decryption proof: %v", err)
+		}
+
+		return numVotes, proofBase64, nil, nil
+	}, nil
+}
+
+// Returns the number of goroutines used to add encrypted counts.
+// This is configured with TALLY_WORKERS and defaults to the number of CPUs.
+func numWorkers() int {
+	if workers, err := strconv.Atoi(os.Getenv("TALLY_WORKERS")); err == nil && workers > 0 {
+		return workers
+	}
+
+	return runtime.NumCPU()
+}
+
+// Creates a result for each candidate of a packed ballot, in slot order
+func unpackedCandidates(ballot chaincode.Ballot) []LambdaResponseCandidate {
+	results := []LambdaResponseCandidate{}
+	for _, candidate := range ballot.Candidates {
+		results = append(results, LambdaResponseCandidate{
+			CandidateID: candidate.Asset.ID,
+			Name:        candidate.Name,
+		})
+	}
+
+	return results
+}
+
+// Unpacks the decrypted packed tally into the NumVotes of each candidate
+func unpackCounts(packing *paillier.Packing, packedVotes *big.Int, results []LambdaResponseCandidate) error {
+	counts, err := packing.Decode(packedVotes)
+	if err != nil {
+		return fmt.Errorf("error unpacking final count: %v", err)
+	}
+
+	for i := range results {
+		results[i].NumVotes = counts[i]
+	}
+
+	return nil
+}
+
 // ======================================================================================
 // HTTP Types
 // ======================================================================================
@@ -172,15 +559,45 @@ func countBallots(ballotsToCount []chaincode.Ballot, publicKey *paillier.PublicK
 type LambdaRequestBody struct {
 	SignerID   string `json:"SignerID"`
 	ElectionID string `json:"ElectionID"`
+
+	// Base64 encoded partial decryptions of each candidate's encrypted count, keyed by CandidateID.
+	// Partial decryptions of a packed tally are keyed by PackedCountKey instead.
+	// Only used when counting with a threshold key.
+	PartialDecryptions map[string][]string `json:"PartialDecryptions,omitempty"`
 }
 
+// Key of the partial decryptions of a packed tally in LambdaRequestBody.PartialDecryptions
+const PackedCountKey = "Packed"
+
 type LambdaResponseBody struct {
-	Duration string                    `json:"Duration"`
-	Results  []LambdaResponseCandidate `json:"Results"`
+	Duration  string                    `json:"Duration"`
+	PublicKey string                    `json:"PublicKey"`
+	Results   []LambdaResponseCandidate `json:"Results"`
+	Packed    *LambdaResponsePacked     `json:"Packed,omitempty"`
 }
 
+// Auditors can verify NumVotes is the decryption of EncryptedVotes
+// with paillier.VerifyDecryption using PublicKey & Proof.
+// When counting with a threshold key, Proof is omitted & PartialDecryptions holds the base64 encoded partial decryptions
+// that were combined. Each can be verified with paillier.VerifyPartialDecryption using the threshold public key.
 type LambdaResponseCandidate struct {
-	CandidateID string   `json:"CandidateID"`
-	Name        string   `json:"Name"`
-	NumVotes    *big.Int `json:"NumVotes"`
+	CandidateID        string   `json:"CandidateID"`
+	Name               string   `json:"Name"`
+	EncryptedVotes     *big.Int `json:"EncryptedVotes"`
+	NumVotes           *big.Int `json:"NumVotes"`
+	Proof              string   `json:"Proof,omitempty"`
+	PartialDecryptions []string `json:"PartialDecryptions,omitempty"`
+}
+
+// Packed tally of ballots with packed counts.
+// EncryptedVotes & Proof of each candidate are omitted, since only the packed tally is decrypted.
+// Auditors can verify NumVotes is the decryption of EncryptedVotes with paillier.VerifyDecryption,
+// or paillier.VerifyPartialDecryption for each of PartialDecryptions when counting with a threshold key,
+// and unpack NumVotes into the count of each candidate with PackingBase.
+type LambdaResponsePacked struct {
+	PackingBase        *big.Int `json:"PackingBase"`
+	EncryptedVotes     *big.Int `json:"EncryptedVotes"`
+	NumVotes           *big.Int `json:"NumVotes"`
+	Proof              string   `json:"Proof,omitempty"`
+	PartialDecryptions []string `json:"PartialDecryptions,omitempty"`
 }
@@ -10,6 +10,8 @@ import (
 	"github.com/aws/aws-lambda-go/lambda"
 	"github.com/direnbharwani/evote-capstone/app/server/common"
 	chaincode "github.com/direnbharwani/evote-capstone/chaincode/src"
+	paillier "github.com/direnbharwani/evote-capstone/paillier"
+	scheme "github.com/direnbharwani/evote-capstone/scheme"
 	"github.com/google/uuid"
 )
 
@@ -22,6 +24,13 @@ func handler(request events.APIGatewayProxyRequest) (events.APIGatewayProxyRespo
 
 	// Create Election
 
+	// Candidates must be encrypted with the same public key as the election
+	publicKey, err := paillier.Base64Decode[paillier.PublicKey](os.Getenv("PAILLIER_PUBLIC_KEY"))
+	if err != nil {
+		errorResponse := common.GenerateErrorResponse(http.StatusBadRequest, fmt.Sprintf("error decoding public key: %v", err))
+		return errorResponse, nil
+	}
+
 	electionID, err := uuid.NewV7()
 	if err != nil {
 		errorResponse := common.GenerateErrorResponse(http.StatusBadRequest, fmt.Sprintf("error generating election id: %v", err))
@@ -29,10 +38,14 @@ func handler(request events.APIGatewayProxyRequest) (events.APIGatewayProxyRespo
 	}
 
 	newElection := chaincode.Election{
-		Asset:     chaincode.Asset{ID: "e-" + electionID.String()},
-		EndTime:   requestBody.EndTime,
-		Name:      requestBody.ElectionName,
-		StartTime: requestBody.StartTime,
+		Asset:                chaincode.Asset{ID: "e-" + electionID.String()},
+		EndTime:              requestBody.EndTime,
+		MaxVoters:            requestBody.MaxVoters,
+		Name:                 requestBody.ElectionName,
+		PackedCounts:         requestBody.PackedCounts,
+		PublicKeyFingerprint: publicKey.Fingerprint(),
+		Scheme:               scheme.PaillierName,
+		StartTime:            requestBody.StartTime,
 	}
 
 	if err = common.ChaincodeCreate("testVoter0", os.Getenv("KALEIDO_AUTH_TOKEN"), newElection); err != nil {
@@ -67,6 +80,12 @@ func handler(request events.APIGatewayProxyRequest) (events.APIGatewayProxyRespo
 		return errorResponse, nil
 	}
 
+	// Open the election for voting now that its candidates are fixed
+	if err = common.ChaincodeOpenElection("testVoter0", os.Getenv("KALEIDO_AUTH_TOKEN"), newElection.Asset.ID); err != nil {
+		errorResponse := common.GenerateErrorResponse(http.StatusBadRequest, fmt.Sprintf("%v", err))
+		return errorResponse, nil
+	}
+
 	// Return electionID
 	responseBody := LambdaResponseBody{newElection.Asset.ID}
 	lambdaResponseBodyData, err := json.Marshal(responseBody)
@@ -91,6 +110,11 @@ type LambdaRequestBody struct {
 	StartTime     string `json:"StartTime"`
 	EndTime       string `json:"EndTime"`
 	NumCandidates int    `json:"NumCandidates"`
+
+	// Optional. Ballots store a single packed count for all candidates if PackedCounts is set,
+	// which requires the maximum number of voters
+	MaxVoters    int64 `json:"MaxVoters,omitempty"`
+	PackedCounts bool  `json:"PackedCounts,omitempty"`
 }
 
 type LambdaResponseBody struct {
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"time"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
@@ -23,9 +24,15 @@ func handler(request events.APIGatewayProxyRequest) (events.APIGatewayProxyRespo
 		return errorResponse, nil
 	}
 
+	isActive, err := election.IsActive(time.Now())
+	if err != nil {
+		errorResponse := common.GenerateErrorResponse(http.StatusInternalServerError, fmt.Sprintf("%v", err))
+		return errorResponse, nil
+	}
+
 	lambdaResponseBody := LambdaResponseBody{
 		Election: election,
-		IsActive: election.IsActive(),
+		IsActive: isActive && election.CurrentStatus() == chaincode.StatusOpen,
 	}
 
 	lambdaResponseBodyData, err := json.Marshal(lambdaResponseBody)
@@ -46,5 +53,5 @@ func main() {
 
 type LambdaResponseBody struct {
 	Election chaincode.Election `json:"Election"`
-	IsActive bool               `json:"isActive`
+	IsActive bool               `json:"IsActive"`
 }
@@ -78,7 +78,7 @@ func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 
 	// Use private key in conjuction with Candidate's public key
 	// to check if candidate has been voted for on a Ballot
-	publicKey, privateKey, err := common.DecodeKeys(ballot.Candidates[0].PublicKey, os.Getenv("PAILLIER_PRIVATE_KEY"))
+	publicKey, privateKey, err := common.LoadKeys(ballot.Candidates[0].PublicKey)
 	if err != nil {
 		errorResponse := common.GenerateErrorResponse(http.StatusBadRequest, fmt.Sprintf("%v", err))
 		return errorResponse, nil
@@ -89,22 +89,37 @@ func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 		BallotID: ballot.Asset.ID,
 	}
 
+	// Packed ballots store the counts of all candidates in a single ciphertext
+	var packedCounts []*big.Int
+	if ballot.PackingBase != "" {
+		packedCounts, err = decryptPackedCounts(ballot, publicKey, privateKey)
+		if err != nil {
+			errorResponse := common.GenerateErrorResponse(http.StatusBadRequest, fmt.Sprintf("%v", err))
+			return errorResponse, nil
+		}
+	}
+
 	for i := range ballot.Candidates {
 		decryptedCandidate := LambdaResponseCandidate{
 			CandidateID: ballot.Candidates[i].Asset.ID,
 			Name:        ballot.Candidates[i].Name,
 		}
 
-		encryptedCount, ok := new(big.Int).SetString(ballot.Candidates[i].Count, 10)
-		if !ok {
-			errorResponse := common.GenerateErrorResponse(http.StatusBadRequest, fmt.Sprintf("%v", err))
-			return errorResponse, nil
-		}
-
-		count, err := paillier.Decrypt(publicKey, privateKey, encryptedCount)
-		if err != nil {
-			errorResponse := common.GenerateErrorResponse(http.StatusBadRequest, fmt.Sprintf("%v", err))
-			return errorResponse, nil
+		var count *big.Int
+		if packedCounts != nil {
+			count = packedCounts[i]
+		} else {
+			encryptedCount, ok := new(big.Int).SetString(ballot.Candidates[i].Count, 10)
+			if !ok {
+				errorResponse := common.GenerateErrorResponse(http.StatusBadRequest, fmt.Sprintf("error parsing count of candidate %s", ballot.Candidates[i].Asset.ID))
+				return errorResponse, nil
+			}
+
+			count, err = paillier.Decrypt(publicKey, privateKey, encryptedCount)
+			if err != nil {
+				errorResponse := common.GenerateErrorResponse(http.StatusBadRequest, fmt.Sprintf("%v", err))
+				return errorResponse, nil
+			}
 		}
 
 		if count.Cmp(big.NewInt(0)) == 0 {
@@ -129,6 +144,31 @@ func main() {
 	lambda.Start(Handler)
 }
 
+// =============================================================================
+// Helper Methods
+// =============================================================================
+
+// Decrypts the packed count of a ballot and unpacks it into the count of each candidate
+func decryptPackedCounts(ballot chaincode.Ballot, publicKey *paillier.PublicKey, privateKey *paillier.PrivateKey) ([]*big.Int, error) {
+	base, ok := new(big.Int).SetString(ballot.PackingBase, 10)
+	if !ok {
+		return nil, fmt.Errorf("error parsing packing base of ballot %s", ballot.Asset.ID)
+	}
+
+	encryptedCount, ok := new(big.Int).SetString(ballot.PackedCount, 10)
+	if !ok {
+		return nil, fmt.Errorf("error parsing packed count of ballot %s", ballot.Asset.ID)
+	}
+
+	packed, err := paillier.Decrypt(publicKey, privateKey, encryptedCount)
+	if err != nil {
+		return nil, err
+	}
+
+	packing := paillier.Packing{Base: base, NumSlots: len(ballot.Candidates)}
+	return packing.Decode(packed)
+}
+
 // =============================================================================
 // API Types
 // =============================================================================
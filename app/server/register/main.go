@@ -95,13 +95,15 @@ func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 	ballotID := "b-" + newBallotUUID.String()
 	fmt.Printf("ballotID: %s\n", ballotID)
 
+	// The chaincode identifies voters by their enrollment ID qualified by the MSP the identity was enrolled with
 	newBallot := chaincode.Ballot{
 		Asset:      chaincode.Asset{ID: ballotID},
 		ElectionID: requestBody.ElectionID,
-		VoterID:    voterID,
+		VoterID:    chaincode.MSPQualifiedID(os.Getenv("KALEIDO_MSP_ID"), voterID),
 	}
 
-	if err = common.ChaincodeCreate(voterID, os.Getenv("KALEIDO_AUTH_TOKEN"), newBallot); err != nil {
+	// Ballots are issued by the registrar, as voters are only allowed to cast votes
+	if err = common.ChaincodeCreate("testVoter0", os.Getenv("KALEIDO_AUTH_TOKEN"), newBallot); err != nil {
 		errorResponse := common.GenerateErrorResponse(http.StatusBadRequest, fmt.Sprintf("%v", err))
 		return errorResponse, nil
 	}
@@ -135,9 +137,13 @@ func registerIdentity(name string) (string, error) {
 
 	// Register
 
-	requestBody, err := json.Marshal(map[string]string{
+	// The role is added to the enrollment certificate, which the chaincode reads to allow the identity to vote
+	requestBody, err := json.Marshal(map[string]interface{}{
 		"name": name,
 		"type": "client",
+		"attributes": map[string]string{
+			chaincode.RoleAttribute: string(chaincode.RoleVoter),
+		},
 	})
 	if err != nil {
 		return "", fmt.Errorf("failed to prepare register identity request: %v", err)
@@ -179,8 +185,11 @@ func registerIdentity(name string) (string, error) {
 func enrollIdentity(name, secret string) error {
 	endpoint := fmt.Sprintf("%s/identities/%s/enroll", os.Getenv("KALEIDO_REST_API_ENDPOINT"), name)
 
-	requestBody, err := json.Marshal(map[string]string{
+	requestBody, err := json.Marshal(map[string]interface{}{
 		"secret": secret,
+		"attributes": map[string]bool{
+			chaincode.RoleAttribute: false,
+		},
 	})
 	if err != nil {
 		return fmt.Errorf("failed to prepare enroll identity request: %v", err)
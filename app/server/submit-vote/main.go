@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -10,6 +11,7 @@ import (
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
 	"github.com/direnbharwani/evote-capstone/app/server/common"
+	chaincode "github.com/direnbharwani/evote-capstone/chaincode/src"
 )
 
 func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
@@ -19,7 +21,20 @@ func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 		return errorResponse, nil
 	}
 
-	err := common.ChaincodeCastVote(requestBody.VoterID, os.Getenv("KALEIDO_AUTH_TOKEN"), requestBody.BallotID, requestBody.CandidateID)
+	ballot, err := common.ChaincodeQuery[chaincode.Ballot](requestBody.VoterID, os.Getenv("KALEIDO_AUTH_TOKEN"), requestBody.BallotID)
+	if err != nil {
+		errorResponse := common.GenerateErrorResponse(http.StatusBadRequest, fmt.Sprintf("Unable to cast vote: %v", err))
+		return errorResponse, nil
+	}
+
+	// The vote is encrypted before it is sent to the chaincode, such that CandidateID never appears on the ledger
+	encryptedCounters, proofs, err := ballot.EncryptVote(rand.Reader, requestBody.CandidateID)
+	if err != nil {
+		errorResponse := common.GenerateErrorResponse(http.StatusBadRequest, fmt.Sprintf("Unable to cast vote: %v", err))
+		return errorResponse, nil
+	}
+
+	err = common.ChaincodeCastEncryptedVote(requestBody.VoterID, os.Getenv("KALEIDO_AUTH_TOKEN"), requestBody.BallotID, encryptedCounters, proofs)
 	if err != nil {
 		errorResponse := common.GenerateErrorResponse(http.StatusBadRequest, fmt.Sprintf("Unable to cast vote: %v", err))
 		return errorResponse, nil
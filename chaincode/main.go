@@ -1,13 +1,16 @@
 package main
 
 import (
+	"os"
+
 	chaincode "github.com/direnbharwani/evote-capstone/chaincode/src"
 
 	"github.com/hyperledger/fabric-contract-api-go/contractapi"
 )
 
 func main() {
-	eVoteSmartContract := new(chaincode.SmartContract)
+	// Rich queries are only run if the chaincode is given the state database of its peer
+	eVoteSmartContract := &chaincode.SmartContract{StateDatabase: os.Getenv("CORE_LEDGER_STATE_STATEDATABASE")}
 	chaincode, err := contractapi.NewChaincode(eVoteSmartContract)
 	if err != nil {
 		panic(err.Error())
@@ -6,16 +6,31 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"strconv"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/hyperledger/fabric-chaincode-go/shim"
 	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+	"github.com/hyperledger/fabric-protos-go/peer"
 )
 
+// Transactions that change the world state or read the history of assets require the client to have a role,
+// which is read from the RoleAttribute of its certificate. Clients without an allowed role get an AccessDeniedError.
 type SmartContract struct {
 	contractapi.Contract
+
+	// State database of the peer, as configured by the peer's CORE_LEDGER_STATE_STATEDATABASE.
+	// Rich queries are only run with StateDatabaseCouchDB. Like the peer's, it defaults to LevelDB if unset.
+	StateDatabase string
 }
 
+// Values of SmartContract.StateDatabase
+const (
+	StateDatabaseCouchDB = "CouchDB"
+	StateDatabaseLevelDB = "goleveldb"
+)
+
 // Function to test if the chaincode has been successfully deployed
 func (s *SmartContract) LiveTest() string {
 	loc, err := time.LoadLocation("Asia/Singapore")
@@ -38,6 +53,146 @@ func (s *SmartContract) LiveTest() string {
 	return string(jsonData)
 }
 
+// =============================================================================
+// Keys
+// =============================================================================
+
+// Ballots & Candidates are keyed under their election, i.e. (Type, ElectionID, ID), so that the assets of an election
+// can be read without reading every asset of the type. Other assets are keyed by their ID alone, i.e. (Type, ID).
+// Assets keyed under an election are looked up by ID with the election index, keyed (Type~ElectionID, ID),
+// which holds the ID of the asset's election.
+
+// Suffix of the object type of the election index of an asset type
+const electionIndexSuffix = "~ElectionID"
+
+// Returns true if assets of type T are keyed under their election
+func keyedByElection[T ITYPES]() bool {
+	var emptyObject T
+
+	switch any(emptyObject).(type) {
+	case Ballot, Candidate:
+		return true
+	default:
+		return false
+	}
+}
+
+// Returns the ID of the election asset is keyed under, or an empty string if it is keyed by its ID alone
+func electionOf[T ITYPES](asset T) string {
+	switch a := any(asset).(type) {
+	case Ballot:
+		return a.ElectionID
+	case Candidate:
+		return a.ElectionID
+	default:
+		return ""
+	}
+}
+
+// Returns the composite key of the asset with key, keyed under electionID if assets of type T are keyed by election
+func assetKey[T ITYPES](ctx contractapi.TransactionContextInterface, electionID, key string) (string, error) {
+	var emptyObject T
+
+	attributes := []string{key}
+	if keyedByElection[T]() {
+		attributes = []string{electionID, key}
+	}
+
+	compositeKey, err := ctx.GetStub().CreateCompositeKey(emptyObject.Type(), attributes)
+	if err != nil {
+		return "", &CompositeKeyCreationError{err.Error(), key, emptyObject.Type()}
+	}
+
+	return compositeKey, nil
+}
+
+// Returns the composite key of the asset with key, reading its election from the election index if required
+func lookupAssetKey[T ITYPES](ctx contractapi.TransactionContextInterface, key string) (string, error) {
+	if !keyedByElection[T]() {
+		return assetKey[T](ctx, "", key)
+	}
+
+	indexKey, err := electionIndexKey[T](ctx, key)
+	if err != nil {
+		return "", err
+	}
+
+	electionID, err := ctx.GetStub().GetState(indexKey)
+	if err != nil {
+		return "", &WorldStateInteractionError{err.Error(), key}
+	}
+	if electionID == nil {
+		return "", &WorldStateReadFailureError{key}
+	}
+
+	return assetKey[T](ctx, string(electionID), key)
+}
+
+// Returns the key of the election index entry of the asset with key
+func electionIndexKey[T ITYPES](ctx contractapi.TransactionContextInterface, key string) (string, error) {
+	var emptyObject T
+
+	indexKey, err := ctx.GetStub().CreateCompositeKey(emptyObject.Type()+electionIndexSuffix, []string{key})
+	if err != nil {
+		return "", &CompositeKeyCreationError{err.Error(), key, emptyObject.Type()}
+	}
+
+	return indexKey, nil
+}
+
+// Object type of the number of ballots issued for an election, keyed (Type, ElectionID).
+// Ballots are counted with a single key rather than by reading the ballots of the election, as range reads are
+// re-executed at commit & would make concurrent CreateBallot transactions fail validation.
+// Deleted ballots remain counted, so that the number of issued ballots never decreases.
+const issuedBallotsObjectType = "IssuedBallots"
+
+// Returns the key of the number of ballots issued for the election with electionID
+func issuedBallotsKey(ctx contractapi.TransactionContextInterface, electionID string) (string, error) {
+	counterKey, err := ctx.GetStub().CreateCompositeKey(issuedBallotsObjectType, []string{electionID})
+	if err != nil {
+		return "", &CompositeKeyCreationError{err.Error(), electionID, issuedBallotsObjectType}
+	}
+
+	return counterKey, nil
+}
+
+// Returns the number of ballots issued for the election with electionID, which is 0 if none have been issued
+func queryIssuedBallots(ctx contractapi.TransactionContextInterface, electionID string) (int64, error) {
+	counterKey, err := issuedBallotsKey(ctx, electionID)
+	if err != nil {
+		return 0, err
+	}
+
+	counterData, err := ctx.GetStub().GetState(counterKey)
+	if err != nil {
+		return 0, &WorldStateInteractionError{err.Error(), counterKey}
+	}
+	if counterData == nil {
+		return 0, nil
+	}
+
+	issuedBallots, err := strconv.ParseInt(string(counterData), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("number of ballots issued for election %s is invalid! %v", electionID, err)
+	}
+
+	return issuedBallots, nil
+}
+
+// Stores the number of ballots issued for the election with electionID
+func putIssuedBallots(ctx contractapi.TransactionContextInterface, electionID string, issuedBallots int64) error {
+	counterKey, err := issuedBallotsKey(ctx, electionID)
+	if err != nil {
+		return err
+	}
+
+	if err = ctx.GetStub().PutState(counterKey, []byte(strconv.FormatInt(issuedBallots, 10))); err != nil {
+		return &WorldStateInteractionError{err.Error(), counterKey}
+	}
+
+	return nil
+}
+
 // =============================================================================
 // Creation
 // =============================================================================
@@ -45,7 +200,12 @@ func (s *SmartContract) LiveTest() string {
 // Creates a ballot as an asset on the blockchain
 // data must contain Asset.ID & ElectionID
 // No candidates are expected as they will be taken from the Election asset
+// Ballots can only be created while the election is Open, once its candidates can no longer be changed.
 func (s *SmartContract) CreateBallot(ctx contractapi.TransactionContextInterface, data string) error {
+	if err := requireRole(ctx, "CreateBallot", RoleRegistrar); err != nil {
+		return err
+	}
+
 	ballot, err := ParseJSON[Ballot](data)
 	if err != nil {
 		return err
@@ -56,6 +216,27 @@ func (s *SmartContract) CreateBallot(ctx contractapi.TransactionContextInterface
 		return err
 	}
 
+	if err = election.checkStatus("create ballots", StatusOpen); err != nil {
+		return err
+	}
+
+	if err = election.checkScheme("create ballot for election"); err != nil {
+		return err
+	}
+
+	// Packed counts overflow into the next candidate's slot once a candidate has more than MaxVoters votes,
+	// so no more than MaxVoters ballots can be issued
+	var issuedBallots int64
+	if election.MaxVoters > 0 {
+		if issuedBallots, err = queryIssuedBallots(ctx, election.Asset.ID); err != nil {
+			return err
+		}
+
+		if issuedBallots >= election.MaxVoters {
+			return fmt.Errorf("unable to create ballot for election %s! all %d ballots have been issued", election.Asset.ID, election.MaxVoters)
+		}
+	}
+
 	// Clear the ballot slice to ensure no duplicate candides
 	ballot.Candidates = ballot.Candidates[:0]
 
@@ -65,47 +246,120 @@ func (s *SmartContract) CreateBallot(ctx contractapi.TransactionContextInterface
 			return err
 		}
 
-		if err = candidate.Init(); err != nil {
+		if err = election.checkPublicKey(candidate); err != nil {
 			return err
 		}
+
 		ballot.Candidates = append(ballot.Candidates, candidate)
 	}
 
+	ballot.PackingBase = election.packingBase()
+
+	random, err := transactionRandomness(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err = ballot.Init(random); err != nil {
+		return err
+	}
+
 	// Default state must be false
 	ballot.Voted = false
 
-	return createAsset(ctx, ballot.Asset.ID, ballot)
+	if err = createAsset(ctx, ballot.Asset.ID, ballot); err != nil {
+		return err
+	}
+
+	if election.MaxVoters > 0 {
+		if err = putIssuedBallots(ctx, election.Asset.ID, issuedBallots+1); err != nil {
+			return err
+		}
+	}
+
+	return emitEvent(ctx, EventBallotIssued, BallotIssuedEvent{BallotID: ballot.Asset.ID, ElectionID: ballot.ElectionID})
 }
 
 // Creates a candidate as an asset on the blockchain
 // data must contain Asset.ID & ElectionID
+// Candidates can only be created while the election is Draft.
 func (s *SmartContract) CreateCandidate(ctx contractapi.TransactionContextInterface, data string) error {
+	if err := requireRole(ctx, "CreateCandidate", RoleElectionAdmin); err != nil {
+		return err
+	}
+
 	candidate, err := ParseJSON[Candidate](data)
 	if err != nil {
 		return err
 	}
 
+	if err = checkElectionStatus(ctx, candidate.ElectionID, "create candidates", StatusDraft); err != nil {
+		return err
+	}
+
+	random, err := transactionRandomness(ctx)
+	if err != nil {
+		return err
+	}
+
 	// Default state must be 0 count. Count will not change on candidate assets, only in ballots.
-	candidate.Init()
-	return createAsset(ctx, candidate.Asset.ID, candidate)
+	if err = candidate.Init(random); err != nil {
+		return err
+	}
+
+	if err = createAsset(ctx, candidate.Asset.ID, candidate); err != nil {
+		return err
+	}
+
+	return emitEvent(ctx, EventCandidateAdded, CandidateAddedEvent{
+		CandidateID: candidate.Asset.ID,
+		ElectionID:  candidate.ElectionID,
+		Name:        candidate.Name,
+	})
 }
 
 // Creates an election as an asset on the blockchain
 // data must contian Asset.ID, StartTime & EndTime.
-// StartTime must be before EndTime.
+// StartTime must be before EndTime. Times are stored as RFC 3339.
+// Elections are always created as Draft.
 func (s *SmartContract) CreateElection(ctx contractapi.TransactionContextInterface, data string) error {
+	if err := requireRole(ctx, "CreateElection", RoleElectionAdmin); err != nil {
+		return err
+	}
+
 	election, err := ParseJSON[Election](data)
 	if err != nil {
 		return err
 	}
 
-	return createAsset(ctx, election.Asset.ID, election)
+	election.Status = StatusDraft
+
+	if err = election.checkScheme("create election"); err != nil {
+		return err
+	}
+
+	if err = election.normaliseTimes(); err != nil {
+		return err
+	}
+
+	if err = createAsset(ctx, election.Asset.ID, election); err != nil {
+		return err
+	}
+
+	return emitEvent(ctx, EventElectionCreated, ElectionCreatedEvent{
+		ElectionID: election.Asset.ID,
+		EndTime:    election.EndTime,
+		Name:       election.Name,
+		StartTime:  election.StartTime,
+	})
 }
 
 func createAsset[T ITYPES](ctx contractapi.TransactionContextInterface, key string, createdAsset T) error {
-	compositeKey, err := ctx.GetStub().CreateCompositeKey(createdAsset.Type(), []string{key})
+	electionID := electionOf(createdAsset)
+
+	compositeKey, err := assetKey[T](ctx, electionID, key)
 	if err != nil {
-		return &CompositeKeyCreationError{err.Error(), key, createdAsset.Type()}
+		return err
 	}
 
 	assetState, err := ctx.GetStub().GetState(compositeKey)
@@ -116,6 +370,26 @@ func createAsset[T ITYPES](ctx contractapi.TransactionContextInterface, key stri
 		return fmt.Errorf("%s: %s already created", createdAsset.Type(), key)
 	}
 
+	// IDs of assets keyed under an election are unique across elections, as they are looked up by ID alone
+	if keyedByElection[T]() {
+		indexKey, err := electionIndexKey[T](ctx, key)
+		if err != nil {
+			return err
+		}
+
+		indexState, err := ctx.GetStub().GetState(indexKey)
+		if err != nil {
+			return &WorldStateInteractionError{err.Error(), key}
+		}
+		if indexState != nil {
+			return fmt.Errorf("%s: %s already created", createdAsset.Type(), key)
+		}
+
+		if err = ctx.GetStub().PutState(indexKey, []byte(electionID)); err != nil {
+			return &WorldStateInteractionError{err.Error(), key}
+		}
+	}
+
 	createdData, err := json.Marshal(createdAsset)
 	if err != nil {
 		return err
@@ -144,18 +418,98 @@ func (s *SmartContract) QueryElection(ctx contractapi.TransactionContextInterfac
 	return queryAsset[Election](ctx, key)
 }
 
+func (s *SmartContract) QueryTally(ctx contractapi.TransactionContextInterface, key string) (Tally, error) {
+	return queryAsset[Tally](ctx, key)
+}
+
+func (s *SmartContract) QueryBallotsByElection(ctx contractapi.TransactionContextInterface, electionID string) ([]Ballot, error) {
+	return queryAssetsByElection[Ballot](ctx, electionID)
+}
+
+func (s *SmartContract) QueryCandidatesByElection(ctx contractapi.TransactionContextInterface, electionID string) ([]Candidate, error) {
+	return queryAssetsByElection[Candidate](ctx, electionID)
+}
+
+// Paginated queries return at most pageSize results per page, starting from the bookmark of the previous page.
+// The bookmark of the first page is empty.
+
+func (s *SmartContract) QueryAllBallotsWithPagination(ctx contractapi.TransactionContextInterface, pageSize int32, bookmark string) (BallotPage, error) {
+	results, metadata, err := queryAssetsByPartialKeyWithPagination[Ballot](ctx, []string{}, pageSize, bookmark)
+	if err != nil {
+		return BallotPage{}, err
+	}
+
+	return BallotPage{Bookmark: metadata.Bookmark, Count: metadata.FetchedRecordsCount, Results: results}, nil
+}
+
+func (s *SmartContract) QueryAllCandidatesWithPagination(ctx contractapi.TransactionContextInterface, pageSize int32, bookmark string) (CandidatePage, error) {
+	results, metadata, err := queryAssetsByPartialKeyWithPagination[Candidate](ctx, []string{}, pageSize, bookmark)
+	if err != nil {
+		return CandidatePage{}, err
+	}
+
+	return CandidatePage{Bookmark: metadata.Bookmark, Count: metadata.FetchedRecordsCount, Results: results}, nil
+}
+
+func (s *SmartContract) QueryAllElectionsWithPagination(ctx contractapi.TransactionContextInterface, pageSize int32, bookmark string) (ElectionPage, error) {
+	results, metadata, err := queryAssetsByPartialKeyWithPagination[Election](ctx, []string{}, pageSize, bookmark)
+	if err != nil {
+		return ElectionPage{}, err
+	}
+
+	return ElectionPage{Bookmark: metadata.Bookmark, Count: metadata.FetchedRecordsCount, Results: results}, nil
+}
+
+func (s *SmartContract) QueryBallotsByElectionWithPagination(ctx contractapi.TransactionContextInterface, electionID string, pageSize int32, bookmark string) (BallotPage, error) {
+	results, metadata, err := queryAssetsByPartialKeyWithPagination[Ballot](ctx, []string{electionID}, pageSize, bookmark)
+	if err != nil {
+		return BallotPage{}, err
+	}
+
+	return BallotPage{Bookmark: metadata.Bookmark, Count: metadata.FetchedRecordsCount, Results: results}, nil
+}
+
+func (s *SmartContract) QueryCandidatesByElectionWithPagination(ctx contractapi.TransactionContextInterface, electionID string, pageSize int32, bookmark string) (CandidatePage, error) {
+	results, metadata, err := queryAssetsByPartialKeyWithPagination[Candidate](ctx, []string{electionID}, pageSize, bookmark)
+	if err != nil {
+		return CandidatePage{}, err
+	}
+
+	return CandidatePage{Bookmark: metadata.Bookmark, Count: metadata.FetchedRecordsCount, Results: results}, nil
+}
+
 func (s *SmartContract) QueryBallotHistory(ctx contractapi.TransactionContextInterface, key string) (map[string]Ballot, error) {
+	if err := requireRole(ctx, "QueryBallotHistory", RoleAuditor, RoleElectionAdmin); err != nil {
+		return nil, err
+	}
+
 	return queryAssetHistory[Ballot](ctx, key)
 }
 
 func (s *SmartContract) QueryCandidateHistory(ctx contractapi.TransactionContextInterface, key string) (map[string]Candidate, error) {
+	if err := requireRole(ctx, "QueryCandidateHistory", RoleAuditor, RoleElectionAdmin); err != nil {
+		return nil, err
+	}
+
 	return queryAssetHistory[Candidate](ctx, key)
 }
 
 func (s *SmartContract) QueryElectionHistory(ctx contractapi.TransactionContextInterface, key string) (map[string]Election, error) {
+	if err := requireRole(ctx, "QueryElectionHistory", RoleAuditor, RoleElectionAdmin); err != nil {
+		return nil, err
+	}
+
 	return queryAssetHistory[Election](ctx, key)
 }
 
+func (s *SmartContract) QueryTallyHistory(ctx contractapi.TransactionContextInterface, key string) (map[string]Tally, error) {
+	if err := requireRole(ctx, "QueryTallyHistory", RoleAuditor, RoleElectionAdmin); err != nil {
+		return nil, err
+	}
+
+	return queryAssetHistory[Tally](ctx, key)
+}
+
 func (s *SmartContract) QueryAllBallots(ctx contractapi.TransactionContextInterface) ([]Ballot, error) {
 	return queryAssetsByType[Ballot](ctx)
 }
@@ -172,9 +526,9 @@ func queryAsset[T ITYPES](ctx contractapi.TransactionContextInterface, key strin
 	var emptyObject T
 	var result T
 
-	compositeKey, err := ctx.GetStub().CreateCompositeKey(result.Type(), []string{key})
+	compositeKey, err := lookupAssetKey[T](ctx, key)
 	if err != nil {
-		return emptyObject, &CompositeKeyCreationError{err.Error(), key, result.Type()}
+		return emptyObject, err
 	}
 
 	assetState, err := ctx.GetStub().GetState(compositeKey)
@@ -195,9 +549,9 @@ func queryAsset[T ITYPES](ctx contractapi.TransactionContextInterface, key strin
 func queryAssetHistory[T ITYPES](ctx contractapi.TransactionContextInterface, key string) (map[string]T, error) {
 	var emptyObject T
 
-	compositeKey, err := ctx.GetStub().CreateCompositeKey(emptyObject.Type(), []string{key})
+	compositeKey, err := lookupAssetKey[T](ctx, key)
 	if err != nil {
-		return nil, &CompositeKeyCreationError{err.Error(), key, emptyObject.Type()}
+		return nil, err
 	}
 
 	assetHistory := make(map[string]T)
@@ -230,15 +584,56 @@ func queryAssetHistory[T ITYPES](ctx contractapi.TransactionContextInterface, ke
 }
 
 func queryAssetsByType[T ITYPES](ctx contractapi.TransactionContextInterface) ([]T, error) {
+	return queryAssetsByPartialKey[T](ctx, []string{})
+}
+
+// Returns the assets keyed under the election with electionID, without reading the assets of other elections
+func queryAssetsByElection[T ITYPES](ctx contractapi.TransactionContextInterface, electionID string) ([]T, error) {
+	if !keyedByElection[T]() {
+		var emptyObject T
+		return nil, fmt.Errorf("%s is not keyed by election", emptyObject.Type())
+	}
+
+	return queryAssetsByPartialKey[T](ctx, []string{electionID})
+}
+
+func queryAssetsByPartialKey[T ITYPES](ctx contractapi.TransactionContextInterface, attributes []string) ([]T, error) {
 	var emptyObject T
 
-	results := []T{}
-	resultIterator, err := ctx.GetStub().GetStateByPartialCompositeKey(emptyObject.Type(), []string{})
+	resultIterator, err := ctx.GetStub().GetStateByPartialCompositeKey(emptyObject.Type(), attributes)
 	if err != nil {
 		return nil, err
 	}
 	defer resultIterator.Close()
 
+	return readAssets[T](resultIterator)
+}
+
+// Returns a page of at most pageSize assets matching the partial key attributes, starting from bookmark,
+// with the metadata of the page that holds the bookmark of the next page
+func queryAssetsByPartialKeyWithPagination[T ITYPES](ctx contractapi.TransactionContextInterface, attributes []string, pageSize int32, bookmark string) ([]T, *peer.QueryResponseMetadata, error) {
+	var emptyObject T
+
+	if pageSize < 1 {
+		return nil, nil, fmt.Errorf("page size must be at least 1, got %d", pageSize)
+	}
+
+	resultIterator, metadata, err := ctx.GetStub().GetStateByPartialCompositeKeyWithPagination(emptyObject.Type(), attributes, pageSize, bookmark)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resultIterator.Close()
+
+	results, err := readAssets[T](resultIterator)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return results, metadata, nil
+}
+
+func readAssets[T ITYPES](resultIterator shim.StateQueryIteratorInterface) ([]T, error) {
+	results := []T{}
 	for resultIterator.HasNext() {
 		assetState, err := resultIterator.Next()
 		if err != nil {
@@ -257,67 +652,356 @@ func queryAssetsByType[T ITYPES](ctx contractapi.TransactionContextInterface) ([
 }
 
 // =============================================================================
-// Update
+// Rich Queries
 // =============================================================================
 
-// Updates a ballot with the specified updated state.
-// The ballot cannot be updated if the ballot has already been cast.
-func (s *SmartContract) UpdateBallot(ctx contractapi.TransactionContextInterface, updatedData string) error {
-	updatedState, err := ParseJSON[Ballot](updatedData)
-	if err != nil {
-		return err
-	}
+// Rich queries select assets by their fields using the indexes in META-INF/statedb/couchdb/indexes.
+// If the state database is not CouchDB, which is the only one that supports rich queries, they fall back to reading
+// the assets by key and filtering them, so a fallback page can hold fewer results than its Count.
 
-	currentState, err := queryAsset[Ballot](ctx, updatedState.Asset.ID)
-	if err != nil {
-		return err
-	}
+// Returns true if the state database supports rich queries
+func (s *SmartContract) richQueriesSupported() bool {
+	return s.StateDatabase == StateDatabaseCouchDB
+}
 
-	if currentState.Voted {
-		return fmt.Errorf("unable to update ballot %s that has already been voted", currentState.Asset.ID)
-	}
+func (s *SmartContract) QueryBallotsByElectionAndVoted(ctx contractapi.TransactionContextInterface, electionID string, voted bool) ([]Ballot, error) {
+	return queryAssetsByRichQuery(ctx, s.richQueriesSupported(), ballotsByElectionAndVoted(electionID, voted))
+}
 
-	return updateAsset(ctx, updatedState.Asset.ID, updatedState)
+func (s *SmartContract) QueryCandidatesByName(ctx contractapi.TransactionContextInterface, name string) ([]Candidate, error) {
+	return queryAssetsByRichQuery(ctx, s.richQueriesSupported(), candidatesByName(name))
 }
 
-func (s *SmartContract) UpdateCandidate(ctx contractapi.TransactionContextInterface, updatedData string) error {
-	updatedState, err := ParseJSON[Candidate](updatedData)
+// Returns the elections that are active at any time between start and end, which are RFC 3339 times
+func (s *SmartContract) QueryElectionsByTimeWindow(ctx contractapi.TransactionContextInterface, start string, end string) ([]Election, error) {
+	query, err := electionsByTimeWindow(start, end)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return updateAsset(ctx, updatedState.Asset.ID, updatedState)
+	return queryAssetsByRichQuery(ctx, s.richQueriesSupported(), query)
 }
 
-func (s *SmartContract) UpdateElection(ctx contractapi.TransactionContextInterface, updatedData string) error {
-	updatedState, err := ParseJSON[Election](updatedData)
+func (s *SmartContract) QueryBallotsByElectionAndVotedWithPagination(ctx contractapi.TransactionContextInterface, electionID string, voted bool, pageSize int32, bookmark string) (BallotPage, error) {
+	results, metadata, err := queryAssetsByRichQueryWithPagination(ctx, s.richQueriesSupported(), ballotsByElectionAndVoted(electionID, voted), pageSize, bookmark)
 	if err != nil {
-		return err
+		return BallotPage{}, err
 	}
 
-	return updateAsset(ctx, updatedState.Asset.ID, updatedState)
+	return BallotPage{Bookmark: metadata.Bookmark, Count: metadata.FetchedRecordsCount, Results: results}, nil
 }
 
-func updateAsset[T ITYPES](ctx contractapi.TransactionContextInterface, key string, updatedAsset T) error {
-	compositeKey, err := ctx.GetStub().CreateCompositeKey(updatedAsset.Type(), []string{key})
+func (s *SmartContract) QueryCandidatesByNameWithPagination(ctx contractapi.TransactionContextInterface, name string, pageSize int32, bookmark string) (CandidatePage, error) {
+	results, metadata, err := queryAssetsByRichQueryWithPagination(ctx, s.richQueriesSupported(), candidatesByName(name), pageSize, bookmark)
 	if err != nil {
-		return &CompositeKeyCreationError{err.Error(), key, updatedAsset.Type()}
+		return CandidatePage{}, err
 	}
 
-	currentState, err := ctx.GetStub().GetState(compositeKey)
+	return CandidatePage{Bookmark: metadata.Bookmark, Count: metadata.FetchedRecordsCount, Results: results}, nil
+}
+
+func (s *SmartContract) QueryElectionsByTimeWindowWithPagination(ctx contractapi.TransactionContextInterface, start string, end string, pageSize int32, bookmark string) (ElectionPage, error) {
+	query, err := electionsByTimeWindow(start, end)
 	if err != nil {
-		return &WorldStateInteractionError{err.Error(), key}
-	}
-	if currentState == nil {
-		return &WorldStateReadFailureError{key}
+		return ElectionPage{}, err
 	}
 
-	var currentAsset T
-	if err = json.Unmarshal(currentState, &currentAsset); err != nil {
-		return err
+	results, metadata, err := queryAssetsByRichQueryWithPagination(ctx, s.richQueriesSupported(), query, pageSize, bookmark)
+	if err != nil {
+		return ElectionPage{}, err
 	}
 
-	if currentAsset.IsEqual(updatedAsset) {
+	return ElectionPage{Bookmark: metadata.Bookmark, Count: metadata.FetchedRecordsCount, Results: results}, nil
+}
+
+// A rich query for assets of type T.
+// Selector is the CouchDB selector, which is answered with the index Index of the design document IndexDoc.
+// Attributes & Matches are the key-range fallback: the partial key of the assets to read, and a filter that
+// selects the same assets as Selector.
+type richQuery[T ITYPES] struct {
+	Attributes []string
+	Index      string
+	IndexDoc   string
+	Matches    func(T) bool
+	Selector   map[string]interface{}
+}
+
+func (q richQuery[T]) String() (string, error) {
+	query, err := json.Marshal(map[string]interface{}{
+		"selector":  q.Selector,
+		"use_index": []string{"_design/" + q.IndexDoc, q.Index},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return string(query), nil
+}
+
+// Only ballots have a Voted field, which tells them apart from the candidates & tallies of the election
+func ballotsByElectionAndVoted(electionID string, voted bool) richQuery[Ballot] {
+	return richQuery[Ballot]{
+		Attributes: []string{electionID},
+		Index:      "indexBallotsByElection",
+		IndexDoc:   "indexBallotsByElectionDoc",
+		Matches:    func(b Ballot) bool { return b.Voted == voted },
+		Selector:   map[string]interface{}{"ElectionID": electionID, "Voted": voted},
+	}
+}
+
+// Elections also have a Name, so candidates are told apart by their PublicKey
+func candidatesByName(name string) richQuery[Candidate] {
+	return richQuery[Candidate]{
+		Attributes: []string{},
+		Index:      "indexCandidatesByName",
+		IndexDoc:   "indexCandidatesByNameDoc",
+		Matches:    func(c Candidate) bool { return c.Name == name },
+		Selector:   map[string]interface{}{"Name": name, "PublicKey": map[string]interface{}{"$exists": true}},
+	}
+}
+
+// Elections are selected by comparing their times as strings, which only holds for times normalised to UTC.
+// Elections with legacy times are only matched by the key-range fallback until they are updated.
+func electionsByTimeWindow(start string, end string) (richQuery[Election], error) {
+	startTime, err := parseElectionTime(start)
+	if err != nil {
+		return richQuery[Election]{}, fmt.Errorf("start %w", err)
+	}
+
+	endTime, err := parseElectionTime(end)
+	if err != nil {
+		return richQuery[Election]{}, fmt.Errorf("end %w", err)
+	}
+
+	if endTime.Before(startTime) {
+		return richQuery[Election]{}, fmt.Errorf("end %s must be after start %s", end, start)
+	}
+
+	return richQuery[Election]{
+		Attributes: []string{},
+		Index:      "indexElectionsByTime",
+		IndexDoc:   "indexElectionsByTimeDoc",
+		Matches: func(e Election) bool {
+			electionStart, err := parseElectionTime(e.StartTime)
+			if err != nil {
+				return false
+			}
+
+			electionEnd, err := parseElectionTime(e.EndTime)
+			if err != nil {
+				return false
+			}
+
+			return !electionStart.After(endTime) && !electionEnd.Before(startTime)
+		},
+		Selector: map[string]interface{}{
+			"EndTime":   map[string]interface{}{"$gte": startTime.UTC().Format(time.RFC3339)},
+			"StartTime": map[string]interface{}{"$lte": endTime.UTC().Format(time.RFC3339)},
+		},
+	}, nil
+}
+
+// Runs the rich query if richQueries is set, otherwise the assets are read by key & filtered
+func queryAssetsByRichQuery[T ITYPES](ctx contractapi.TransactionContextInterface, richQueries bool, q richQuery[T]) ([]T, error) {
+	if !richQueries {
+		results, err := queryAssetsByPartialKey[T](ctx, q.Attributes)
+		if err != nil {
+			return nil, err
+		}
+
+		return filterAssets(results, q.Matches), nil
+	}
+
+	query, err := q.String()
+	if err != nil {
+		return nil, err
+	}
+
+	resultIterator, err := ctx.GetStub().GetQueryResult(query)
+	if err != nil {
+		return nil, err
+	}
+	defer resultIterator.Close()
+
+	return readAssets[T](resultIterator)
+}
+
+// Returns a page of at most pageSize assets selected by the rich query, starting from bookmark,
+// with the metadata of the page that holds the bookmark of the next page.
+// The rich query is only run if richQueries is set, otherwise the page is read by key & filtered.
+func queryAssetsByRichQueryWithPagination[T ITYPES](ctx contractapi.TransactionContextInterface, richQueries bool, q richQuery[T], pageSize int32, bookmark string) ([]T, *peer.QueryResponseMetadata, error) {
+	if pageSize < 1 {
+		return nil, nil, fmt.Errorf("page size must be at least 1, got %d", pageSize)
+	}
+
+	if !richQueries {
+		results, metadata, err := queryAssetsByPartialKeyWithPagination[T](ctx, q.Attributes, pageSize, bookmark)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return filterAssets(results, q.Matches), metadata, nil
+	}
+
+	query, err := q.String()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resultIterator, metadata, err := ctx.GetStub().GetQueryResultWithPagination(query, pageSize, bookmark)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resultIterator.Close()
+
+	results, err := readAssets[T](resultIterator)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return results, metadata, nil
+}
+
+func filterAssets[T ITYPES](assets []T, matches func(T) bool) []T {
+	results := []T{}
+	for _, asset := range assets {
+		if matches(asset) {
+			results = append(results, asset)
+		}
+	}
+
+	return results
+}
+
+// =============================================================================
+// Update
+// =============================================================================
+
+// Updates a ballot with the specified updated state.
+// The ballot cannot be updated if the ballot has already been cast or once the election is Closed.
+// Only the voter of the ballot can be changed, as its candidates, counts & proofs are only changed by casting a vote.
+func (s *SmartContract) UpdateBallot(ctx contractapi.TransactionContextInterface, updatedData string) error {
+	if err := requireRole(ctx, "UpdateBallot", RoleRegistrar); err != nil {
+		return err
+	}
+
+	updatedState, err := ParseJSON[Ballot](updatedData)
+	if err != nil {
+		return err
+	}
+
+	currentState, err := queryAsset[Ballot](ctx, updatedState.Asset.ID)
+	if err != nil {
+		return err
+	}
+
+	if updatedState.ElectionID != currentState.ElectionID {
+		return fmt.Errorf("unable to change the election of ballot %s", currentState.Asset.ID)
+	}
+
+	if err = checkElectionStatus(ctx, currentState.ElectionID, "update ballots", StatusDraft, StatusOpen); err != nil {
+		return err
+	}
+
+	if currentState.Voted {
+		return fmt.Errorf("unable to update ballot %s that has already been voted", currentState.Asset.ID)
+	}
+
+	if !updatedState.hasSameVotes(currentState) {
+		return fmt.Errorf("unable to change the candidates or counts of ballot %s! votes can only be cast with CastVote or CastEncryptedVote", currentState.Asset.ID)
+	}
+
+	return updateAsset(ctx, updatedState.Asset.ID, updatedState)
+}
+
+// Updates a candidate with the specified updated state.
+// Candidates can only be updated while the election is Draft.
+func (s *SmartContract) UpdateCandidate(ctx contractapi.TransactionContextInterface, updatedData string) error {
+	if err := requireRole(ctx, "UpdateCandidate", RoleElectionAdmin); err != nil {
+		return err
+	}
+
+	updatedState, err := ParseJSON[Candidate](updatedData)
+	if err != nil {
+		return err
+	}
+
+	currentState, err := queryAsset[Candidate](ctx, updatedState.Asset.ID)
+	if err != nil {
+		return err
+	}
+
+	if updatedState.ElectionID != currentState.ElectionID {
+		return fmt.Errorf("unable to change the election of candidate %s", currentState.Asset.ID)
+	}
+
+	if err = checkElectionStatus(ctx, currentState.ElectionID, "update candidates", StatusDraft); err != nil {
+		return err
+	}
+
+	return updateAsset(ctx, updatedState.Asset.ID, updatedState)
+}
+
+// Updates an election with the specified updated state.
+// Elections can only be updated while Draft. The status can only be changed with
+// OpenElection, CloseElection, PublishTally & ArchiveElection.
+func (s *SmartContract) UpdateElection(ctx contractapi.TransactionContextInterface, updatedData string) error {
+	if err := requireRole(ctx, "UpdateElection", RoleElectionAdmin); err != nil {
+		return err
+	}
+
+	updatedState, err := ParseJSON[Election](updatedData)
+	if err != nil {
+		return err
+	}
+
+	currentState, err := queryAsset[Election](ctx, updatedState.Asset.ID)
+	if err != nil {
+		return err
+	}
+
+	if err = currentState.checkStatus("update election", StatusDraft); err != nil {
+		return err
+	}
+
+	if updatedState.CurrentStatus() != currentState.CurrentStatus() {
+		return fmt.Errorf("unable to change the status of election %s! status can only be changed by its transitions", currentState.Asset.ID)
+	}
+	updatedState.Status = currentState.Status
+
+	if err = updatedState.checkScheme("update election"); err != nil {
+		return err
+	}
+
+	if err = updatedState.normaliseTimes(); err != nil {
+		return err
+	}
+
+	return updateAsset(ctx, updatedState.Asset.ID, updatedState)
+}
+
+// Assets keyed under an election are updated under the election of updatedAsset,
+// so updates that change the election of an asset fail to read its current state.
+func updateAsset[T ITYPES](ctx contractapi.TransactionContextInterface, key string, updatedAsset T) error {
+	compositeKey, err := assetKey[T](ctx, electionOf(updatedAsset), key)
+	if err != nil {
+		return err
+	}
+
+	currentState, err := ctx.GetStub().GetState(compositeKey)
+	if err != nil {
+		return &WorldStateInteractionError{err.Error(), key}
+	}
+	if currentState == nil {
+		return &WorldStateReadFailureError{key}
+	}
+
+	var currentAsset T
+	if err = json.Unmarshal(currentState, &currentAsset); err != nil {
+		return err
+	}
+
+	if currentAsset.IsEqual(updatedAsset) {
 		return &ObjectEqualityError{key, updatedAsset.Type()}
 	}
 
@@ -337,24 +1021,64 @@ func updateAsset[T ITYPES](ctx contractapi.TransactionContextInterface, key stri
 // Delete (only for testing)
 // =============================================================================
 
+// Elections can only be deleted while Draft or once Archived
 func (s *SmartContract) DeleteElection(ctx contractapi.TransactionContextInterface, key string) error {
+	if err := requireRole(ctx, "DeleteElection", RoleElectionAdmin); err != nil {
+		return err
+	}
+
+	if err := checkElectionStatus(ctx, key, "delete election", StatusDraft, StatusArchived); err != nil {
+		return err
+	}
+
 	return deleteAsset[Election](ctx, key)
 }
 
+// Candidates can only be deleted while the election is Draft
 func (s *SmartContract) DeleteCandidate(ctx contractapi.TransactionContextInterface, key string) error {
+	if err := requireRole(ctx, "DeleteCandidate", RoleElectionAdmin); err != nil {
+		return err
+	}
+
+	candidate, err := queryAsset[Candidate](ctx, key)
+	if err != nil {
+		return err
+	}
+
+	if err = checkElectionStatus(ctx, candidate.ElectionID, "delete candidates", StatusDraft); err != nil {
+		return err
+	}
+
 	return deleteAsset[Candidate](ctx, key)
 }
 
+// Ballots can only be deleted until the election is Closed, and only if they have not been voted,
+// as deleting a voted ballot would erase its vote from the tally
 func (s *SmartContract) DeleteBallot(ctx contractapi.TransactionContextInterface, key string) error {
+	if err := requireRole(ctx, "DeleteBallot", RoleRegistrar); err != nil {
+		return err
+	}
+
+	ballot, err := queryAsset[Ballot](ctx, key)
+	if err != nil {
+		return err
+	}
+
+	if err = checkElectionStatus(ctx, ballot.ElectionID, "delete ballots", StatusDraft, StatusOpen); err != nil {
+		return err
+	}
+
+	if ballot.Voted {
+		return fmt.Errorf("unable to delete ballot %s that has already been voted", ballot.Asset.ID)
+	}
+
 	return deleteAsset[Ballot](ctx, key)
 }
 
 func deleteAsset[T ITYPES](ctx contractapi.TransactionContextInterface, key string) error {
-	var emptyObject T
-
-	compositeKey, err := ctx.GetStub().CreateCompositeKey(emptyObject.Type(), []string{key})
+	compositeKey, err := lookupAssetKey[T](ctx, key)
 	if err != nil {
-		return &CompositeKeyCreationError{err.Error(), key, emptyObject.Type()}
+		return err
 	}
 
 	currentState, err := ctx.GetStub().GetState(compositeKey)
@@ -369,6 +1093,17 @@ func deleteAsset[T ITYPES](ctx contractapi.TransactionContextInterface, key stri
 		return err
 	}
 
+	if keyedByElection[T]() {
+		indexKey, err := electionIndexKey[T](ctx, key)
+		if err != nil {
+			return err
+		}
+
+		if err := ctx.GetStub().DelState(indexKey); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -377,44 +1112,123 @@ func deleteAsset[T ITYPES](ctx contractapi.TransactionContextInterface, key stri
 // =============================================================================
 
 // Casts a vote for a ballot.
-// This function will assert that the ballot has been assigned to voterID and has a matching candidate with candidateID.
+// The voter is the client invoking the transaction, identified by the name it was enrolled with.
+// This function will assert that the ballot has been assigned to the voter and has a matching candidate with candidateID.
 // This function will return an error if the vote has already been cast.
-func (s *SmartContract) CastVote(ctx contractapi.TransactionContextInterface, voterID string, ballotID string, candidateID string) error {
-	ballot, err := queryAsset[Ballot](ctx, ballotID)
+// candidateID is visible to every peer & stored in the block. Use CastEncryptedVote to keep the choice of the voter secret.
+func (s *SmartContract) CastVote(ctx contractapi.TransactionContextInterface, ballotID string, candidateID string) error {
+	if err := requireRole(ctx, "CastVote", RoleVoter); err != nil {
+		return err
+	}
+
+	ballot, err := queryBallotToVote(ctx, ballotID)
+	if err != nil {
+		return err
+	}
+
+	random, err := transactionRandomness(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err = ballot.Vote(random, candidateID); err != nil {
+		return err
+	}
+
+	if err = updateAsset(ctx, ballot.Asset.ID, ballot); err != nil {
+		return err
+	}
+
+	return emitEvent(ctx, EventVoteCast, VoteCastEvent{BallotID: ballot.Asset.ID})
+}
+
+// Casts a vote for a ballot with counts encrypted by the voter's client, such that the choice of the voter
+// never appears in the transaction. The arguments are produced with Ballot.EncryptVote.
+// The voter & ballot are checked as in CastVote, and the counts must be well-formed & bound to the ballot by their
+// proofs of knowledge to replace the ballot's counts.
+func (s *SmartContract) CastEncryptedVote(ctx contractapi.TransactionContextInterface, ballotID string, encryptedCounters []string, proofs []string) error {
+	if err := requireRole(ctx, "CastEncryptedVote", RoleVoter); err != nil {
+		return err
+	}
+
+	ballot, err := queryBallotToVote(ctx, ballotID)
 	if err != nil {
 		return err
 	}
 
+	if err = ballot.CastEncrypted(encryptedCounters, proofs); err != nil {
+		return err
+	}
+
+	if err = updateAsset(ctx, ballot.Asset.ID, ballot); err != nil {
+		return err
+	}
+
+	return emitEvent(ctx, EventVoteCast, VoteCastEvent{BallotID: ballot.Asset.ID})
+}
+
+// Returns the ballot with ballotID, ensuring it has been assigned to the client invoking the transaction
+// and its election is Open & active at the time of the transaction
+func queryBallotToVote(ctx contractapi.TransactionContextInterface, ballotID string) (Ballot, error) {
+	voterID, err := clientID(ctx)
+	if err != nil {
+		return Ballot{}, err
+	}
+
+	ballot, err := queryAsset[Ballot](ctx, ballotID)
+	if err != nil {
+		return Ballot{}, err
+	}
+
 	if ballot.VoterID != voterID {
 		errorMessage := fmt.Sprintf("voter %s is not assigned ballot %s!", voterID, ballotID)
-		return errors.New(errorMessage)
+		return Ballot{}, errors.New(errorMessage)
 	}
 
 	// Ensure election is active
 	election, err := queryAsset[Election](ctx, ballot.ElectionID)
 	if err != nil {
-		return err
+		return Ballot{}, err
 	}
-	if !election.IsActive() {
-		errorMessage := fmt.Sprintf("election %s is not active! vote cannot be cast", election.Asset.ID)
-		return errors.New(errorMessage)
+
+	if err = election.checkStatus("cast votes", StatusOpen); err != nil {
+		return Ballot{}, err
 	}
 
-	if err = ballot.Vote(candidateID); err != nil {
-		return err
+	now, err := transactionTime(ctx)
+	if err != nil {
+		return Ballot{}, err
 	}
 
-	return updateAsset(ctx, ballot.Asset.ID, ballot)
+	active, err := election.IsActive(now)
+	if err != nil {
+		return Ballot{}, err
+	}
+	if !active {
+		errorMessage := fmt.Sprintf("election %s is not active! vote cannot be cast", election.Asset.ID)
+		return Ballot{}, errors.New(errorMessage)
+	}
+
+	return ballot, nil
 }
 
 // Helper function to sync the election and candidates. Duplicates are aptly handled.
+// Candidates can only be synced while the election is Draft.
 func (s *SmartContract) SyncElectionAndCandidates(ctx contractapi.TransactionContextInterface, electionID string) error {
+	if err := requireRole(ctx, "SyncElectionAndCandidates", RoleElectionAdmin); err != nil {
+		return err
+	}
+
 	election, err := queryAsset[Election](ctx, electionID)
 	if err != nil {
 		return err
 	}
 
-	allCandidates, err := queryAssetsByType[Candidate](ctx)
+	if err = election.checkStatus("sync candidates", StatusDraft); err != nil {
+		return err
+	}
+
+	candidates, err := queryAssetsByElection[Candidate](ctx, electionID)
 	if err != nil {
 		return err
 	}
@@ -422,26 +1236,248 @@ func (s *SmartContract) SyncElectionAndCandidates(ctx contractapi.TransactionCon
 	// Update election with candidates assigned to it
 	// We clear the candidate slice in the election to prevent duplicates
 	election.Candidates = election.Candidates[:0]
-	for i := range allCandidates {
-		if allCandidates[i].ElectionID != electionID {
+	for i := range candidates {
+		election.Candidates = append(election.Candidates, candidates[i].Asset.ID)
+	}
+
+	if err = updateAsset(ctx, election.Asset.ID, election); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// =============================================================================
+// Election Lifecycle
+// =============================================================================
+
+// Opens a Draft election for voting. The candidates of the election can no longer be changed.
+func (s *SmartContract) OpenElection(ctx contractapi.TransactionContextInterface, electionID string) error {
+	if err := requireRole(ctx, "OpenElection", RoleElectionAdmin); err != nil {
+		return err
+	}
+
+	election, err := queryAsset[Election](ctx, electionID)
+	if err != nil {
+		return err
+	}
+
+	if len(election.Candidates) == 0 {
+		return fmt.Errorf("unable to open election %s without candidates", electionID)
+	}
+
+	if err = election.transition(StatusDraft); err != nil {
+		return err
+	}
+
+	return updateAsset(ctx, election.Asset.ID, election)
+}
+
+// Closes an Open election. Votes can no longer be cast and ballots can no longer be changed.
+func (s *SmartContract) CloseElection(ctx contractapi.TransactionContextInterface, electionID string) error {
+	if err := requireRole(ctx, "CloseElection", RoleElectionAdmin); err != nil {
+		return err
+	}
+
+	if err := transitionElection(ctx, electionID, StatusOpen); err != nil {
+		return err
+	}
+
+	return emitEvent(ctx, EventElectionClosed, ElectionClosedEvent{ElectionID: electionID})
+}
+
+// Computes the tally of a Closed election on the ledger, by homomorphically adding the counts of every voted ballot.
+// The tally can only be computed once, so that everyone decrypts the same counts.
+func (s *SmartContract) ComputeTally(ctx contractapi.TransactionContextInterface, electionID string) error {
+	if err := requireRole(ctx, "ComputeTally", RoleElectionAdmin); err != nil {
+		return err
+	}
+
+	election, err := queryAsset[Election](ctx, electionID)
+	if err != nil {
+		return err
+	}
+
+	if err = election.checkStatus("compute tally", StatusClosed); err != nil {
+		return err
+	}
+
+	candidates := []Candidate{}
+	for _, candidateID := range election.Candidates {
+		candidate, err := queryAsset[Candidate](ctx, candidateID)
+		if err != nil {
+			return err
+		}
+
+		candidates = append(candidates, candidate)
+	}
+
+	var tally Tally
+	if err = tally.Init(election, candidates); err != nil {
+		return err
+	}
+
+	ballots, err := queryAssetsByElection[Ballot](ctx, electionID)
+	if err != nil {
+		return err
+	}
+
+	for _, ballot := range ballots {
+		if !ballot.Voted {
 			continue
 		}
 
-		election.Candidates = append(election.Candidates, allCandidates[i].Asset.ID)
+		if err = tally.Add(ballot); err != nil {
+			return err
+		}
+	}
+
+	return createAsset(ctx, tally.Asset.ID, tally)
+}
+
+// Publishes the tally of a Closed election, which must have been computed with ComputeTally
+func (s *SmartContract) PublishTally(ctx contractapi.TransactionContextInterface, electionID string) error {
+	if err := requireRole(ctx, "PublishTally", RoleElectionAdmin); err != nil {
+		return err
+	}
+
+	election, err := queryAsset[Election](ctx, electionID)
+	if err != nil {
+		return err
+	}
+
+	if err = election.transition(StatusClosed); err != nil {
+		return err
+	}
+
+	tally, err := queryAsset[Tally](ctx, electionID)
+	if err != nil {
+		return fmt.Errorf("unable to publish tally of election %s before it is computed: %w", electionID, err)
 	}
 
 	if err = updateAsset(ctx, election.Asset.ID, election); err != nil {
 		return err
 	}
 
-	return nil
+	return emitEvent(ctx, EventTallyPublished, TallyPublishedEvent{ElectionID: electionID, NumBallots: tally.NumBallots})
+}
+
+// Archives a Tallied election
+func (s *SmartContract) ArchiveElection(ctx contractapi.TransactionContextInterface, electionID string) error {
+	if err := requireRole(ctx, "ArchiveElection", RoleElectionAdmin); err != nil {
+		return err
+	}
+
+	return transitionElection(ctx, electionID, StatusTallied)
+}
+
+// Moves an election from status from to the next status of its lifecycle
+func transitionElection(ctx contractapi.TransactionContextInterface, electionID string, from ElectionStatus) error {
+	election, err := queryAsset[Election](ctx, electionID)
+	if err != nil {
+		return err
+	}
+
+	if err = election.transition(from); err != nil {
+		return err
+	}
+
+	return updateAsset(ctx, election.Asset.ID, election)
+}
+
+// Ensures the election with electionID is in one of the allowed statuses for action
+func checkElectionStatus(ctx contractapi.TransactionContextInterface, electionID, action string, allowed ...ElectionStatus) error {
+	election, err := queryAsset[Election](ctx, electionID)
+	if err != nil {
+		return err
+	}
+
+	return election.checkStatus(action, allowed...)
+}
+
+// =============================================================================
+// Migration
+// =============================================================================
+
+// Re-keys Ballots & Candidates that are keyed by their ID alone under their election, adding them to the election index.
+// Returns the number of assets that were re-keyed. Assets already keyed under their election are left unchanged,
+// so the migration can be run again, e.g. if a previous run exceeded the limits of a transaction.
+func (s *SmartContract) MigrateAssetKeys(ctx contractapi.TransactionContextInterface) (int, error) {
+	if err := requireRole(ctx, "MigrateAssetKeys", RoleElectionAdmin); err != nil {
+		return 0, err
+	}
+
+	numBallots, err := migrateAssetKeys[Ballot](ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	numCandidates, err := migrateAssetKeys[Candidate](ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return numBallots + numCandidates, nil
+}
+
+func migrateAssetKeys[T ITYPES](ctx contractapi.TransactionContextInterface) (int, error) {
+	var emptyObject T
+
+	resultIterator, err := ctx.GetStub().GetStateByPartialCompositeKey(emptyObject.Type(), []string{})
+	if err != nil {
+		return 0, err
+	}
+	defer resultIterator.Close()
+
+	numMigrated := 0
+	for resultIterator.HasNext() {
+		assetState, err := resultIterator.Next()
+		if err != nil {
+			return 0, err
+		}
+
+		_, attributes, err := ctx.GetStub().SplitCompositeKey(assetState.Key)
+		if err != nil {
+			return 0, err
+		}
+
+		// Assets keyed under their election have the election's ID as an additional attribute
+		if len(attributes) != 1 {
+			continue
+		}
+
+		var asset T
+		if err = json.Unmarshal(assetState.Value, &asset); err != nil {
+			return 0, err
+		}
+
+		if err = ctx.GetStub().DelState(assetState.Key); err != nil {
+			return 0, &WorldStateInteractionError{err.Error(), attributes[0]}
+		}
+
+		if err = createAsset(ctx, attributes[0], asset); err != nil {
+			return 0, err
+		}
+
+		numMigrated++
+	}
+
+	return numMigrated, nil
 }
 
 // =============================================================================
 // Performance Testing
 // =============================================================================
 
+// Base64 encoded public key of the candidates of performance test elections, of MinPublicKeyBits in size.
+// The key is fixed so that every peer endorses the same candidates.
+const performanceTestPublicKey = "eyJOIjoyNTgzNTA0ODcyNzkwMTMxNzIxODI3MTMzNTkyODE2NjIwMzA3NjgxMzgzNDY0NDc4MzE5NDE1NDM1OTI2MzEzNzgwNjIxMjcxNjA3NjQ3Mjc4MTY1MTI1MDY0OTcwMTYwMjI4NDAxMDc5NjMzMzcyMTUwMjMwNzkzODE3MDY0MjU3Nzc5OTM4MDk2NDYzNzgwMTY5NjM3NjM4NDM2NTczODI5MjU1NTQxMzYzMTYzMDA3MDcwMDM3MDY4NTk5ODEzMDA2NzU1MjQ2MjgyMDc3MDgwMjkxNTU5NjQxNDA2Mjc1MDA3ODk0MTg0OTMxMDk5NTY5NjI0MTI1NzU4NjgxNjA0NDg4NjI5NjU1MTg5NjIxMzk2MDMxNjI2NTYwNDU1NTU4OTYyMDEwNjk1ODQzMjg5NzM5OTQ1Njg5MTEyODM5NjIyODI0NjQ4NTQ2MTM2Mzc0MTU2NTA1NDExNjIyODY0MzE0NDQxNDY3NzY5MDUxNzE4MzkzOTc4NzU5MzkwOTAzODQ4NzAwNDgyODgwNzAxOTA5MDQ5MDMwOTU5NTAwOTczNTU0OTk4MTgxNjkyODgzNjIwMjc3MjM4NjE0OTE5ODU5MzY3Njc4MjM1NzY3NDY3MTE2OTgxMjU5NzMzMDU2MTc3MTE4NzI2MzM1NzI5NjAxMjExMDg3OTY4MTcxMDU0NDU1NDM5Njg4MDU3Mjk5Nzk0ODM5ODk3Mjg1NjM5MjU2NTUyMDM1MzE2ODI4NTc4OTUzNzkwNDg5NDQzODg5MjEyNTIxODU0ODEyNDkzMjExNjkzOTg2NDc1NTA1NjI3MzMyMywiTlNxdWFyZSI6NjY3NDQ5NzQyNzczMDM1NDY5MDM0ODYwNDg5NTUxMDcxNjM1NTAwMDM1NTI3MDk0OTM2NzAxMDY1MjM3MzU2NDcwNzAyNzMyMTYyNzczNzYwNzcxMTkzNjUwNDczMDQ2NTg1ODIyNTQzMTM1MTcxMTgzMTAwMTgxOTY0MDU3NTMzMjkyNTI4NTE0NTM0NTcxOTAyNTEyMDYwNTM0MzcxMjExNzE5NDkzNTUyNjkxOTQzNjU5Njg1NzIxNzAyNzE1OTIwMzE1MDI5MDk1MDgxNDc0MTU2NzcxNzg4OTkwOTkxMDc2NTQwNTQwMzQ2OTE5OTc3NDAxMjA4MjU5MTQzMjIyNjgwNjM0MzUxNDU0NjIxMTUyMDk5Njk1ODUzMTMxMTgwNTkzNTEzNzc0MTk3MzE3NjkyNDUyNDQ3Njk2MTAyOTkwMDYwOTM2Njc5MDE2NzY5MjQ0MDE0NjgyOTA4MDA2OTQxMjY1MzgxMjY2MTQ1ODczMjI0NzgyMTk0NTAxODc4Mjg3NjM5MTI5NzUxOTM4MzU2MDQ2OTkyMzAyMjIwMTc5MTE4NTg3ODIwMTg0MjIyMDQ2MDkzOTQwMjc4MjgzOTEzNjQ4NjU0NjgyNzc3NTUzMDA4NTQ3Mjc1NDE3Nzg5NTQ3NjU4MzUyNDQxODc5NjY0ODY4NjAyNTI2MjIzMTgzMzU3NTg3OTA4OTY3NTYzNzE4MTM3MjI1ODI5OTYyNTQ1MzU5NDgwMDgyMTM3NDEzOTM4NTE2NjAxNjAzMjA3NzMyMzIwNzU5NDk5NTExODEzMjE3NzU3MTkwNjY1ODU4NTgxODQzNjQ3NjQwNDQ4NTA2MDE2MzYxMDY4MDYxNTExMjAwNTUyNjA3OTU3MjI4NDUxNTQyMjE0MTk3MDA0MDY1MjAwNTI4OTUxMDU1NDkwODU4MjE3NzE1MDQzODI5MTgzNjMwOTM5NDE3OTgwNjIyODI3NDY1NDY4MTU3MDA3MjA2MzYxNDQ3NjM3MDgyODg3NjY2Njc2NDIxODI1MzQ3MTgzNDU2Nzk0NzAwMzY3ODYzMTcwOTkzNzQyOTUwNTM2NDc2NzgzMTE1NTI5NzI3NDYxNTgzNTczNTc1NzUwODkyMDg2MTA5NzQ3OTQwMDU5NzUxMjY2MzI3OTUyMDk3Mjg3MjkyNjQxMDM1NDc5NjUxMzYyNzQ5NTgwMzY1ODkwMzI4NzQ2NDE5Mzc4NDY1NTI0MDc2NTY5NjYzMTAwMjA5MzY4OTEyNjMwMjIzNjg2OTI2NTM3ODAyNjIxOTIxNDU5MjEwMjE3MDkxNDYyNDg0Nzk0NTQzMDY3OTgxNTU5NjQwOTAxNjI0MDMyNjM3NzY2NjYzNjA4NzU5ODE3NjQyMDE1MTg0MzMzNzg3MDQ1NzY2NDIxNDQ1NjIxNDc3ODg2MDc2NjY2NzAyMzUzMTE2NTkxOTA1NzEyMTM4ODE5NjM4NjAxNjgwOTkxNTI3NTYzMDY2NzU4MDYzODcyNTkzNDA2OTc1ODA5NjkxNTE2MTQzOTgzMzg4MTQ5ODEzNDg2MzY0NjU3MjA2NzQ3ODU5MDU3ODQ4OTk5MTcxMDc4Njc2NjgxNjgzNzM5NjE2NDMwNDcxNTg2ODcyMDI4NDE2ODgxNDYyMzI5LCJHIjoyNTgzNTA0ODcyNzkwMTMxNzIxODI3MTMzNTkyODE2NjIwMzA3NjgxMzgzNDY0NDc4MzE5NDE1NDM1OTI2MzEzNzgwNjIxMjcxNjA3NjQ3Mjc4MTY1MTI1MDY0OTcwMTYwMjI4NDAxMDc5NjMzMzcyMTUwMjMwNzkzODE3MDY0MjU3Nzc5OTM4MDk2NDYzNzgwMTY5NjM3NjM4NDM2NTczODI5MjU1NTQxMzYzMTYzMDA3MDcwMDM3MDY4NTk5ODEzMDA2NzU1MjQ2MjgyMDc3MDgwMjkxNTU5NjQxNDA2Mjc1MDA3ODk0MTg0OTMxMDk5NTY5NjI0MTI1NzU4NjgxNjA0NDg4NjI5NjU1MTg5NjIxMzk2MDMxNjI2NTYwNDU1NTU4OTYyMDEwNjk1ODQzMjg5NzM5OTQ1Njg5MTEyODM5NjIyODI0NjQ4NTQ2MTM2Mzc0MTU2NTA1NDExNjIyODY0MzE0NDQxNDY3NzY5MDUxNzE4MzkzOTc4NzU5MzkwOTAzODQ4NzAwNDgyODgwNzAxOTA5MDQ5MDMwOTU5NTAwOTczNTU0OTk4MTgxNjkyODgzNjIwMjc3MjM4NjE0OTE5ODU5MzY3Njc4MjM1NzY3NDY3MTE2OTgxMjU5NzMzMDU2MTc3MTE4NzI2MzM1NzI5NjAxMjExMDg3OTY4MTcxMDU0NDU1NDM5Njg4MDU3Mjk5Nzk0ODM5ODk3Mjg1NjM5MjU2NTUyMDM1MzE2ODI4NTc4OTUzNzkwNDg5NDQzODg5MjEyNTIxODU0ODEyNDkzMjExNjkzOTg2NDc1NTA1NjI3MzMyNCwiTGVuZ3RoIjoxMDI0fQ=="
+
+// Ballots are assigned to voterID, which must be qualified by the MSP ID of the voter, see MSPQualifiedID
 func (s *SmartContract) SetupPerformanceTestElection(ctx contractapi.TransactionContextInterface, voterID string, numBallots int, numCandidates int) (string, error) {
+	if err := requireRole(ctx, "SetupPerformanceTestElection", RoleElectionAdmin); err != nil {
+		return "", err
+	}
 
 	// Setup Election with no candidates
 	electionID, err := uuid.NewV7()
@@ -451,8 +1487,14 @@ func (s *SmartContract) SetupPerformanceTestElection(ctx contractapi.Transaction
 
 	election := Election{
 		Asset:     Asset{ID: "e-" + electionID.String()},
-		EndTime:   "2024-03-24 23:59:59",
-		StartTime: "2024-03-23 00:00:00",
+		EndTime:   "2024-03-24T23:59:59Z",
+		StartTime: "2024-03-23T00:00:00Z",
+		Status:    StatusOpen,
+	}
+
+	random, err := transactionRandomness(ctx)
+	if err != nil {
+		return "", err
 	}
 
 	// Create candidates
@@ -468,13 +1510,13 @@ func (s *SmartContract) SetupPerformanceTestElection(ctx contractapi.Transaction
 			Asset:      Asset{ID: "c-" + candidateID.String()},
 			ElectionID: election.Asset.ID,
 			Name:       fmt.Sprintf("performanceTestCandidate%d", i),
-			PublicKey:  "eyJOIjoxMDA5ODc4ODk1NjcwMjc2NjY5OTQzMzg2Njk3MjUzNDA1ODMxNjE2NDY3MjMwODgyNDg5MjIyMzI4ODc4NjI3NDE4Nzg2MDExODQ3MDcsIk5TcXVhcmUiOjEwMTk4NTUzODM5MjAyMTc1NTEwMjI2ODQ5NTUwMzAzMzM3ODAzNTY2MTUwNjE4NTE0NDY0NTM1NTk1NDEwNjE0MzQ3NzIxNjU5NjgyNjY2ODE1NTExMTIzMzgyNzcxMzczMjE5NzA4OTYzMzQwOTcxODY2NzYxNjM4NzE4NDA5MDA2MDE5NTQ4NjQ1NTQyNTQzOTMwNjc1ODQ5LCJHIjoxMDA5ODc4ODk1NjcwMjc2NjY5OTQzMzg2Njk3MjUzNDA1ODMxNjE2NDY3MjMwODgyNDg5MjIyMzI4ODc4NjI3NDE4Nzg2MDExODQ3MDgsIkxlbmd0aCI6MTI4fQ==",
+			PublicKey:  performanceTestPublicKey,
 		}
 
 		election.Candidates = append(election.Candidates, candidate.Asset.ID)
 		candidates = append(candidates, candidate)
 
-		if err = candidate.Init(); err != nil {
+		if err = candidate.Init(random); err != nil {
 			return "", err
 		}
 		if err = createAsset(ctx, candidate.Asset.ID, candidate); err != nil {
@@ -502,10 +1544,8 @@ func (s *SmartContract) SetupPerformanceTestElection(ctx contractapi.Transaction
 			VoterID:    voterID,
 		}
 
-		for i := range ballot.Candidates {
-			if err = ballot.Candidates[i].Init(); err != nil {
-				return "", err
-			}
+		if err = ballot.Init(random); err != nil {
+			return "", err
 		}
 
 		if err = createAsset(ctx, ballot.Asset.ID, ballot); err != nil {
@@ -517,52 +1557,45 @@ func (s *SmartContract) SetupPerformanceTestElection(ctx contractapi.Transaction
 }
 
 func (s *SmartContract) CastVotesForPerformanceTestElection(ctx contractapi.TransactionContextInterface, electionID string) error {
+	if err := requireRole(ctx, "CastVotesForPerformanceTestElection", RoleElectionAdmin); err != nil {
+		return err
+	}
+
 	election, err := queryAsset[Election](ctx, electionID)
 	if err != nil {
 		return err
 	}
 
-	var emptyBallot Ballot
+	random, err := transactionRandomness(ctx)
+	if err != nil {
+		return err
+	}
 
 	// Get all ballots from this election
-	resultIterator, err := ctx.GetStub().GetStateByPartialCompositeKey(emptyBallot.Type(), []string{})
+	ballots, err := queryAssetsByElection[Ballot](ctx, electionID)
 	if err != nil {
 		return err
 	}
-	defer resultIterator.Close()
 
-	for resultIterator.HasNext() {
-		assetState, err := resultIterator.Next()
+	for _, ballot := range ballots {
+		// RNG the candidateID
+		numCandidates := int64(len(election.Candidates))
+
+		index, err := rand.Int(random, big.NewInt(numCandidates))
 		if err != nil {
 			return err
 		}
 
-		var ballot Ballot
-		if err = json.Unmarshal(assetState.Value, &ballot); err != nil {
+		candidateID := election.Candidates[index.Int64()]
+
+		// Cast Vote
+		if err = ballot.Vote(random, candidateID); err != nil {
 			return err
 		}
 
-		// Ensure it matches this election
-		if ballot.ElectionID == electionID {
-			// RNG the candidateID
-			numCandidates := int64(len(election.Candidates))
-
-			index, err := rand.Int(rand.Reader, big.NewInt(numCandidates))
-			if err != nil {
-				return err
-			}
-
-			candidateID := election.Candidates[index.Int64()]
-
-			// Cast Vote
-			if err = ballot.Vote(candidateID); err != nil {
-				return err
-			}
-
-			// Update ballot
-			if err = updateAsset(ctx, ballot.Asset.ID, ballot); err != nil {
-				return err
-			}
+		// Update ballot
+		if err = updateAsset(ctx, ballot.Asset.ID, ballot); err != nil {
+			return err
 		}
 	}
 
@@ -570,32 +1603,15 @@ func (s *SmartContract) CastVotesForPerformanceTestElection(ctx contractapi.Tran
 }
 
 func (s *SmartContract) CleanUpPerformanceTestElection(ctx contractapi.TransactionContextInterface, electionID string) error {
-	// Get all ballot IDs
-	var emptyBallot Ballot
+	if err := requireRole(ctx, "CleanUpPerformanceTestElection", RoleElectionAdmin); err != nil {
+		return err
+	}
 
 	// Get all ballots from this election
-	ballotIDsToDelete := []string{}
-	resultIterator, err := ctx.GetStub().GetStateByPartialCompositeKey(emptyBallot.Type(), []string{})
+	ballots, err := queryAssetsByElection[Ballot](ctx, electionID)
 	if err != nil {
 		return err
 	}
-	defer resultIterator.Close()
-
-	for resultIterator.HasNext() {
-		assetState, err := resultIterator.Next()
-		if err != nil {
-			return err
-		}
-
-		var ballot Ballot
-		if err = json.Unmarshal(assetState.Value, &ballot); err != nil {
-			return err
-		}
-
-		if ballot.ElectionID == electionID {
-			ballotIDsToDelete = append(ballotIDsToDelete, ballot.Asset.ID)
-		}
-	}
 
 	// Get candidate IDs
 	election, err := queryAsset[Election](ctx, electionID)
@@ -604,8 +1620,8 @@ func (s *SmartContract) CleanUpPerformanceTestElection(ctx contractapi.Transacti
 	}
 
 	// Delete ballots
-	for _, id := range ballotIDsToDelete {
-		if err = deleteAsset[Ballot](ctx, id); err != nil {
+	for _, ballot := range ballots {
+		if err = deleteAsset[Ballot](ctx, ballot.Asset.ID); err != nil {
 			return err
 		}
 	}
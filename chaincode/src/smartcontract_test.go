@@ -1,16 +1,29 @@
 package chaincode_test
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
+	"math/big"
+	"strings"
 	"testing"
+	"time"
 
 	chaincode "github.com/direnbharwani/evote-capstone/chaincode/src"
 	mocks "github.com/direnbharwani/evote-capstone/chaincode/src/mocks"
+	paillier "github.com/direnbharwani/evote-capstone/paillier"
+	scheme "github.com/direnbharwani/evote-capstone/scheme"
 
+	"github.com/hyperledger/fabric-protos-go/ledger/queryresult"
+	"github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 // =============================================================================
@@ -26,18 +39,26 @@ func TestCreateBallot(t *testing.T) {
 		mockCtx := &mocks.TransactionContextInterface{}
 
 		mockCtx.On("GetStub").Return(mockStub)
+		mockCtx.On("GetClientIdentity").Return(MockClientIdentity("mockRegistrar", chaincode.RoleRegistrar))
 
 		mockBallot, mockBallotData := MockBallot()
-		mockElection, mockElectionData := MockElection()
+		mockElection, _ := MockElection()
+		mockElection.Status = chaincode.StatusOpen
+		mockElectionData, err := json.Marshal(mockElection)
+		if err != nil {
+			t.Error(err)
+		}
 
-		mockStub.On("CreateCompositeKey", mockBallot.Type(), []string{mockBallot.Asset.ID}).Return(mockBallot.Asset.ID, nil)
+		mockStub.On("GetTransient").Return(map[string][]byte{}, nil)
+		MockNewAssetKeys(mockStub, mockBallot.Type(), mockBallot.ElectionID, mockBallot.Asset.ID)
 		mockStub.On("CreateCompositeKey", mockElection.Type(), []string{mockElection.Asset.ID}).Return(mockElection.Asset.ID, nil)
 		mockStub.On("GetState", mockElection.Asset.ID).Return(mockElectionData, nil)
 		mockStub.On("GetState", mockBallot.Asset.ID).Return(nil, nil)
 		mockStub.On("PutState", mockBallot.Asset.ID, mock.AnythingOfType("[]uint8")).Return(nil, nil)
+		mockStub.On("SetEvent", string(chaincode.EventBallotIssued), mock.AnythingOfType("[]uint8")).Return(nil)
 
 		// Test
-		err := smartContract.CreateBallot(mockCtx, string(mockBallotData))
+		err = smartContract.CreateBallot(mockCtx, string(mockBallotData))
 		require.NoError(t, err)
 	})
 
@@ -47,11 +68,18 @@ func TestCreateBallot(t *testing.T) {
 		mockCtx := &mocks.TransactionContextInterface{}
 
 		mockCtx.On("GetStub").Return(mockStub)
+		mockCtx.On("GetClientIdentity").Return(MockClientIdentity("mockRegistrar", chaincode.RoleRegistrar))
 
 		mockBallot, mockBallotData := MockBallot()
-		mockElection, mockElectionData := MockElection()
+		mockElection, _ := MockElection()
+		mockElection.Status = chaincode.StatusOpen
+		mockElectionData, err := json.Marshal(mockElection)
+		if err != nil {
+			t.Error(err)
+		}
 
-		mockStub.On("CreateCompositeKey", mockBallot.Type(), []string{mockBallot.Asset.ID}).Return(mockBallot.Asset.ID, nil)
+		mockStub.On("GetTransient").Return(map[string][]byte{}, nil)
+		MockNewAssetKeys(mockStub, mockBallot.Type(), mockBallot.ElectionID, mockBallot.Asset.ID)
 		mockStub.On("CreateCompositeKey", mockElection.Type(), []string{mockElection.Asset.ID}).Return(mockElection.Asset.ID, nil)
 		mockStub.On("GetState", mockElection.Asset.ID).Return(mockElectionData, nil)
 		mockStub.On("GetState", mockBallot.Asset.ID).Return(mockBallotData, nil)
@@ -59,6 +87,27 @@ func TestCreateBallot(t *testing.T) {
 		// Test
 		expectedError := fmt.Sprintf("%s: %s already created", mockBallot.Type(), mockBallot.Asset.ID)
 
+		err = smartContract.CreateBallot(mockCtx, string(mockBallotData))
+		require.EqualError(t, err, expectedError)
+	})
+
+	t.Run("fail to create ballot before election is open", func(t *testing.T) {
+		// Mocks
+		mockStub := &mocks.ChaincodeStubInterface{}
+		mockCtx := &mocks.TransactionContextInterface{}
+
+		mockCtx.On("GetStub").Return(mockStub)
+		mockCtx.On("GetClientIdentity").Return(MockClientIdentity("mockRegistrar", chaincode.RoleRegistrar))
+
+		_, mockBallotData := MockBallot()
+		mockElection, mockElectionData := MockElection()
+
+		mockStub.On("CreateCompositeKey", mockElection.Type(), []string{mockElection.Asset.ID}).Return(mockElection.Asset.ID, nil)
+		mockStub.On("GetState", mockElection.Asset.ID).Return(mockElectionData, nil)
+
+		// Test
+		expectedError := fmt.Sprintf("election %s is %s! unable to create ballots", mockElection.Asset.ID, chaincode.StatusDraft)
+
 		err := smartContract.CreateBallot(mockCtx, string(mockBallotData))
 		require.EqualError(t, err, expectedError)
 	})
@@ -69,6 +118,7 @@ func TestCreateBallot(t *testing.T) {
 		mockCtx := &mocks.TransactionContextInterface{}
 
 		mockCtx.On("GetStub").Return(mockStub)
+		mockCtx.On("GetClientIdentity").Return(MockClientIdentity("mockRegistrar", chaincode.RoleRegistrar))
 
 		// Modify ballot for fail case
 		mockBallot, _ := MockBallot()
@@ -91,6 +141,7 @@ func TestCreateBallot(t *testing.T) {
 		mockCtx := &mocks.TransactionContextInterface{}
 
 		mockCtx.On("GetStub").Return(mockStub)
+		mockCtx.On("GetClientIdentity").Return(MockClientIdentity("mockRegistrar", chaincode.RoleRegistrar))
 
 		_, mockBallotData := MockBallot()
 		mockElection, _ := MockElection()
@@ -104,6 +155,70 @@ func TestCreateBallot(t *testing.T) {
 		err := smartContract.CreateBallot(mockCtx, string(mockBallotData))
 		require.EqualError(t, err, expectedError)
 	})
+
+	t.Run("successfully count ballots issued for election with MaxVoters", func(t *testing.T) {
+		// Mocks
+		mockStub := &mocks.ChaincodeStubInterface{}
+		mockCtx := &mocks.TransactionContextInterface{}
+
+		mockCtx.On("GetStub").Return(mockStub)
+		mockCtx.On("GetClientIdentity").Return(MockClientIdentity("mockRegistrar", chaincode.RoleRegistrar))
+
+		mockBallot, mockBallotData := MockBallot()
+		mockElection, _ := MockElection()
+		mockElection.Status = chaincode.StatusOpen
+		mockElection.MaxVoters = 2
+		mockElectionData, err := json.Marshal(mockElection)
+		if err != nil {
+			t.Error(err)
+		}
+
+		mockStub.On("GetTransient").Return(map[string][]byte{}, nil)
+		MockNewAssetKeys(mockStub, mockBallot.Type(), mockBallot.ElectionID, mockBallot.Asset.ID)
+		mockStub.On("CreateCompositeKey", mockElection.Type(), []string{mockElection.Asset.ID}).Return(mockElection.Asset.ID, nil)
+		mockStub.On("CreateCompositeKey", "IssuedBallots", []string{mockElection.Asset.ID}).Return("issued-"+mockElection.Asset.ID, nil)
+		mockStub.On("GetState", mockElection.Asset.ID).Return(mockElectionData, nil)
+		mockStub.On("GetState", "issued-"+mockElection.Asset.ID).Return([]byte("1"), nil)
+		mockStub.On("GetState", mockBallot.Asset.ID).Return(nil, nil)
+		mockStub.On("PutState", mockBallot.Asset.ID, mock.AnythingOfType("[]uint8")).Return(nil, nil)
+		mockStub.On("PutState", "issued-"+mockElection.Asset.ID, []byte("2")).Return(nil)
+		mockStub.On("SetEvent", string(chaincode.EventBallotIssued), mock.AnythingOfType("[]uint8")).Return(nil)
+
+		// Test
+		err = smartContract.CreateBallot(mockCtx, string(mockBallotData))
+		require.NoError(t, err)
+		mockStub.AssertCalled(t, "PutState", "issued-"+mockElection.Asset.ID, []byte("2"))
+		mockStub.AssertNotCalled(t, "GetStateByPartialCompositeKey", mock.Anything, mock.Anything)
+	})
+
+	t.Run("fail to create more ballots than MaxVoters", func(t *testing.T) {
+		// Mocks
+		mockStub := &mocks.ChaincodeStubInterface{}
+		mockCtx := &mocks.TransactionContextInterface{}
+
+		mockCtx.On("GetStub").Return(mockStub)
+		mockCtx.On("GetClientIdentity").Return(MockClientIdentity("mockRegistrar", chaincode.RoleRegistrar))
+
+		_, mockBallotData := MockBallot()
+		mockElection, _ := MockElection()
+		mockElection.Status = chaincode.StatusOpen
+		mockElection.PackedCounts = true
+		mockElection.MaxVoters = 1
+		mockElectionData, err := json.Marshal(mockElection)
+		if err != nil {
+			t.Error(err)
+		}
+
+		mockStub.On("CreateCompositeKey", mockElection.Type(), []string{mockElection.Asset.ID}).Return(mockElection.Asset.ID, nil)
+		mockStub.On("CreateCompositeKey", "IssuedBallots", []string{mockElection.Asset.ID}).Return("issued-"+mockElection.Asset.ID, nil)
+		mockStub.On("GetState", mockElection.Asset.ID).Return(mockElectionData, nil)
+		mockStub.On("GetState", "issued-"+mockElection.Asset.ID).Return([]byte("1"), nil)
+
+		// Test
+		err = smartContract.CreateBallot(mockCtx, string(mockBallotData))
+		require.EqualError(t, err, "unable to create ballot for election e-0! all 1 ballots have been issued")
+		mockStub.AssertNotCalled(t, "PutState", mock.Anything, mock.Anything)
+	})
 }
 
 func TestCreateCandidate(t *testing.T) {
@@ -115,29 +230,72 @@ func TestCreateCandidate(t *testing.T) {
 		mockCtx := &mocks.TransactionContextInterface{}
 
 		mockCtx.On("GetStub").Return(mockStub)
+		mockCtx.On("GetClientIdentity").Return(MockClientIdentity("mockAdmin", chaincode.RoleElectionAdmin))
 
 		mockCandidate, mockCandidateData := MockCandidate()
+		mockElection, mockElectionData := MockElection()
 
-		mockStub.On("CreateCompositeKey", mockCandidate.Type(), []string{mockCandidate.Asset.ID}).Return(mockCandidate.Asset.ID, nil)
+		mockStub.On("GetTransient").Return(map[string][]byte{}, nil)
+		MockNewAssetKeys(mockStub, mockCandidate.Type(), mockCandidate.ElectionID, mockCandidate.Asset.ID)
 		mockStub.On("GetState", mockCandidate.Asset.ID).Return(nil, nil)
+		mockStub.On("CreateCompositeKey", mockElection.Type(), []string{mockElection.Asset.ID}).Return(mockElection.Asset.ID, nil)
+		mockStub.On("GetState", mockElection.Asset.ID).Return(mockElectionData, nil)
 		mockStub.On("PutState", mockCandidate.Asset.ID, mock.AnythingOfType("[]uint8")).Return(nil, nil)
+		mockStub.On("SetEvent", string(chaincode.EventCandidateAdded), mock.AnythingOfType("[]uint8")).Return(nil)
 
 		// Test
 		err := smartContract.CreateCandidate(mockCtx, string(mockCandidateData))
 		require.NoError(t, err)
 	})
 
+	t.Run("successfully create identical candidates with the same randomness seed", func(t *testing.T) {
+		mockCandidate, mockCandidateData := MockCandidate()
+		mockElection, mockElectionData := MockElection()
+		seed := []byte("0123456789abcdef0123456789abcdef")
+
+		// Each endorsing peer runs the transaction with the same ID & transient data
+		endorse := func() []byte {
+			mockStub := &mocks.ChaincodeStubInterface{}
+			mockCtx := &mocks.TransactionContextInterface{}
+
+			mockCtx.On("GetStub").Return(mockStub)
+			mockCtx.On("GetClientIdentity").Return(MockClientIdentity("mockAdmin", chaincode.RoleElectionAdmin))
+
+			mockStub.On("GetTransient").Return(map[string][]byte{chaincode.RandomnessSeedKey: seed}, nil)
+			mockStub.On("GetTxID").Return("mockTxID")
+			MockNewAssetKeys(mockStub, mockCandidate.Type(), mockCandidate.ElectionID, mockCandidate.Asset.ID)
+			mockStub.On("GetState", mockCandidate.Asset.ID).Return(nil, nil)
+			mockStub.On("CreateCompositeKey", mockElection.Type(), []string{mockElection.Asset.ID}).Return(mockElection.Asset.ID, nil)
+			mockStub.On("GetState", mockElection.Asset.ID).Return(mockElectionData, nil)
+			mockStub.On("PutState", mockCandidate.Asset.ID, mock.AnythingOfType("[]uint8")).Return(nil, nil)
+			mockStub.On("SetEvent", string(chaincode.EventCandidateAdded), mock.AnythingOfType("[]uint8")).Return(nil)
+
+			err := smartContract.CreateCandidate(mockCtx, string(mockCandidateData))
+			require.NoError(t, err)
+
+			// The stored candidate is the last state written before the event
+			return mockStub.Calls[len(mockStub.Calls)-2].Arguments.Get(1).([]byte)
+		}
+
+		require.Equal(t, endorse(), endorse())
+	})
+
 	t.Run("fail to create existing candidate", func(t *testing.T) {
 		// Mocks
 		mockStub := &mocks.ChaincodeStubInterface{}
 		mockCtx := &mocks.TransactionContextInterface{}
 
 		mockCtx.On("GetStub").Return(mockStub)
+		mockCtx.On("GetClientIdentity").Return(MockClientIdentity("mockAdmin", chaincode.RoleElectionAdmin))
 
 		mockCandidate, mockCandidateData := MockCandidate()
+		mockElection, mockElectionData := MockElection()
 
-		mockStub.On("CreateCompositeKey", mockCandidate.Type(), []string{mockCandidate.Asset.ID}).Return(mockCandidate.Asset.ID, nil)
+		mockStub.On("GetTransient").Return(map[string][]byte{}, nil)
+		MockNewAssetKeys(mockStub, mockCandidate.Type(), mockCandidate.ElectionID, mockCandidate.Asset.ID)
 		mockStub.On("GetState", mockCandidate.Asset.ID).Return(mockCandidateData, nil)
+		mockStub.On("CreateCompositeKey", mockElection.Type(), []string{mockElection.Asset.ID}).Return(mockElection.Asset.ID, nil)
+		mockStub.On("GetState", mockElection.Asset.ID).Return(mockElectionData, nil)
 
 		// Test
 		expectedError := fmt.Sprintf("%s: %s already created", mockCandidate.Type(), mockCandidate.Asset.ID)
@@ -152,6 +310,7 @@ func TestCreateCandidate(t *testing.T) {
 		mockCtx := &mocks.TransactionContextInterface{}
 
 		mockCtx.On("GetStub").Return(mockStub)
+		mockCtx.On("GetClientIdentity").Return(MockClientIdentity("mockAdmin", chaincode.RoleElectionAdmin))
 
 		// Modify candidate for fail case
 		mockCandidate, _ := MockCandidate()
@@ -167,6 +326,40 @@ func TestCreateCandidate(t *testing.T) {
 		err = smartContract.CreateCandidate(mockCtx, string(mockCandidateData))
 		require.EqualError(t, err, expectedError)
 	})
+
+	t.Run("fail to create candidate with a public key that is too short", func(t *testing.T) {
+		// Mocks
+		mockStub := &mocks.ChaincodeStubInterface{}
+		mockCtx := &mocks.TransactionContextInterface{}
+
+		mockCtx.On("GetStub").Return(mockStub)
+		mockCtx.On("GetClientIdentity").Return(MockClientIdentity("mockAdmin", chaincode.RoleElectionAdmin))
+
+		// Modify candidate for fail case
+		publicKey, _, err := paillier.GenerateKeys(512)
+		if err != nil {
+			t.Error(err)
+		}
+
+		mockCandidate, _ := MockCandidate()
+		mockCandidate.Count = ""
+		mockCandidate.Proof = ""
+		mockCandidate.PublicKeyFingerprint = ""
+		if mockCandidate.PublicKey, err = paillier.Base64Encode(publicKey); err != nil {
+			t.Error(err)
+		}
+		mockCandidateData, err := json.Marshal(mockCandidate)
+		if err != nil {
+			t.Error(err)
+		}
+
+		// Test
+		expectedError := fmt.Sprintf("%s is invalid! invalid Public Key: public key N must be at least %d bits but is %d bits", mockCandidate.Type(), chaincode.MinPublicKeyBits, publicKey.N.BitLen())
+
+		err = smartContract.CreateCandidate(mockCtx, string(mockCandidateData))
+		require.EqualError(t, err, expectedError)
+		mockStub.AssertNotCalled(t, "PutState", mock.Anything, mock.Anything)
+	})
 }
 
 func TestCreateElection(t *testing.T) {
@@ -178,24 +371,63 @@ func TestCreateElection(t *testing.T) {
 		mockCtx := &mocks.TransactionContextInterface{}
 
 		mockCtx.On("GetStub").Return(mockStub)
+		mockCtx.On("GetClientIdentity").Return(MockClientIdentity("mockAdmin", chaincode.RoleElectionAdmin))
 
 		mockElection, mockElectionData := MockElection()
 
 		mockStub.On("CreateCompositeKey", mockElection.Type(), []string{mockElection.Asset.ID}).Return(mockElection.Asset.ID, nil)
 		mockStub.On("GetState", mockElection.Asset.ID).Return(nil, nil)
 		mockStub.On("PutState", mockElection.Asset.ID, mock.AnythingOfType("[]uint8")).Return(nil, nil)
+		mockStub.On("SetEvent", string(chaincode.EventElectionCreated), mock.AnythingOfType("[]uint8")).Return(nil)
 
 		// Test
 		err := smartContract.CreateElection(mockCtx, string(mockElectionData))
 		require.NoError(t, err)
 	})
 
+	t.Run("successfully create election with legacy times as RFC 3339", func(t *testing.T) {
+		// Mocks
+		mockStub := &mocks.ChaincodeStubInterface{}
+		mockCtx := &mocks.TransactionContextInterface{}
+
+		mockCtx.On("GetStub").Return(mockStub)
+		mockCtx.On("GetClientIdentity").Return(MockClientIdentity("mockAdmin", chaincode.RoleElectionAdmin))
+
+		mockElection, _ := MockElection()
+		mockElection.StartTime = "2024-01-01 00:00:00"
+		mockElection.EndTime = "2024-01-01 23:59:59"
+		mockElectionData, err := json.Marshal(mockElection)
+		if err != nil {
+			t.Error(err)
+		}
+
+		storedTimes := mock.MatchedBy(func(data []byte) bool {
+			var election chaincode.Election
+			if err := json.Unmarshal(data, &election); err != nil {
+				return false
+			}
+
+			return election.StartTime == "2024-01-01T00:00:00Z" && election.EndTime == "2024-01-01T23:59:59Z"
+		})
+
+		mockStub.On("CreateCompositeKey", mockElection.Type(), []string{mockElection.Asset.ID}).Return(mockElection.Asset.ID, nil)
+		mockStub.On("GetState", mockElection.Asset.ID).Return(nil, nil)
+		mockStub.On("PutState", mockElection.Asset.ID, storedTimes).Return(nil, nil)
+		mockStub.On("SetEvent", string(chaincode.EventElectionCreated), mock.AnythingOfType("[]uint8")).Return(nil)
+
+		// Test
+		err = smartContract.CreateElection(mockCtx, string(mockElectionData))
+		require.NoError(t, err)
+		mockStub.AssertExpectations(t)
+	})
+
 	t.Run("fail to create existing election", func(t *testing.T) {
 		// Mocks
 		mockStub := &mocks.ChaincodeStubInterface{}
 		mockCtx := &mocks.TransactionContextInterface{}
 
 		mockCtx.On("GetStub").Return(mockStub)
+		mockCtx.On("GetClientIdentity").Return(MockClientIdentity("mockAdmin", chaincode.RoleElectionAdmin))
 
 		mockElection, mockElectionData := MockElection()
 
@@ -215,6 +447,7 @@ func TestCreateElection(t *testing.T) {
 		mockCtx := &mocks.TransactionContextInterface{}
 
 		mockCtx.On("GetStub").Return(mockStub)
+		mockCtx.On("GetClientIdentity").Return(MockClientIdentity("mockAdmin", chaincode.RoleElectionAdmin))
 
 		// Modify election for fail case
 		mockElection, _ := MockElection()
@@ -225,7 +458,53 @@ func TestCreateElection(t *testing.T) {
 		}
 
 		// Test
-		expectedError := fmt.Sprintf("%s is invalid! %s", mockElection.Type(), "parsing time \"error\" as \"2006-01-02 15:04:05\": cannot parse \"error\" as \"2006\"")
+		expectedError := fmt.Sprintf("%s is invalid! %s", mockElection.Type(), "StartTime \"error\" must be an RFC 3339 time, e.g. 2006-01-02T15:04:05Z07:00")
+
+		err = smartContract.CreateElection(mockCtx, string(mockElectionData))
+		require.EqualError(t, err, expectedError)
+	})
+
+	t.Run("fail to create election with unsupported scheme", func(t *testing.T) {
+		// Mocks
+		mockStub := &mocks.ChaincodeStubInterface{}
+		mockCtx := &mocks.TransactionContextInterface{}
+
+		mockCtx.On("GetStub").Return(mockStub)
+		mockCtx.On("GetClientIdentity").Return(MockClientIdentity("mockAdmin", chaincode.RoleElectionAdmin))
+
+		// Modify election for fail case
+		mockElection, _ := MockElection()
+		mockElection.Scheme = "rsa"
+		mockElectionData, err := json.Marshal(mockElection)
+		if err != nil {
+			t.Error(err)
+		}
+
+		// Test
+		expectedError := fmt.Sprintf("%s is invalid! %s", mockElection.Type(), "unsupported homomorphic scheme rsa")
+
+		err = smartContract.CreateElection(mockCtx, string(mockElectionData))
+		require.EqualError(t, err, expectedError)
+	})
+
+	t.Run("fail to create election with a scheme ballots do not support", func(t *testing.T) {
+		// Mocks
+		mockStub := &mocks.ChaincodeStubInterface{}
+		mockCtx := &mocks.TransactionContextInterface{}
+
+		mockCtx.On("GetStub").Return(mockStub)
+		mockCtx.On("GetClientIdentity").Return(MockClientIdentity("mockAdmin", chaincode.RoleElectionAdmin))
+
+		// Modify election for fail case
+		mockElection, _ := MockElection()
+		mockElection.Scheme = scheme.ElGamalName
+		mockElectionData, err := json.Marshal(mockElection)
+		if err != nil {
+			t.Error(err)
+		}
+
+		// Test
+		expectedError := fmt.Sprintf("unable to create election %s! ballots do not support the %s scheme yet", mockElection.Asset.ID, scheme.ElGamalName)
 
 		err = smartContract.CreateElection(mockCtx, string(mockElectionData))
 		require.EqualError(t, err, expectedError)
@@ -248,7 +527,7 @@ func TestQueryBallot(t *testing.T) {
 
 		mockBallot, mockBallotData := MockBallot()
 
-		mockStub.On("CreateCompositeKey", mockBallot.Type(), []string{mockBallot.Asset.ID}).Return(mockBallot.Asset.ID, nil)
+		MockAssetKeys(mockStub, mockBallot.Type(), mockBallot.ElectionID, mockBallot.Asset.ID)
 		mockStub.On("GetState", mockBallot.Asset.ID).Return(mockBallotData, nil)
 
 		// Test
@@ -268,7 +547,7 @@ func TestQueryBallot(t *testing.T) {
 
 		mockBallot, _ := MockBallot()
 
-		mockStub.On("CreateCompositeKey", mockBallot.Type(), []string{mockBallot.Asset.ID}).Return(mockBallot.Asset.ID, nil)
+		MockAssetKeys(mockStub, mockBallot.Type(), mockBallot.ElectionID, mockBallot.Asset.ID)
 		mockStub.On("GetState", mockBallot.Asset.ID).Return(nil, nil)
 
 		// Test
@@ -291,7 +570,7 @@ func TestQueryCandidate(t *testing.T) {
 
 		mockCandidate, mockCandidateData := MockCandidate()
 
-		mockStub.On("CreateCompositeKey", mockCandidate.Type(), []string{mockCandidate.Asset.ID}).Return(mockCandidate.Asset.ID, nil)
+		MockAssetKeys(mockStub, mockCandidate.Type(), mockCandidate.ElectionID, mockCandidate.Asset.ID)
 		mockStub.On("GetState", mockCandidate.Asset.ID).Return(mockCandidateData, nil)
 
 		// Test
@@ -311,7 +590,7 @@ func TestQueryCandidate(t *testing.T) {
 
 		mockCandidate, _ := MockCandidate()
 
-		mockStub.On("CreateCompositeKey", mockCandidate.Type(), []string{mockCandidate.Asset.ID}).Return(mockCandidate.Asset.ID, nil)
+		MockAssetKeys(mockStub, mockCandidate.Type(), mockCandidate.ElectionID, mockCandidate.Asset.ID)
 		mockStub.On("GetState", mockCandidate.Asset.ID).Return(nil, nil)
 
 		// Test
@@ -365,175 +644,1057 @@ func TestQueryElection(t *testing.T) {
 	})
 }
 
-// =============================================================================
-// Update Tests
-// =============================================================================
-
-func TestUpdateBallot(t *testing.T) {
+func TestQueryBallotsWithPagination(t *testing.T) {
 	smartContract := chaincode.SmartContract{}
 
-	t.Run("successfully to update ballot", func(t *testing.T) {
+	t.Run("successfully query page of ballots", func(t *testing.T) {
 		// Mocks
 		mockStub := &mocks.ChaincodeStubInterface{}
 		mockCtx := &mocks.TransactionContextInterface{}
+		mockIterator := &mocks.StateQueryIteratorInterface{}
 
 		mockCtx.On("GetStub").Return(mockStub)
 
 		mockBallot, mockBallotData := MockBallot()
 
-		mockStub.On("CreateCompositeKey", mockBallot.Type(), []string{mockBallot.Asset.ID}).Return(mockBallot.Asset.ID, nil)
-		mockStub.On("GetState", mockBallot.Asset.ID).Return(mockBallotData, nil)
-		mockStub.On("PutState", mockBallot.Asset.ID, mock.AnythingOfType("[]uint8")).Return(nil, nil)
+		mockIterator.On("HasNext").Return(true).Once()
+		mockIterator.On("Next").Return(&queryresult.KV{Key: mockBallot.Asset.ID, Value: mockBallotData}, nil).Once()
+		mockIterator.On("HasNext").Return(false)
+		mockIterator.On("Close").Return(nil)
 
-		// Test
-		mockBallot.VoterID = "v-0"
-		updatedMockBallotData, err := json.Marshal(mockBallot)
-		if err != nil {
-			t.Error(err)
-		}
+		metadata := &peer.QueryResponseMetadata{FetchedRecordsCount: 1, Bookmark: "nextBookmark"}
+		mockStub.On("GetStateByPartialCompositeKeyWithPagination", mockBallot.Type(), []string{mockBallot.ElectionID}, int32(1), "bookmark").Return(mockIterator, metadata, nil)
 
-		err = smartContract.UpdateBallot(mockCtx, string(updatedMockBallotData))
+		// Test
+		page, err := smartContract.QueryBallotsByElectionWithPagination(mockCtx, mockBallot.ElectionID, 1, "bookmark")
 		require.NoError(t, err)
+		require.Equal(t, "nextBookmark", page.Bookmark)
+		require.Equal(t, int32(1), page.Count)
+		require.Len(t, page.Results, 1)
+		require.Equal(t, mockBallot.Asset.ID, page.Results[0].Asset.ID)
 	})
 
-	t.Run("fail to update identical ballot data", func(t *testing.T) {
+	t.Run("fail to query page of ballots with invalid page size", func(t *testing.T) {
 		// Mocks
 		mockStub := &mocks.ChaincodeStubInterface{}
 		mockCtx := &mocks.TransactionContextInterface{}
 
 		mockCtx.On("GetStub").Return(mockStub)
 
-		mockBallot, mockBallotData := MockBallot()
-
-		mockStub.On("CreateCompositeKey", mockBallot.Type(), []string{mockBallot.Asset.ID}).Return(mockBallot.Asset.ID, nil)
-		mockStub.On("GetState", mockBallot.Asset.ID).Return(mockBallotData, nil)
-
 		// Test
-		expectedError := chaincode.ObjectEqualityError{mockBallot.Asset.ID, mockBallot.Type()}
-
-		err := smartContract.UpdateBallot(mockCtx, string(mockBallotData))
-		require.EqualError(t, err, expectedError.Error())
+		_, err := smartContract.QueryAllBallotsWithPagination(mockCtx, 0, "")
+		require.EqualError(t, err, "page size must be at least 1, got 0")
 	})
+}
 
-	t.Run("fail to update non-existent ballot", func(t *testing.T) {
+func TestRichQueries(t *testing.T) {
+	smartContract := chaincode.SmartContract{StateDatabase: chaincode.StateDatabaseCouchDB}
+
+	t.Run("successfully query ballots by election and voted", func(t *testing.T) {
 		// Mocks
 		mockStub := &mocks.ChaincodeStubInterface{}
 		mockCtx := &mocks.TransactionContextInterface{}
+		mockIterator := &mocks.StateQueryIteratorInterface{}
 
 		mockCtx.On("GetStub").Return(mockStub)
 
 		mockBallot, mockBallotData := MockBallot()
 
-		mockStub.On("CreateCompositeKey", mockBallot.Type(), []string{mockBallot.Asset.ID}).Return(mockBallot.Asset.ID, nil)
-		mockStub.On("GetState", mockBallot.Asset.ID).Return(nil, nil)
+		mockIterator.On("HasNext").Return(true).Once()
+		mockIterator.On("Next").Return(&queryresult.KV{Key: mockBallot.Asset.ID, Value: mockBallotData}, nil).Once()
+		mockIterator.On("HasNext").Return(false)
+		mockIterator.On("Close").Return(nil)
+
+		query := `{"selector":{"ElectionID":"e-0","Voted":false},"use_index":["_design/indexBallotsByElectionDoc","indexBallotsByElection"]}`
+		mockStub.On("GetQueryResult", query).Return(mockIterator, nil)
 
 		// Test
-		expectedError := fmt.Sprintf("cannot read world state with key %s", mockBallot.Asset.ID)
+		ballots, err := smartContract.QueryBallotsByElectionAndVoted(mockCtx, mockBallot.ElectionID, false)
+		require.NoError(t, err)
+		require.Len(t, ballots, 1)
+		require.Equal(t, mockBallot.Asset.ID, ballots[0].Asset.ID)
 
-		err := smartContract.UpdateBallot(mockCtx, string(mockBallotData))
-		require.EqualError(t, err, expectedError)
+		mockStub.AssertExpectations(t)
+		mockIterator.AssertExpectations(t)
 	})
-}
-
-func TestUpdateCandidate(t *testing.T) {
-	smartContract := chaincode.SmartContract{}
 
-	t.Run("successfully update candidate", func(t *testing.T) {
+	t.Run("fail to query ballots when the rich query fails", func(t *testing.T) {
 		// Mocks
 		mockStub := &mocks.ChaincodeStubInterface{}
 		mockCtx := &mocks.TransactionContextInterface{}
 
 		mockCtx.On("GetStub").Return(mockStub)
 
-		mockCandidate, mockCandidateData := MockCandidate()
-
-		mockStub.On("CreateCompositeKey", mockCandidate.Type(), []string{mockCandidate.Asset.ID}).Return(mockCandidate.Asset.ID, nil)
-		mockStub.On("GetState", mockCandidate.Asset.ID).Return(mockCandidateData, nil)
-		mockStub.On("PutState", mockCandidate.Asset.ID, mock.AnythingOfType("[]uint8")).Return(nil, nil)
+		mockStub.On("GetQueryResult", mock.AnythingOfType("string")).Return(nil, errors.New("index not supported by design document"))
 
 		// Test
-		mockCandidate.Name = "mockCandidate1"
-		updatedMockCandidateData, err := json.Marshal(mockCandidate)
-		if err != nil {
-			t.Error(err)
-		}
-
-		err = smartContract.UpdateCandidate(mockCtx, string(updatedMockCandidateData))
-		require.NoError(t, err)
+		_, err := smartContract.QueryBallotsByElectionAndVoted(mockCtx, "e-0", true)
+		require.EqualError(t, err, "index not supported by design document")
+		mockStub.AssertNotCalled(t, "GetStateByPartialCompositeKey", mock.Anything, mock.Anything)
 	})
 
-	t.Run("fail to update identical candidate data", func(t *testing.T) {
+	t.Run("fall back to key range when the state database is LevelDB", func(t *testing.T) {
+		smartContract := chaincode.SmartContract{StateDatabase: chaincode.StateDatabaseLevelDB}
+
 		// Mocks
 		mockStub := &mocks.ChaincodeStubInterface{}
 		mockCtx := &mocks.TransactionContextInterface{}
+		mockIterator := &mocks.StateQueryIteratorInterface{}
 
 		mockCtx.On("GetStub").Return(mockStub)
 
-		mockCandidate, mockCandidateData := MockCandidate()
+		mockBallot, mockBallotData := MockBallot()
+		mockCandidate, _ := MockCandidate()
+		votedBallot := MockVotedBallot(t, "b-1", mockBallot.ElectionID, *mockCandidate)
+		votedBallotData, err := json.Marshal(votedBallot)
+		if err != nil {
+			t.Error(err)
+		}
 
-		mockStub.On("CreateCompositeKey", mockCandidate.Type(), []string{mockCandidate.Asset.ID}).Return(mockCandidate.Asset.ID, nil)
-		mockStub.On("GetState", mockCandidate.Asset.ID).Return(mockCandidateData, nil)
+		mockIterator.On("HasNext").Return(true).Twice()
+		mockIterator.On("Next").Return(&queryresult.KV{Key: mockBallot.Asset.ID, Value: mockBallotData}, nil).Once()
+		mockIterator.On("Next").Return(&queryresult.KV{Key: votedBallot.Asset.ID, Value: votedBallotData}, nil).Once()
+		mockIterator.On("HasNext").Return(false)
+		mockIterator.On("Close").Return(nil)
+
+		mockStub.On("GetStateByPartialCompositeKey", mockBallot.Type(), []string{mockBallot.ElectionID}).Return(mockIterator, nil)
 
 		// Test
-		expectedError := chaincode.ObjectEqualityError{mockCandidate.Asset.ID, mockCandidate.Type()}
+		ballots, err := smartContract.QueryBallotsByElectionAndVoted(mockCtx, mockBallot.ElectionID, true)
+		require.NoError(t, err)
+		require.Len(t, ballots, 1)
+		require.Equal(t, votedBallot.Asset.ID, ballots[0].Asset.ID)
 
-		err := smartContract.UpdateCandidate(mockCtx, string(mockCandidateData))
-		require.EqualError(t, err, expectedError.Error())
+		mockStub.AssertExpectations(t)
+		mockIterator.AssertExpectations(t)
 	})
 
-	t.Run("fail to update non-existent candidate", func(t *testing.T) {
+	t.Run("fall back to key range for page of elections by time window without state database", func(t *testing.T) {
+		smartContract := chaincode.SmartContract{}
+
 		// Mocks
 		mockStub := &mocks.ChaincodeStubInterface{}
 		mockCtx := &mocks.TransactionContextInterface{}
+		mockIterator := &mocks.StateQueryIteratorInterface{}
 
 		mockCtx.On("GetStub").Return(mockStub)
 
-		mockCandidate, mockCandidateData := MockCandidate()
+		mockElection, mockElectionData := MockElection()
 
-		mockStub.On("CreateCompositeKey", mockCandidate.Type(), []string{mockCandidate.Asset.ID}).Return(mockCandidate.Asset.ID, nil)
-		mockStub.On("GetState", mockCandidate.Asset.ID).Return(nil, nil)
+		mockIterator.On("HasNext").Return(true).Once()
+		mockIterator.On("Next").Return(&queryresult.KV{Key: mockElection.Asset.ID, Value: mockElectionData}, nil).Once()
+		mockIterator.On("HasNext").Return(false)
+		mockIterator.On("Close").Return(nil)
+
+		metadata := &peer.QueryResponseMetadata{FetchedRecordsCount: 1, Bookmark: "nextBookmark"}
+		mockStub.On("GetStateByPartialCompositeKeyWithPagination", mockElection.Type(), []string{}, int32(1), "").Return(mockIterator, metadata, nil)
 
 		// Test
-		expectedError := fmt.Sprintf("cannot read world state with key %s", mockCandidate.Asset.ID)
+		// 2024-01-02T07:00:00+08:00 is 2024-01-01T23:00:00Z, before the election ends
+		page, err := smartContract.QueryElectionsByTimeWindowWithPagination(mockCtx, "2024-01-02T07:00:00+08:00", "2024-01-03T00:00:00Z", 1, "")
+		require.NoError(t, err)
+		require.Equal(t, "nextBookmark", page.Bookmark)
+		require.Equal(t, int32(1), page.Count)
+		require.Len(t, page.Results, 1)
+		require.Equal(t, mockElection.Asset.ID, page.Results[0].Asset.ID)
 
-		err := smartContract.UpdateCandidate(mockCtx, string(mockCandidateData))
-		require.EqualError(t, err, expectedError)
+		mockStub.AssertExpectations(t)
+		mockIterator.AssertExpectations(t)
 	})
-}
 
-func TestUpdateElection(t *testing.T) {
-	smartContract := chaincode.SmartContract{}
-
-	t.Run("successfully update Election", func(t *testing.T) {
+	t.Run("fail to query elections with end before start", func(t *testing.T) {
 		// Mocks
 		mockStub := &mocks.ChaincodeStubInterface{}
 		mockCtx := &mocks.TransactionContextInterface{}
 
 		mockCtx.On("GetStub").Return(mockStub)
 
-		mockElection, mockElectionData := MockElection()
+		// Test
+		_, err := smartContract.QueryElectionsByTimeWindow(mockCtx, "2024-01-02T00:00:00Z", "2024-01-01T00:00:00Z")
+		require.EqualError(t, err, "end 2024-01-01T00:00:00Z must be after start 2024-01-02T00:00:00Z")
+	})
+}
+
+// =============================================================================
+// Update Tests
+// =============================================================================
+
+func TestUpdateBallot(t *testing.T) {
+	smartContract := chaincode.SmartContract{}
+
+	t.Run("successfully to update ballot", func(t *testing.T) {
+		// Mocks
+		mockStub := &mocks.ChaincodeStubInterface{}
+		mockCtx := &mocks.TransactionContextInterface{}
+
+		mockCtx.On("GetStub").Return(mockStub)
+		mockCtx.On("GetClientIdentity").Return(MockClientIdentity("mockRegistrar", chaincode.RoleRegistrar))
+
+		mockBallot, mockBallotData := MockBallot()
+		mockElection, mockElectionData := MockElection()
+
+		MockAssetKeys(mockStub, mockBallot.Type(), mockBallot.ElectionID, mockBallot.Asset.ID)
+		mockStub.On("GetState", mockBallot.Asset.ID).Return(mockBallotData, nil)
+		mockStub.On("CreateCompositeKey", mockElection.Type(), []string{mockElection.Asset.ID}).Return(mockElection.Asset.ID, nil)
+		mockStub.On("GetState", mockElection.Asset.ID).Return(mockElectionData, nil)
+		mockStub.On("PutState", mockBallot.Asset.ID, mock.AnythingOfType("[]uint8")).Return(nil, nil)
+
+		// Test
+		mockBallot.VoterID = "v-0"
+		updatedMockBallotData, err := json.Marshal(mockBallot)
+		if err != nil {
+			t.Error(err)
+		}
+
+		err = smartContract.UpdateBallot(mockCtx, string(updatedMockBallotData))
+		require.NoError(t, err)
+	})
+
+	t.Run("fail to update identical ballot data", func(t *testing.T) {
+		// Mocks
+		mockStub := &mocks.ChaincodeStubInterface{}
+		mockCtx := &mocks.TransactionContextInterface{}
+
+		mockCtx.On("GetStub").Return(mockStub)
+		mockCtx.On("GetClientIdentity").Return(MockClientIdentity("mockRegistrar", chaincode.RoleRegistrar))
+
+		mockBallot, mockBallotData := MockBallot()
+		mockElection, mockElectionData := MockElection()
+
+		MockAssetKeys(mockStub, mockBallot.Type(), mockBallot.ElectionID, mockBallot.Asset.ID)
+		mockStub.On("GetState", mockBallot.Asset.ID).Return(mockBallotData, nil)
+		mockStub.On("CreateCompositeKey", mockElection.Type(), []string{mockElection.Asset.ID}).Return(mockElection.Asset.ID, nil)
+		mockStub.On("GetState", mockElection.Asset.ID).Return(mockElectionData, nil)
+
+		// Test
+		expectedError := chaincode.ObjectEqualityError{mockBallot.Asset.ID, mockBallot.Type()}
+
+		err := smartContract.UpdateBallot(mockCtx, string(mockBallotData))
+		require.EqualError(t, err, expectedError.Error())
+	})
+
+	t.Run("fail to update non-existent ballot", func(t *testing.T) {
+		// Mocks
+		mockStub := &mocks.ChaincodeStubInterface{}
+		mockCtx := &mocks.TransactionContextInterface{}
+
+		mockCtx.On("GetStub").Return(mockStub)
+		mockCtx.On("GetClientIdentity").Return(MockClientIdentity("mockRegistrar", chaincode.RoleRegistrar))
+
+		mockBallot, mockBallotData := MockBallot()
+
+		MockAssetKeys(mockStub, mockBallot.Type(), mockBallot.ElectionID, mockBallot.Asset.ID)
+		mockStub.On("GetState", mockBallot.Asset.ID).Return(nil, nil)
+
+		// Test
+		expectedError := fmt.Sprintf("cannot read world state with key %s", mockBallot.Asset.ID)
+
+		err := smartContract.UpdateBallot(mockCtx, string(mockBallotData))
+		require.EqualError(t, err, expectedError)
+	})
+
+	t.Run("fail to mark ballot as voted", func(t *testing.T) {
+		// Mocks
+		mockStub := &mocks.ChaincodeStubInterface{}
+		mockCtx := &mocks.TransactionContextInterface{}
+
+		mockCtx.On("GetStub").Return(mockStub)
+		mockCtx.On("GetClientIdentity").Return(MockClientIdentity("mockRegistrar", chaincode.RoleRegistrar))
+
+		mockBallot, mockBallotData := MockBallot()
+		mockElection, mockElectionData := MockElection()
+
+		MockAssetKeys(mockStub, mockBallot.Type(), mockBallot.ElectionID, mockBallot.Asset.ID)
+		mockStub.On("GetState", mockBallot.Asset.ID).Return(mockBallotData, nil)
+		mockStub.On("CreateCompositeKey", mockElection.Type(), []string{mockElection.Asset.ID}).Return(mockElection.Asset.ID, nil)
+		mockStub.On("GetState", mockElection.Asset.ID).Return(mockElectionData, nil)
+
+		// Modify ballot for fail case
+		mockBallot.Voted = true
+
+		updatedMockBallotData, err := json.Marshal(mockBallot)
+		if err != nil {
+			t.Error(err)
+		}
+
+		// Test
+		expectedError := fmt.Sprintf("unable to change the candidates or counts of ballot %s! votes can only be cast with CastVote or CastEncryptedVote", mockBallot.Asset.ID)
+
+		err = smartContract.UpdateBallot(mockCtx, string(updatedMockBallotData))
+		require.EqualError(t, err, expectedError)
+	})
+
+	t.Run("fail to change candidates of ballot", func(t *testing.T) {
+		// Mocks
+		mockStub := &mocks.ChaincodeStubInterface{}
+		mockCtx := &mocks.TransactionContextInterface{}
+
+		mockCtx.On("GetStub").Return(mockStub)
+		mockCtx.On("GetClientIdentity").Return(MockClientIdentity("mockRegistrar", chaincode.RoleRegistrar))
+
+		mockBallot, _ := MockBallot()
+		mockCandidate, _ := MockCandidate()
+		mockBallot.Candidates = []chaincode.Candidate{*mockCandidate}
+		if err := mockBallot.Init(MockRandomness()); err != nil {
+			t.Error(err)
+		}
+		mockBallotData, err := json.Marshal(mockBallot)
+		if err != nil {
+			t.Error(err)
+		}
+		mockElection, mockElectionData := MockElection()
+
+		MockAssetKeys(mockStub, mockBallot.Type(), mockBallot.ElectionID, mockBallot.Asset.ID)
+		mockStub.On("GetState", mockBallot.Asset.ID).Return(mockBallotData, nil)
+		mockStub.On("CreateCompositeKey", mockElection.Type(), []string{mockElection.Asset.ID}).Return(mockElection.Asset.ID, nil)
+		mockStub.On("GetState", mockElection.Asset.ID).Return(mockElectionData, nil)
+
+		// Modify ballot for fail case by adding a candidate with a well-formed count
+		otherMockCandidate, _ := MockCandidate()
+		otherMockCandidate.Asset.ID = "c-1"
+		mockBallot.Candidates = []chaincode.Candidate{*mockCandidate, *otherMockCandidate}
+		if err := mockBallot.Init(MockRandomness()); err != nil {
+			t.Error(err)
+		}
+
+		updatedMockBallotData, err := json.Marshal(mockBallot)
+		if err != nil {
+			t.Error(err)
+		}
+
+		// Test
+		expectedError := fmt.Sprintf("unable to change the candidates or counts of ballot %s! votes can only be cast with CastVote or CastEncryptedVote", mockBallot.Asset.ID)
+
+		err = smartContract.UpdateBallot(mockCtx, string(updatedMockBallotData))
+		require.EqualError(t, err, expectedError)
+	})
+
+	t.Run("fail to update ballot with malformed counts", func(t *testing.T) {
+		// Mocks
+		mockStub := &mocks.ChaincodeStubInterface{}
+		mockCtx := &mocks.TransactionContextInterface{}
+
+		mockCtx.On("GetStub").Return(mockStub)
+		mockCtx.On("GetClientIdentity").Return(MockClientIdentity("mockRegistrar", chaincode.RoleRegistrar))
+
+		mockBallot, _ := MockBallot()
+		mockCandidate, _ := MockCandidate()
+		mockBallot.Candidates = []chaincode.Candidate{*mockCandidate}
+		if err := mockBallot.Init(MockRandomness()); err != nil {
+			t.Error(err)
+		}
+
+		// Modify ballot for fail case by adding 1000 votes to the candidate's count
+		publicKey, err := paillier.Base64Decode[paillier.PublicKey](mockCandidate.PublicKey)
+		if err != nil {
+			t.Error(err)
+		}
+		count, _ := new(big.Int).SetString(mockBallot.Candidates[0].Count, 10)
+		mockBallot.Candidates[0].Count = paillier.AddEncryptedWithPlain(publicKey, count, big.NewInt(1000)).String()
+
+		updatedMockBallotData, err := json.Marshal(mockBallot)
+		if err != nil {
+			t.Error(err)
+		}
+
+		// Test
+		expectedError := &chaincode.ObjectValidationError{"candidate c-0 count is not well-formed", mockBallot.Type()}
+
+		err = smartContract.UpdateBallot(mockCtx, string(updatedMockBallotData))
+		require.EqualError(t, err, expectedError.Error())
+	})
+
+	t.Run("fail to update packed ballot with malformed count", func(t *testing.T) {
+		// Mocks
+		mockStub := &mocks.ChaincodeStubInterface{}
+		mockCtx := &mocks.TransactionContextInterface{}
+
+		mockCtx.On("GetStub").Return(mockStub)
+		mockCtx.On("GetClientIdentity").Return(MockClientIdentity("mockRegistrar", chaincode.RoleRegistrar))
+
+		mockBallot, _ := MockBallot()
+		mockCandidate, _ := MockCandidate()
+		otherMockCandidate, _ := MockCandidate()
+		otherMockCandidate.Asset.ID = "c-1"
+		mockBallot.Candidates = []chaincode.Candidate{*mockCandidate, *otherMockCandidate}
+		mockBallot.PackingBase = "100"
+		if err := mockBallot.Init(MockRandomness()); err != nil {
+			t.Error(err)
+		}
+
+		// Modify ballot for fail case by adding a vote to the second candidate's slot
+		publicKey, err := paillier.Base64Decode[paillier.PublicKey](mockCandidate.PublicKey)
+		if err != nil {
+			t.Error(err)
+		}
+		count, _ := new(big.Int).SetString(mockBallot.PackedCount, 10)
+		mockBallot.PackedCount = paillier.AddEncryptedWithPlain(publicKey, count, big.NewInt(100)).String()
+
+		updatedMockBallotData, err := json.Marshal(mockBallot)
+		if err != nil {
+			t.Error(err)
+		}
+
+		// Test
+		expectedError := &chaincode.ObjectValidationError{"ballot b-0 packed count is not well-formed", mockBallot.Type()}
+
+		err = smartContract.UpdateBallot(mockCtx, string(updatedMockBallotData))
+		require.EqualError(t, err, expectedError.Error())
+	})
+}
+
+func TestUpdateCandidate(t *testing.T) {
+	smartContract := chaincode.SmartContract{}
+
+	t.Run("successfully update candidate", func(t *testing.T) {
+		// Mocks
+		mockStub := &mocks.ChaincodeStubInterface{}
+		mockCtx := &mocks.TransactionContextInterface{}
+
+		mockCtx.On("GetStub").Return(mockStub)
+		mockCtx.On("GetClientIdentity").Return(MockClientIdentity("mockAdmin", chaincode.RoleElectionAdmin))
+
+		mockCandidate, mockCandidateData := MockCandidate()
+		mockElection, mockElectionData := MockElection()
+
+		MockAssetKeys(mockStub, mockCandidate.Type(), mockCandidate.ElectionID, mockCandidate.Asset.ID)
+		mockStub.On("GetState", mockCandidate.Asset.ID).Return(mockCandidateData, nil)
+		mockStub.On("CreateCompositeKey", mockElection.Type(), []string{mockElection.Asset.ID}).Return(mockElection.Asset.ID, nil)
+		mockStub.On("GetState", mockElection.Asset.ID).Return(mockElectionData, nil)
+		mockStub.On("PutState", mockCandidate.Asset.ID, mock.AnythingOfType("[]uint8")).Return(nil, nil)
+
+		// Test
+		mockCandidate.Name = "mockCandidate1"
+		updatedMockCandidateData, err := json.Marshal(mockCandidate)
+		if err != nil {
+			t.Error(err)
+		}
+
+		err = smartContract.UpdateCandidate(mockCtx, string(updatedMockCandidateData))
+		require.NoError(t, err)
+	})
+
+	t.Run("fail to update identical candidate data", func(t *testing.T) {
+		// Mocks
+		mockStub := &mocks.ChaincodeStubInterface{}
+		mockCtx := &mocks.TransactionContextInterface{}
+
+		mockCtx.On("GetStub").Return(mockStub)
+		mockCtx.On("GetClientIdentity").Return(MockClientIdentity("mockAdmin", chaincode.RoleElectionAdmin))
+
+		mockCandidate, mockCandidateData := MockCandidate()
+		mockElection, mockElectionData := MockElection()
+
+		MockAssetKeys(mockStub, mockCandidate.Type(), mockCandidate.ElectionID, mockCandidate.Asset.ID)
+		mockStub.On("GetState", mockCandidate.Asset.ID).Return(mockCandidateData, nil)
+		mockStub.On("CreateCompositeKey", mockElection.Type(), []string{mockElection.Asset.ID}).Return(mockElection.Asset.ID, nil)
+		mockStub.On("GetState", mockElection.Asset.ID).Return(mockElectionData, nil)
+
+		// Test
+		expectedError := chaincode.ObjectEqualityError{mockCandidate.Asset.ID, mockCandidate.Type()}
+
+		err := smartContract.UpdateCandidate(mockCtx, string(mockCandidateData))
+		require.EqualError(t, err, expectedError.Error())
+	})
+
+	t.Run("fail to update non-existent candidate", func(t *testing.T) {
+		// Mocks
+		mockStub := &mocks.ChaincodeStubInterface{}
+		mockCtx := &mocks.TransactionContextInterface{}
+
+		mockCtx.On("GetStub").Return(mockStub)
+		mockCtx.On("GetClientIdentity").Return(MockClientIdentity("mockAdmin", chaincode.RoleElectionAdmin))
+
+		mockCandidate, mockCandidateData := MockCandidate()
+
+		MockAssetKeys(mockStub, mockCandidate.Type(), mockCandidate.ElectionID, mockCandidate.Asset.ID)
+		mockStub.On("GetState", mockCandidate.Asset.ID).Return(nil, nil)
+
+		// Test
+		expectedError := fmt.Sprintf("cannot read world state with key %s", mockCandidate.Asset.ID)
+
+		err := smartContract.UpdateCandidate(mockCtx, string(mockCandidateData))
+		require.EqualError(t, err, expectedError)
+	})
+}
+
+func TestUpdateElection(t *testing.T) {
+	smartContract := chaincode.SmartContract{}
+
+	t.Run("successfully update Election", func(t *testing.T) {
+		// Mocks
+		mockStub := &mocks.ChaincodeStubInterface{}
+		mockCtx := &mocks.TransactionContextInterface{}
+
+		mockCtx.On("GetStub").Return(mockStub)
+		mockCtx.On("GetClientIdentity").Return(MockClientIdentity("mockAdmin", chaincode.RoleElectionAdmin))
+
+		mockElection, mockElectionData := MockElection()
+
+		mockStub.On("CreateCompositeKey", mockElection.Type(), []string{mockElection.Asset.ID}).Return(mockElection.Asset.ID, nil)
+		mockStub.On("GetState", mockElection.Asset.ID).Return(mockElectionData, nil)
+		mockStub.On("PutState", mockElection.Asset.ID, mock.AnythingOfType("[]uint8")).Return(nil, nil)
+
+		// Test
+		mockElection.Name = "mockElection1"
+		updatedMockElectionData, err := json.Marshal(mockElection)
+		if err != nil {
+			t.Error(err)
+		}
+
+		err = smartContract.UpdateElection(mockCtx, string(updatedMockElectionData))
+		require.NoError(t, err)
+	})
+
+	t.Run("fail to update identical election data", func(t *testing.T) {
+		// Mocks
+		mockStub := &mocks.ChaincodeStubInterface{}
+		mockCtx := &mocks.TransactionContextInterface{}
+
+		mockCtx.On("GetStub").Return(mockStub)
+		mockCtx.On("GetClientIdentity").Return(MockClientIdentity("mockAdmin", chaincode.RoleElectionAdmin))
+
+		mockElection, mockElectionData := MockElection()
+
+		mockStub.On("CreateCompositeKey", mockElection.Type(), []string{mockElection.Asset.ID}).Return(mockElection.Asset.ID, nil)
+		mockStub.On("GetState", mockElection.Asset.ID).Return(mockElectionData, nil)
+
+		// Test
+		expectedError := chaincode.ObjectEqualityError{mockElection.Asset.ID, mockElection.Type()}
+
+		err := smartContract.UpdateElection(mockCtx, string(mockElectionData))
+		require.EqualError(t, err, expectedError.Error())
+	})
+
+	t.Run("fail to update non-existent Election", func(t *testing.T) {
+		// Mocks
+		mockStub := &mocks.ChaincodeStubInterface{}
+		mockCtx := &mocks.TransactionContextInterface{}
+
+		mockCtx.On("GetStub").Return(mockStub)
+		mockCtx.On("GetClientIdentity").Return(MockClientIdentity("mockAdmin", chaincode.RoleElectionAdmin))
+
+		mockElection, mockElectionData := MockElection()
+
+		mockStub.On("CreateCompositeKey", mockElection.Type(), []string{mockElection.Asset.ID}).Return(mockElection.Asset.ID, nil)
+		mockStub.On("GetState", mockElection.Asset.ID).Return(nil, nil)
+
+		// Test
+		expectedError := fmt.Sprintf("cannot read world state with key %s", mockElection.Asset.ID)
+
+		err := smartContract.UpdateElection(mockCtx, string(mockElectionData))
+		require.EqualError(t, err, expectedError)
+	})
+
+	t.Run("fail to invalidate existing Election", func(t *testing.T) {
+		// Mocks
+		mockStub := &mocks.ChaincodeStubInterface{}
+		mockCtx := &mocks.TransactionContextInterface{}
+
+		mockCtx.On("GetStub").Return(mockStub)
+		mockCtx.On("GetClientIdentity").Return(MockClientIdentity("mockAdmin", chaincode.RoleElectionAdmin))
+
+		mockElection, mockElectionData := MockElection()
+
+		mockStub.On("CreateCompositeKey", mockElection.Type(), []string{mockElection.Asset.ID}).Return(mockElection.Asset.ID, nil)
+		mockStub.On("GetState", mockElection.Asset.ID).Return(mockElectionData, nil)
+
+		// Modify election for fail case
+		mockElection.StartTime = "2024-01-02 00:00:00"
+		updatedMockElectionData, err := json.Marshal(mockElection)
+		if err != nil {
+			t.Error(err)
+		}
+
+		// Test
+		expectedError := &chaincode.ObjectValidationError{"EndTime must be after StartTime", mockElection.Type()}
+
+		err = smartContract.UpdateElection(mockCtx, string(updatedMockElectionData))
+		require.EqualError(t, err, expectedError.Error())
+	})
+}
+
+// =============================================================================
+// Deletion Tests
+// =============================================================================
+
+func TestDeleteBallot(t *testing.T) {
+	smartContract := chaincode.SmartContract{}
+
+	t.Run("successfully delete ballot", func(t *testing.T) {
+		// Mocks
+		mockStub := &mocks.ChaincodeStubInterface{}
+		mockCtx := &mocks.TransactionContextInterface{}
+
+		mockCtx.On("GetStub").Return(mockStub)
+		mockCtx.On("GetClientIdentity").Return(MockClientIdentity("mockRegistrar", chaincode.RoleRegistrar))
+
+		mockBallot, mockBallotData, mockElectionData := MockOpenBallot(t, "v-0")
+
+		MockAssetKeys(mockStub, mockBallot.Type(), mockBallot.ElectionID, mockBallot.Asset.ID)
+		mockStub.On("GetState", mockBallot.Asset.ID).Return(mockBallotData, nil)
+		mockStub.On("CreateCompositeKey", "chaincode.Election", []string{mockBallot.ElectionID}).Return(mockBallot.ElectionID, nil)
+		mockStub.On("GetState", mockBallot.ElectionID).Return(mockElectionData, nil)
+		mockStub.On("DelState", mock.AnythingOfType("string")).Return(nil)
+
+		// Test
+		err := smartContract.DeleteBallot(mockCtx, mockBallot.Asset.ID)
+		require.NoError(t, err)
+		mockStub.AssertCalled(t, "DelState", mockBallot.Asset.ID)
+	})
+
+	t.Run("fail to delete voted ballot", func(t *testing.T) {
+		// Mocks
+		mockStub := &mocks.ChaincodeStubInterface{}
+		mockCtx := &mocks.TransactionContextInterface{}
+
+		mockCtx.On("GetStub").Return(mockStub)
+		mockCtx.On("GetClientIdentity").Return(MockClientIdentity("mockRegistrar", chaincode.RoleRegistrar))
+
+		mockBallot, _, mockElectionData := MockOpenBallot(t, "v-0")
+		if err := mockBallot.Vote(MockRandomness(), "c-0"); err != nil {
+			t.Error(err)
+		}
+		mockBallotData, err := json.Marshal(mockBallot)
+		if err != nil {
+			t.Error(err)
+		}
+
+		MockAssetKeys(mockStub, mockBallot.Type(), mockBallot.ElectionID, mockBallot.Asset.ID)
+		mockStub.On("GetState", mockBallot.Asset.ID).Return(mockBallotData, nil)
+		mockStub.On("CreateCompositeKey", "chaincode.Election", []string{mockBallot.ElectionID}).Return(mockBallot.ElectionID, nil)
+		mockStub.On("GetState", mockBallot.ElectionID).Return(mockElectionData, nil)
+
+		// Test
+		err = smartContract.DeleteBallot(mockCtx, mockBallot.Asset.ID)
+		require.EqualError(t, err, fmt.Sprintf("unable to delete ballot %s that has already been voted", mockBallot.Asset.ID))
+		mockStub.AssertNotCalled(t, "DelState", mock.Anything)
+	})
+}
+
+// =============================================================================
+// Custom Method Tests
+// =============================================================================
+
+func TestCastVote(t *testing.T) {
+	smartContract := chaincode.SmartContract{}
+
+	t.Run("fail to cast vote outside of election time", func(t *testing.T) {
+		// Mocks
+		mockStub := &mocks.ChaincodeStubInterface{}
+		mockCtx := &mocks.TransactionContextInterface{}
+
+		mockCtx.On("GetStub").Return(mockStub)
+		mockCtx.On("GetClientIdentity").Return(MockClientIdentity("v-0", chaincode.RoleVoter))
+
+		mockBallot, _ := MockBallot()
+		mockBallot.VoterID = chaincode.MSPQualifiedID(mockMSPID, "v-0")
+		mockBallotData, err := json.Marshal(mockBallot)
+		if err != nil {
+			t.Error(err)
+		}
+
+		mockElection, _ := MockElection()
+		mockElection.Status = chaincode.StatusOpen
+		mockElectionData, err := json.Marshal(mockElection)
+		if err != nil {
+			t.Error(err)
+		}
+
+		MockAssetKeys(mockStub, mockBallot.Type(), mockBallot.ElectionID, mockBallot.Asset.ID)
+		mockStub.On("GetState", mockBallot.Asset.ID).Return(mockBallotData, nil)
+		mockStub.On("CreateCompositeKey", mockElection.Type(), []string{mockElection.Asset.ID}).Return(mockElection.Asset.ID, nil)
+		mockStub.On("GetState", mockElection.Asset.ID).Return(mockElectionData, nil)
+
+		// The election ends at 2024-01-01T23:59:59Z
+		mockStub.On("GetTxTimestamp").Return(timestamppb.New(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)), nil)
+
+		// Test
+		expectedError := fmt.Sprintf("election %s is not active! vote cannot be cast", mockElection.Asset.ID)
+
+		err = smartContract.CastVote(mockCtx, mockBallot.Asset.ID, "c-0")
+		require.EqualError(t, err, expectedError)
+	})
+
+	t.Run("successfully cast encrypted vote", func(t *testing.T) {
+		// Mocks
+		mockStub := &mocks.ChaincodeStubInterface{}
+		mockCtx := &mocks.TransactionContextInterface{}
+
+		mockCtx.On("GetStub").Return(mockStub)
+		mockCtx.On("GetClientIdentity").Return(MockClientIdentity("v-0", chaincode.RoleVoter))
+
+		mockBallot, mockBallotData, mockElectionData := MockOpenBallot(t, "v-0")
+
+		encryptedCounters, proofs, err := mockBallot.EncryptVote(MockRandomness(), "c-0")
+		require.NoError(t, err)
+
+		votedBallot := mock.MatchedBy(func(data []byte) bool {
+			var ballot chaincode.Ballot
+			if err := json.Unmarshal(data, &ballot); err != nil {
+				return false
+			}
+
+			return ballot.Voted && ballot.Candidates[0].Count == encryptedCounters[0] && ballot.VerifyCounts() == nil
+		})
+
+		MockAssetKeys(mockStub, mockBallot.Type(), mockBallot.ElectionID, mockBallot.Asset.ID)
+		mockStub.On("GetState", mockBallot.Asset.ID).Return(mockBallotData, nil)
+		mockStub.On("CreateCompositeKey", "chaincode.Election", []string{mockBallot.ElectionID}).Return(mockBallot.ElectionID, nil)
+		mockStub.On("GetState", mockBallot.ElectionID).Return(mockElectionData, nil)
+		mockStub.On("GetTxTimestamp").Return(timestamppb.New(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)), nil)
+		mockStub.On("PutState", mockBallot.Asset.ID, votedBallot).Return(nil, nil)
+		// The event must not reveal the candidate voted for
+		mockStub.On("SetEvent", string(chaincode.EventVoteCast), []byte(`{"BallotID":"b-0"}`)).Return(nil)
+
+		// Test
+		err = smartContract.CastEncryptedVote(mockCtx, mockBallot.Asset.ID, encryptedCounters, proofs)
+		require.NoError(t, err)
+		mockStub.AssertExpectations(t)
+	})
+
+	t.Run("successfully cast encrypted vote on packed ballot", func(t *testing.T) {
+		// Mocks
+		mockStub := &mocks.ChaincodeStubInterface{}
+		mockCtx := &mocks.TransactionContextInterface{}
+
+		mockCtx.On("GetStub").Return(mockStub)
+		mockCtx.On("GetClientIdentity").Return(MockClientIdentity("v-0", chaincode.RoleVoter))
+
+		mockBallot, _, mockElectionData := MockOpenBallot(t, "v-0")
+		mockBallot.PackingBase = "100"
+		if err := mockBallot.Init(MockRandomness()); err != nil {
+			t.Error(err)
+		}
+		mockBallotData, err := json.Marshal(mockBallot)
+		if err != nil {
+			t.Error(err)
+		}
+
+		encryptedCounters, proofs, err := mockBallot.EncryptVote(MockRandomness(), "c-0")
+		require.NoError(t, err)
+		require.Len(t, proofs, 2)
+
+		votedBallot := mock.MatchedBy(func(data []byte) bool {
+			var ballot chaincode.Ballot
+			if err := json.Unmarshal(data, &ballot); err != nil {
+				return false
+			}
+
+			return ballot.Voted && ballot.PackedCount == encryptedCounters[0] && ballot.VerifyCounts() == nil
+		})
+
+		MockAssetKeys(mockStub, mockBallot.Type(), mockBallot.ElectionID, mockBallot.Asset.ID)
+		mockStub.On("GetState", mockBallot.Asset.ID).Return(mockBallotData, nil)
+		mockStub.On("CreateCompositeKey", "chaincode.Election", []string{mockBallot.ElectionID}).Return(mockBallot.ElectionID, nil)
+		mockStub.On("GetState", mockBallot.ElectionID).Return(mockElectionData, nil)
+		mockStub.On("GetTxTimestamp").Return(timestamppb.New(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)), nil)
+		mockStub.On("PutState", mockBallot.Asset.ID, votedBallot).Return(nil, nil)
+		mockStub.On("SetEvent", string(chaincode.EventVoteCast), []byte(`{"BallotID":"b-0"}`)).Return(nil)
+
+		// Test
+		err = smartContract.CastEncryptedVote(mockCtx, mockBallot.Asset.ID, encryptedCounters, proofs)
+		require.NoError(t, err)
+		mockStub.AssertExpectations(t)
+	})
+
+	t.Run("fail to cast encrypted vote with mismatched proofs", func(t *testing.T) {
+		// Mocks
+		mockStub := &mocks.ChaincodeStubInterface{}
+		mockCtx := &mocks.TransactionContextInterface{}
+
+		mockCtx.On("GetStub").Return(mockStub)
+		mockCtx.On("GetClientIdentity").Return(MockClientIdentity("v-0", chaincode.RoleVoter))
+
+		mockBallot, mockBallotData, mockElectionData := MockOpenBallot(t, "v-0")
+
+		// The counts of one encryption are cast with the proofs of another
+		encryptedCounters, _, err := mockBallot.EncryptVote(MockRandomness(), "c-0")
+		require.NoError(t, err)
+		_, proofs, err := mockBallot.EncryptVote(rand.Reader, "c-0")
+		require.NoError(t, err)
+
+		MockAssetKeys(mockStub, mockBallot.Type(), mockBallot.ElectionID, mockBallot.Asset.ID)
+		mockStub.On("GetState", mockBallot.Asset.ID).Return(mockBallotData, nil)
+		mockStub.On("CreateCompositeKey", "chaincode.Election", []string{mockBallot.ElectionID}).Return(mockBallot.ElectionID, nil)
+		mockStub.On("GetState", mockBallot.ElectionID).Return(mockElectionData, nil)
+		mockStub.On("GetTxTimestamp").Return(timestamppb.New(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)), nil)
+
+		// Test
+		err = smartContract.CastEncryptedVote(mockCtx, mockBallot.Asset.ID, encryptedCounters, proofs)
+		require.ErrorContains(t, err, "encrypted vote for ballot b-0 is invalid")
+		mockStub.AssertNotCalled(t, "PutState", mock.Anything, mock.Anything)
+	})
+
+	t.Run("fail to cast encrypted vote with out-of-range counts & forged proofs", func(t *testing.T) {
+		// Mocks
+		mockStub := &mocks.ChaincodeStubInterface{}
+		mockCtx := &mocks.TransactionContextInterface{}
+
+		mockCtx.On("GetStub").Return(mockStub)
+		mockCtx.On("GetClientIdentity").Return(MockClientIdentity("v-0", chaincode.RoleVoter))
+
+		mockBallot, _, mockElectionData := MockOpenBallot(t, "v-0")
+		otherMockCandidate := mockBallot.Candidates[0]
+		otherMockCandidate.Asset.ID = "c-1"
+		mockBallot.Candidates = append(mockBallot.Candidates, otherMockCandidate)
+		if err := mockBallot.Init(MockRandomness()); err != nil {
+			t.Error(err)
+		}
+		mockBallotData, err := json.Marshal(mockBallot)
+		if err != nil {
+			t.Error(err)
+		}
+
+		publicKey, err := paillier.Base64Decode[paillier.PublicKey](otherMockCandidate.PublicKey)
+		if err != nil {
+			t.Error(err)
+		}
+
+		// 1000 votes for c-0 & -999 votes for c-1 sum to 1, so only the binary proofs of the counts are forged
+		values := []*big.Int{big.NewInt(1000), new(big.Int).Sub(publicKey.N, big.NewInt(999))}
+
+		encryptedCounters := []string{}
+		counts := []*big.Int{}
+		randomness := []*big.Int{}
+		binaryProofs := []string{}
+		knowledgeProofs := []string{}
+
+		for _, value := range values {
+			count, r, err := paillier.EncryptWithRandomness(publicKey, value)
+			require.NoError(t, err)
+
+			binaryProof, err := paillier.Base64Encode(MockForgedBinaryProof(t, publicKey, count))
+			require.NoError(t, err)
+
+			knowledgeProof, err := paillier.ProvePlaintextKnowledge(publicKey, count, value, r, mockBallot.Asset.ID)
+			require.NoError(t, err)
+			knowledgeProofData, err := paillier.Base64Encode(knowledgeProof)
+			require.NoError(t, err)
+
+			encryptedCounters = append(encryptedCounters, count.String())
+			counts = append(counts, count)
+			randomness = append(randomness, r)
+			binaryProofs = append(binaryProofs, binaryProof)
+			knowledgeProofs = append(knowledgeProofs, knowledgeProofData)
+		}
+
+		sumProof, err := paillier.ProveSumEqualsOne(publicKey, counts, randomness)
+		require.NoError(t, err)
+		sumProofData, err := paillier.Base64Encode(sumProof)
+		require.NoError(t, err)
+
+		proofs := append(append(binaryProofs, sumProofData), knowledgeProofs...)
+
+		MockAssetKeys(mockStub, mockBallot.Type(), mockBallot.ElectionID, mockBallot.Asset.ID)
+		mockStub.On("GetState", mockBallot.Asset.ID).Return(mockBallotData, nil)
+		mockStub.On("CreateCompositeKey", "chaincode.Election", []string{mockBallot.ElectionID}).Return(mockBallot.ElectionID, nil)
+		mockStub.On("GetState", mockBallot.ElectionID).Return(mockElectionData, nil)
+		mockStub.On("GetTxTimestamp").Return(timestamppb.New(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)), nil)
+
+		// Test
+		err = smartContract.CastEncryptedVote(mockCtx, mockBallot.Asset.ID, encryptedCounters, proofs)
+		require.EqualError(t, err, "encrypted vote for ballot b-0 is invalid: candidate c-0 count is not well-formed")
+		mockStub.AssertNotCalled(t, "PutState", mock.Anything, mock.Anything)
+	})
+
+	t.Run("fail to cast encrypted vote copied from another ballot", func(t *testing.T) {
+		// Mocks
+		mockStub := &mocks.ChaincodeStubInterface{}
+		mockCtx := &mocks.TransactionContextInterface{}
+
+		mockCtx.On("GetStub").Return(mockStub)
+		mockCtx.On("GetClientIdentity").Return(MockClientIdentity("v-0", chaincode.RoleVoter))
+
+		mockBallot, mockBallotData, mockElectionData := MockOpenBallot(t, "v-0")
+
+		// The well-formed counts & proofs of another voter's ballot are cast on this ballot
+		otherMockBallot := *mockBallot
+		otherMockBallot.Asset.ID = "b-1"
+		encryptedCounters, proofs, err := otherMockBallot.EncryptVote(MockRandomness(), "c-0")
+		require.NoError(t, err)
+
+		MockAssetKeys(mockStub, mockBallot.Type(), mockBallot.ElectionID, mockBallot.Asset.ID)
+		mockStub.On("GetState", mockBallot.Asset.ID).Return(mockBallotData, nil)
+		mockStub.On("CreateCompositeKey", "chaincode.Election", []string{mockBallot.ElectionID}).Return(mockBallot.ElectionID, nil)
+		mockStub.On("GetState", mockBallot.ElectionID).Return(mockElectionData, nil)
+		mockStub.On("GetTxTimestamp").Return(timestamppb.New(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)), nil)
+
+		// Test
+		err = smartContract.CastEncryptedVote(mockCtx, mockBallot.Asset.ID, encryptedCounters, proofs)
+		require.EqualError(t, err, "encrypted vote for ballot b-0 is invalid: encrypted count 0 is not bound to ballot b-0")
+		mockStub.AssertNotCalled(t, "PutState", mock.Anything, mock.Anything)
+	})
+}
+
+func TestAccessControl(t *testing.T) {
+	smartContract := chaincode.SmartContract{}
+
+	t.Run("fail to create election without election-admin role", func(t *testing.T) {
+		// Mocks
+		mockStub := &mocks.ChaincodeStubInterface{}
+		mockCtx := &mocks.TransactionContextInterface{}
+
+		mockCtx.On("GetStub").Return(mockStub)
+		mockCtx.On("GetClientIdentity").Return(MockClientIdentity("mockRegistrar", chaincode.RoleRegistrar, chaincode.RoleVoter))
+
+		_, mockElectionData := MockElection()
+
+		// Test
+		expectedError := &chaincode.AccessDeniedError{chaincode.MSPQualifiedID(mockMSPID, "mockRegistrar"), "CreateElection", []chaincode.Role{chaincode.RoleElectionAdmin}}
+
+		err := smartContract.CreateElection(mockCtx, string(mockElectionData))
+		require.EqualError(t, err, expectedError.Error())
+		mockStub.AssertNotCalled(t, "PutState", mock.Anything, mock.Anything)
+	})
+
+	t.Run("fail to set up performance test without roles", func(t *testing.T) {
+		// Mocks
+		mockStub := &mocks.ChaincodeStubInterface{}
+		mockCtx := &mocks.TransactionContextInterface{}
+
+		mockCtx.On("GetStub").Return(mockStub)
+		mockCtx.On("GetClientIdentity").Return(MockClientIdentity("v-0"))
+
+		// Test
+		_, err := smartContract.SetupPerformanceTestElection(mockCtx, "v-0", 1, 1)
+
+		var accessDeniedError *chaincode.AccessDeniedError
+		require.ErrorAs(t, err, &accessDeniedError)
+		require.Equal(t, chaincode.MSPQualifiedID(mockMSPID, "v-0"), accessDeniedError.ClientID)
+	})
+
+	t.Run("fail to cast vote on ballot of another voter", func(t *testing.T) {
+		// Mocks
+		mockStub := &mocks.ChaincodeStubInterface{}
+		mockCtx := &mocks.TransactionContextInterface{}
+
+		mockCtx.On("GetStub").Return(mockStub)
+		mockCtx.On("GetClientIdentity").Return(MockClientIdentity("v-0", chaincode.RoleVoter))
+
+		mockBallot, _ := MockBallot()
+		mockBallot.VoterID = chaincode.MSPQualifiedID(mockMSPID, "v-1")
+		mockBallotData, err := json.Marshal(mockBallot)
+		if err != nil {
+			t.Error(err)
+		}
+
+		MockAssetKeys(mockStub, mockBallot.Type(), mockBallot.ElectionID, mockBallot.Asset.ID)
+		mockStub.On("GetState", mockBallot.Asset.ID).Return(mockBallotData, nil)
+
+		// Test
+		err = smartContract.CastVote(mockCtx, mockBallot.Asset.ID, "c-0")
+		require.EqualError(t, err, fmt.Sprintf("voter %s is not assigned ballot b-0!", chaincode.MSPQualifiedID(mockMSPID, "v-0")))
+	})
+
+	t.Run("fail to cast vote on ballot of voter with the same enrollment ID in another MSP", func(t *testing.T) {
+		// Mocks
+		mockStub := &mocks.ChaincodeStubInterface{}
+		mockCtx := &mocks.TransactionContextInterface{}
+		mockIdentity := &mocks.ClientIdentity{}
+
+		mockIdentity.On("GetMSPID").Return("Org2MSP", nil)
+		mockIdentity.On("GetAttributeValue", chaincode.RoleAttribute).Return(string(chaincode.RoleVoter), true, nil)
+		mockIdentity.On("GetAttributeValue", chaincode.EnrollmentIDAttribute).Return("v-0", true, nil)
+
+		mockCtx.On("GetStub").Return(mockStub)
+		mockCtx.On("GetClientIdentity").Return(mockIdentity)
+
+		mockBallot, _ := MockBallot()
+		mockBallot.VoterID = chaincode.MSPQualifiedID(mockMSPID, "v-0")
+		mockBallotData, err := json.Marshal(mockBallot)
+		if err != nil {
+			t.Error(err)
+		}
+
+		MockAssetKeys(mockStub, mockBallot.Type(), mockBallot.ElectionID, mockBallot.Asset.ID)
+		mockStub.On("GetState", mockBallot.Asset.ID).Return(mockBallotData, nil)
+
+		// Test
+		err = smartContract.CastVote(mockCtx, mockBallot.Asset.ID, "c-0")
+		require.EqualError(t, err, "voter Org2MSP:v-0 is not assigned ballot b-0!")
+	})
+}
+
+func TestElectionLifecycle(t *testing.T) {
+	smartContract := chaincode.SmartContract{}
+
+	t.Run("successfully open draft election", func(t *testing.T) {
+		// Mocks
+		mockStub := &mocks.ChaincodeStubInterface{}
+		mockCtx := &mocks.TransactionContextInterface{}
+
+		mockCtx.On("GetStub").Return(mockStub)
+		mockCtx.On("GetClientIdentity").Return(MockClientIdentity("mockAdmin", chaincode.RoleElectionAdmin))
+
+		mockElection, _ := MockElection()
+		mockElection.Candidates = []string{"c-0"}
+		mockElectionData, err := json.Marshal(mockElection)
+		if err != nil {
+			t.Error(err)
+		}
+
+		openStatus := mock.MatchedBy(func(data []byte) bool {
+			var election chaincode.Election
+			if err := json.Unmarshal(data, &election); err != nil {
+				return false
+			}
+
+			return election.Status == chaincode.StatusOpen
+		})
 
 		mockStub.On("CreateCompositeKey", mockElection.Type(), []string{mockElection.Asset.ID}).Return(mockElection.Asset.ID, nil)
 		mockStub.On("GetState", mockElection.Asset.ID).Return(mockElectionData, nil)
-		mockStub.On("PutState", mockElection.Asset.ID, mock.AnythingOfType("[]uint8")).Return(nil, nil)
+		mockStub.On("PutState", mockElection.Asset.ID, openStatus).Return(nil, nil)
 
 		// Test
-		mockElection.Name = "mockElection1"
-		updatedMockElectionData, err := json.Marshal(mockElection)
+		err = smartContract.OpenElection(mockCtx, mockElection.Asset.ID)
+		require.NoError(t, err)
+		mockStub.AssertExpectations(t)
+	})
+
+	t.Run("successfully close open election", func(t *testing.T) {
+		// Mocks
+		mockStub := &mocks.ChaincodeStubInterface{}
+		mockCtx := &mocks.TransactionContextInterface{}
+
+		mockCtx.On("GetStub").Return(mockStub)
+		mockCtx.On("GetClientIdentity").Return(MockClientIdentity("mockAdmin", chaincode.RoleElectionAdmin))
+
+		mockElection, _ := MockElection()
+		mockElection.Status = chaincode.StatusOpen
+		mockElectionData, err := json.Marshal(mockElection)
 		if err != nil {
 			t.Error(err)
 		}
 
-		err = smartContract.UpdateElection(mockCtx, string(updatedMockElectionData))
+		closedStatus := mock.MatchedBy(func(data []byte) bool {
+			var election chaincode.Election
+			if err := json.Unmarshal(data, &election); err != nil {
+				return false
+			}
+
+			return election.Status == chaincode.StatusClosed
+		})
+
+		mockStub.On("CreateCompositeKey", mockElection.Type(), []string{mockElection.Asset.ID}).Return(mockElection.Asset.ID, nil)
+		mockStub.On("GetState", mockElection.Asset.ID).Return(mockElectionData, nil)
+		mockStub.On("PutState", mockElection.Asset.ID, closedStatus).Return(nil, nil)
+		mockStub.On("SetEvent", string(chaincode.EventElectionClosed), []byte(`{"ElectionID":"e-0"}`)).Return(nil)
+
+		// Test
+		err = smartContract.CloseElection(mockCtx, mockElection.Asset.ID)
 		require.NoError(t, err)
+		mockStub.AssertExpectations(t)
 	})
 
-	t.Run("fail to update identical election data", func(t *testing.T) {
+	t.Run("fail to open election without candidates", func(t *testing.T) {
 		// Mocks
 		mockStub := &mocks.ChaincodeStubInterface{}
 		mockCtx := &mocks.TransactionContextInterface{}
 
 		mockCtx.On("GetStub").Return(mockStub)
+		mockCtx.On("GetClientIdentity").Return(MockClientIdentity("mockAdmin", chaincode.RoleElectionAdmin))
 
 		mockElection, mockElectionData := MockElection()
 
@@ -541,56 +1702,313 @@ func TestUpdateElection(t *testing.T) {
 		mockStub.On("GetState", mockElection.Asset.ID).Return(mockElectionData, nil)
 
 		// Test
-		expectedError := chaincode.ObjectEqualityError{mockElection.Asset.ID, mockElection.Type()}
-
-		err := smartContract.UpdateElection(mockCtx, string(mockElectionData))
-		require.EqualError(t, err, expectedError.Error())
+		err := smartContract.OpenElection(mockCtx, mockElection.Asset.ID)
+		require.EqualError(t, err, "unable to open election e-0 without candidates")
 	})
 
-	t.Run("fail to update non-existent Election", func(t *testing.T) {
+	t.Run("fail to skip election status", func(t *testing.T) {
 		// Mocks
 		mockStub := &mocks.ChaincodeStubInterface{}
 		mockCtx := &mocks.TransactionContextInterface{}
 
 		mockCtx.On("GetStub").Return(mockStub)
+		mockCtx.On("GetClientIdentity").Return(MockClientIdentity("mockAdmin", chaincode.RoleElectionAdmin))
 
 		mockElection, mockElectionData := MockElection()
 
 		mockStub.On("CreateCompositeKey", mockElection.Type(), []string{mockElection.Asset.ID}).Return(mockElection.Asset.ID, nil)
-		mockStub.On("GetState", mockElection.Asset.ID).Return(nil, nil)
+		mockStub.On("GetState", mockElection.Asset.ID).Return(mockElectionData, nil)
 
 		// Test
-		expectedError := fmt.Sprintf("cannot read world state with key %s", mockElection.Asset.ID)
+		expectedError := &chaincode.ElectionStatusError{mockElection.Asset.ID, chaincode.StatusDraft, "change status to Tallied"}
 
-		err := smartContract.UpdateElection(mockCtx, string(mockElectionData))
-		require.EqualError(t, err, expectedError)
+		err := smartContract.PublishTally(mockCtx, mockElection.Asset.ID)
+		require.EqualError(t, err, expectedError.Error())
 	})
 
-	t.Run("fail to invalidate existing Election", func(t *testing.T) {
+	t.Run("fail to change election status with update", func(t *testing.T) {
 		// Mocks
 		mockStub := &mocks.ChaincodeStubInterface{}
 		mockCtx := &mocks.TransactionContextInterface{}
 
 		mockCtx.On("GetStub").Return(mockStub)
+		mockCtx.On("GetClientIdentity").Return(MockClientIdentity("mockAdmin", chaincode.RoleElectionAdmin))
 
 		mockElection, mockElectionData := MockElection()
 
 		mockStub.On("CreateCompositeKey", mockElection.Type(), []string{mockElection.Asset.ID}).Return(mockElection.Asset.ID, nil)
 		mockStub.On("GetState", mockElection.Asset.ID).Return(mockElectionData, nil)
 
-		// Modify election for fail case
-		mockElection.StartTime = "2024-01-02 00:00:00"
+		// Test
+		mockElection.Status = chaincode.StatusClosed
 		updatedMockElectionData, err := json.Marshal(mockElection)
 		if err != nil {
 			t.Error(err)
 		}
 
+		err = smartContract.UpdateElection(mockCtx, string(updatedMockElectionData))
+		require.EqualError(t, err, "unable to change the status of election e-0! status can only be changed by its transitions")
+	})
+
+	t.Run("fail to create candidate for open election", func(t *testing.T) {
+		// Mocks
+		mockStub := &mocks.ChaincodeStubInterface{}
+		mockCtx := &mocks.TransactionContextInterface{}
+
+		mockCtx.On("GetStub").Return(mockStub)
+		mockCtx.On("GetClientIdentity").Return(MockClientIdentity("mockAdmin", chaincode.RoleElectionAdmin))
+
+		_, mockCandidateData := MockCandidate()
+		mockElection, _ := MockElection()
+		mockElection.Status = chaincode.StatusOpen
+		mockElectionData, err := json.Marshal(mockElection)
+		if err != nil {
+			t.Error(err)
+		}
+
+		mockStub.On("CreateCompositeKey", mockElection.Type(), []string{mockElection.Asset.ID}).Return(mockElection.Asset.ID, nil)
+		mockStub.On("GetState", mockElection.Asset.ID).Return(mockElectionData, nil)
+
 		// Test
-		expectedError := &chaincode.ObjectValidationError{"EndTime must be after StartTime", mockElection.Type()}
+		expectedError := &chaincode.ElectionStatusError{mockElection.Asset.ID, chaincode.StatusOpen, "create candidates"}
 
-		err = smartContract.UpdateElection(mockCtx, string(updatedMockElectionData))
+		err = smartContract.CreateCandidate(mockCtx, string(mockCandidateData))
+		require.EqualError(t, err, expectedError.Error())
+	})
+
+	t.Run("fail to update ballot for closed election", func(t *testing.T) {
+		// Mocks
+		mockStub := &mocks.ChaincodeStubInterface{}
+		mockCtx := &mocks.TransactionContextInterface{}
+
+		mockCtx.On("GetStub").Return(mockStub)
+		mockCtx.On("GetClientIdentity").Return(MockClientIdentity("mockRegistrar", chaincode.RoleRegistrar))
+
+		mockBallot, mockBallotData := MockBallot()
+		mockElection, _ := MockElection()
+		mockElection.Status = chaincode.StatusClosed
+		mockElectionData, err := json.Marshal(mockElection)
+		if err != nil {
+			t.Error(err)
+		}
+
+		MockAssetKeys(mockStub, mockBallot.Type(), mockBallot.ElectionID, mockBallot.Asset.ID)
+		mockStub.On("GetState", mockBallot.Asset.ID).Return(mockBallotData, nil)
+		mockStub.On("CreateCompositeKey", mockElection.Type(), []string{mockElection.Asset.ID}).Return(mockElection.Asset.ID, nil)
+		mockStub.On("GetState", mockElection.Asset.ID).Return(mockElectionData, nil)
+
+		// Test
+		mockBallot.VoterID = "v-0"
+		updatedMockBallotData, err := json.Marshal(mockBallot)
+		if err != nil {
+			t.Error(err)
+		}
+
+		expectedError := &chaincode.ElectionStatusError{mockElection.Asset.ID, chaincode.StatusClosed, "update ballots"}
+
+		err = smartContract.UpdateBallot(mockCtx, string(updatedMockBallotData))
+		require.EqualError(t, err, expectedError.Error())
+	})
+}
+
+func TestComputeTally(t *testing.T) {
+	smartContract := chaincode.SmartContract{}
+
+	t.Run("successfully compute tally of closed election", func(t *testing.T) {
+		// Mocks
+		mockStub := &mocks.ChaincodeStubInterface{}
+		mockCtx := &mocks.TransactionContextInterface{}
+		mockIterator := &mocks.StateQueryIteratorInterface{}
+
+		mockCtx.On("GetStub").Return(mockStub)
+		mockCtx.On("GetClientIdentity").Return(MockClientIdentity("mockAdmin", chaincode.RoleElectionAdmin))
+
+		mockCandidate, mockCandidateData := MockCandidate()
+		mockElection, _ := MockElection()
+		mockElection.Candidates = []string{mockCandidate.Asset.ID}
+		mockElection.Status = chaincode.StatusClosed
+		mockElectionData, err := json.Marshal(mockElection)
+		if err != nil {
+			t.Error(err)
+		}
+
+		// Only voted ballots are tallied
+		votedBallot := MockVotedBallot(t, "b-0", mockElection.Asset.ID, *mockCandidate)
+		unvotedBallot := MockVotedBallot(t, "b-1", mockElection.Asset.ID, *mockCandidate)
+		unvotedBallot.Voted = false
+
+		for _, ballot := range []chaincode.Ballot{votedBallot, unvotedBallot} {
+			ballotData, err := json.Marshal(ballot)
+			if err != nil {
+				t.Error(err)
+			}
+
+			mockIterator.On("HasNext").Return(true).Once()
+			mockIterator.On("Next").Return(&queryresult.KV{Key: ballot.Asset.ID, Value: ballotData}, nil).Once()
+		}
+		mockIterator.On("HasNext").Return(false)
+		mockIterator.On("Close").Return(nil)
+
+		publicKey, err := paillier.Base64Decode[paillier.PublicKey](mockCandidate.PublicKey)
+		if err != nil {
+			t.Error(err)
+		}
+		votedCount, _ := new(big.Int).SetString(votedBallot.Candidates[0].Count, 10)
+		expectedCount := paillier.AddEncrypted(publicKey, big.NewInt(1), votedCount).String()
+
+		expectedTally := mock.MatchedBy(func(data []byte) bool {
+			var tally chaincode.Tally
+			if err := json.Unmarshal(data, &tally); err != nil {
+				return false
+			}
+
+			return tally.NumBallots == 1 && len(tally.Counts) == 1 && tally.Counts[0].Count == expectedCount
+		})
+
+		mockStub.On("CreateCompositeKey", mockElection.Type(), []string{mockElection.Asset.ID}).Return(mockElection.Asset.ID, nil)
+		mockStub.On("GetState", mockElection.Asset.ID).Return(mockElectionData, nil)
+		MockAssetKeys(mockStub, mockCandidate.Type(), mockCandidate.ElectionID, mockCandidate.Asset.ID)
+		mockStub.On("GetState", mockCandidate.Asset.ID).Return(mockCandidateData, nil)
+		mockStub.On("GetStateByPartialCompositeKey", votedBallot.Type(), []string{mockElection.Asset.ID}).Return(mockIterator, nil)
+		mockStub.On("CreateCompositeKey", chaincode.Tally{}.Type(), []string{mockElection.Asset.ID}).Return("t-"+mockElection.Asset.ID, nil)
+		mockStub.On("GetState", "t-"+mockElection.Asset.ID).Return(nil, nil)
+		mockStub.On("PutState", "t-"+mockElection.Asset.ID, expectedTally).Return(nil, nil)
+
+		// Test
+		err = smartContract.ComputeTally(mockCtx, mockElection.Asset.ID)
+		require.NoError(t, err)
+		mockStub.AssertExpectations(t)
+	})
+
+	t.Run("fail to compute tally of open election", func(t *testing.T) {
+		// Mocks
+		mockStub := &mocks.ChaincodeStubInterface{}
+		mockCtx := &mocks.TransactionContextInterface{}
+
+		mockCtx.On("GetStub").Return(mockStub)
+		mockCtx.On("GetClientIdentity").Return(MockClientIdentity("mockAdmin", chaincode.RoleElectionAdmin))
+
+		mockElection, _ := MockElection()
+		mockElection.Status = chaincode.StatusOpen
+		mockElectionData, err := json.Marshal(mockElection)
+		if err != nil {
+			t.Error(err)
+		}
+
+		mockStub.On("CreateCompositeKey", mockElection.Type(), []string{mockElection.Asset.ID}).Return(mockElection.Asset.ID, nil)
+		mockStub.On("GetState", mockElection.Asset.ID).Return(mockElectionData, nil)
+
+		// Test
+		expectedError := &chaincode.ElectionStatusError{mockElection.Asset.ID, chaincode.StatusOpen, "compute tally"}
+
+		err = smartContract.ComputeTally(mockCtx, mockElection.Asset.ID)
 		require.EqualError(t, err, expectedError.Error())
 	})
+
+	t.Run("fail to publish tally before it is computed", func(t *testing.T) {
+		// Mocks
+		mockStub := &mocks.ChaincodeStubInterface{}
+		mockCtx := &mocks.TransactionContextInterface{}
+
+		mockCtx.On("GetStub").Return(mockStub)
+		mockCtx.On("GetClientIdentity").Return(MockClientIdentity("mockAdmin", chaincode.RoleElectionAdmin))
+
+		mockElection, _ := MockElection()
+		mockElection.Status = chaincode.StatusClosed
+		mockElectionData, err := json.Marshal(mockElection)
+		if err != nil {
+			t.Error(err)
+		}
+
+		mockStub.On("CreateCompositeKey", mockElection.Type(), []string{mockElection.Asset.ID}).Return(mockElection.Asset.ID, nil)
+		mockStub.On("GetState", mockElection.Asset.ID).Return(mockElectionData, nil)
+		mockStub.On("CreateCompositeKey", chaincode.Tally{}.Type(), []string{mockElection.Asset.ID}).Return("t-"+mockElection.Asset.ID, nil)
+		mockStub.On("GetState", "t-"+mockElection.Asset.ID).Return(nil, nil)
+
+		// Test
+		err = smartContract.PublishTally(mockCtx, mockElection.Asset.ID)
+		require.EqualError(t, err, "unable to publish tally of election e-0 before it is computed: cannot read world state with key e-0")
+		mockStub.AssertNotCalled(t, "PutState", mock.Anything, mock.Anything)
+	})
+}
+
+func TestMigrateAssetKeys(t *testing.T) {
+	smartContract := chaincode.SmartContract{}
+
+	t.Run("successfully re-key ballots under their election", func(t *testing.T) {
+		// Mocks
+		mockStub := &mocks.ChaincodeStubInterface{}
+		mockCtx := &mocks.TransactionContextInterface{}
+		mockBallotIterator := &mocks.StateQueryIteratorInterface{}
+		mockCandidateIterator := &mocks.StateQueryIteratorInterface{}
+
+		mockCtx.On("GetStub").Return(mockStub)
+		mockCtx.On("GetClientIdentity").Return(MockClientIdentity("mockAdmin", chaincode.RoleElectionAdmin))
+
+		mockBallot, mockBallotData := MockBallot()
+		mockCandidate, mockCandidateData := MockCandidate()
+
+		// The ballot is keyed by its ID alone, while the candidate is already keyed under its election
+		mockBallotIterator.On("HasNext").Return(true).Once()
+		mockBallotIterator.On("Next").Return(&queryresult.KV{Key: "legacy-" + mockBallot.Asset.ID, Value: mockBallotData}, nil).Once()
+		mockBallotIterator.On("HasNext").Return(false)
+		mockBallotIterator.On("Close").Return(nil)
+
+		mockCandidateIterator.On("HasNext").Return(true).Once()
+		mockCandidateIterator.On("Next").Return(&queryresult.KV{Key: mockCandidate.Asset.ID, Value: mockCandidateData}, nil).Once()
+		mockCandidateIterator.On("HasNext").Return(false)
+		mockCandidateIterator.On("Close").Return(nil)
+
+		mockStub.On("GetStateByPartialCompositeKey", mockBallot.Type(), []string{}).Return(mockBallotIterator, nil)
+		mockStub.On("GetStateByPartialCompositeKey", mockCandidate.Type(), []string{}).Return(mockCandidateIterator, nil)
+		mockStub.On("SplitCompositeKey", "legacy-"+mockBallot.Asset.ID).Return(mockBallot.Type(), []string{mockBallot.Asset.ID}, nil)
+		mockStub.On("SplitCompositeKey", mockCandidate.Asset.ID).Return(mockCandidate.Type(), []string{mockCandidate.ElectionID, mockCandidate.Asset.ID}, nil)
+		mockStub.On("DelState", "legacy-"+mockBallot.Asset.ID).Return(nil)
+		MockNewAssetKeys(mockStub, mockBallot.Type(), mockBallot.ElectionID, mockBallot.Asset.ID)
+		mockStub.On("GetState", mockBallot.Asset.ID).Return(nil, nil)
+		mockStub.On("PutState", mockBallot.Asset.ID, mockBallotData).Return(nil)
+
+		// Test
+		numMigrated, err := smartContract.MigrateAssetKeys(mockCtx)
+		require.NoError(t, err)
+		require.Equal(t, 1, numMigrated)
+		mockStub.AssertExpectations(t)
+	})
+}
+
+func TestElectionIsActive(t *testing.T) {
+	mockElection, _ := MockElection()
+
+	t.Run("successfully check election times", func(t *testing.T) {
+		active, err := mockElection.IsActive(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+		require.NoError(t, err)
+		require.True(t, active)
+
+		// 2024-01-01T07:59:59+08:00 is before the election starts at 2024-01-01T00:00:00Z
+		singapore := time.FixedZone("SGT", 8*60*60)
+
+		active, err = mockElection.IsActive(time.Date(2024, 1, 1, 7, 59, 59, 0, singapore))
+		require.NoError(t, err)
+		require.False(t, active)
+	})
+
+	t.Run("successfully check legacy election times", func(t *testing.T) {
+		legacyElection := *mockElection
+		legacyElection.StartTime = "2024-01-01 00:00:00"
+		legacyElection.EndTime = "2024-01-01 23:59:59"
+
+		active, err := legacyElection.IsActive(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+		require.NoError(t, err)
+		require.True(t, active)
+	})
+
+	t.Run("fail to check invalid election times", func(t *testing.T) {
+		invalidElection := *mockElection
+		invalidElection.EndTime = "error"
+
+		_, err := invalidElection.IsActive(time.Now())
+		require.EqualError(t, err, "election e-0 EndTime \"error\" must be an RFC 3339 time, e.g. 2006-01-02T15:04:05Z07:00")
+	})
 }
 
 // =============================================================================
@@ -616,6 +2034,103 @@ func MockBallot() (*chaincode.Ballot, []byte) {
 	return &mock, mockData
 }
 
+// Ballot with a vote cast for the first candidate
+func MockVotedBallot(t *testing.T, id, electionID string, candidates ...chaincode.Candidate) chaincode.Ballot {
+	ballot := chaincode.Ballot{
+		Asset:      chaincode.Asset{ID: id},
+		Candidates: candidates,
+		ElectionID: electionID,
+		VoterID:    "v-" + id,
+	}
+
+	random := MockRandomness()
+	if err := ballot.Init(random); err != nil {
+		t.Fatal(err)
+	}
+	if err := ballot.Vote(random, candidates[0].Asset.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	return ballot
+}
+
+// Ballot assigned to voterID enrolled by mockMSPID with a single candidate, for an election that is Open at 2024-01-01T12:00:00Z
+func MockOpenBallot(t *testing.T, voterID string) (*chaincode.Ballot, []byte, []byte) {
+	mockCandidate, _ := MockCandidate()
+	mockElection, _ := MockElection()
+	mockElection.Candidates = []string{mockCandidate.Asset.ID}
+	mockElection.Status = chaincode.StatusOpen
+
+	mockElectionData, err := json.Marshal(mockElection)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mockBallot, _ := MockBallot()
+	mockBallot.Candidates = []chaincode.Candidate{*mockCandidate}
+	mockBallot.VoterID = chaincode.MSPQualifiedID(mockMSPID, voterID)
+	if err = mockBallot.Init(MockRandomness()); err != nil {
+		t.Fatal(err)
+	}
+
+	mockBallotData, err := json.Marshal(mockBallot)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return mockBallot, mockBallotData, mockElectionData
+}
+
+// Deterministic randomness, such that mock objects are identical on every run
+func MockRandomness() io.Reader {
+	random, err := paillier.NewDRBG([]byte("mockRandomnessSeed"))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return random
+}
+
+// Binary proof that ciphertext encrypts 0 or 1, forged without knowing its plaintext by answering the 0 branch with
+// a challenge that is a multiple of n, which makes any ciphertext raised to it an n-th power
+func MockForgedBinaryProof(t *testing.T, publicKey *paillier.PublicKey, ciphertext *big.Int) *paillier.BinaryProof {
+	modulus := new(big.Int).Lsh(big.NewInt(1), uint(min(256, publicKey.N.BitLen()/2-1)))
+
+	// Simulate the 1 branch, where u_1 = c * g^-1
+	u1 := paillier.AddEncryptedWithPlain(publicKey, ciphertext, new(big.Int).Sub(publicKey.N, big.NewInt(1)))
+	e1 := big.NewInt(1)
+	z1 := big.NewInt(2)
+	a1 := new(big.Int).Exp(z1, publicKey.N, publicKey.NSquare)
+	a1.Mul(a1, new(big.Int).ModInverse(new(big.Int).Exp(u1, e1, publicKey.NSquare), publicKey.NSquare)).Mod(a1, publicKey.NSquare)
+
+	rho := big.NewInt(3)
+	a0 := new(big.Int).Exp(rho, publicKey.N, publicKey.NSquare)
+
+	// Fiat-Shamir challenge as computed by the verifier, i.e. the SHA-256 hash of the length-prefixed values % 2^t
+	hash := sha256.New()
+	for _, v := range []*big.Int{publicKey.N, ciphertext, a0, a1} {
+		var length [8]byte
+		binary.BigEndian.PutUint64(length[:], uint64(len(v.Bytes())))
+		hash.Write(length[:])
+		hash.Write(v.Bytes())
+	}
+	challenge := new(big.Int).SetBytes(hash.Sum(nil))
+	challenge.Mod(challenge, modulus)
+
+	// e_0 = n * k with n * k = e - e_1 % 2^t, so z_0 = rho * c^k % n
+	k := new(big.Int).Sub(challenge, e1)
+	k.Mul(k, new(big.Int).ModInverse(publicKey.N, modulus)).Mod(k, modulus)
+
+	e0 := new(big.Int).Mul(publicKey.N, k)
+	z0 := new(big.Int).Exp(ciphertext, k, publicKey.N)
+	z0.Mul(z0, rho).Mod(z0, publicKey.N)
+
+	return &paillier.BinaryProof{A0: a0, A1: a1, E0: e0, E1: e1, Z0: z0, Z1: z1}
+}
+
+// Base64 encoded public key of MockCandidate, of chaincode.MinPublicKeyBits in size
+const mockPublicKey = "eyJOIjoxODg5MzE3NTEzMzc1ODQ4NTg2MjE3NTg4MDk2MjQxMzE0NDg4NjIwMDQ5ODIxNjI3MDE0MDI4NDI1MDg4OTYwNjg5MTc0NDc1MDYwMDI5Mjg2OTE5OTI0NTMxMDYzOTYxNDMxMTEyOTMzOTM1MTAxMDIzMTQ5NjY0MjQzMTk4MzQzMDkxNjA1NDM4NjgyMjU4NjM3NjYxOTg0NTYzODc5MTg5OTA5NzczNDU0NzM5MDg1OTY1MDU3NjY1OTE5MzE1MjQyMTU2ODMxOTc2MDA2MDYyMjM4NTA1NzEwMTAyOTU2Mjk0NTU5MjI3NzY2NTI5OTgyOTY1MzQ2NTY0MTQzNjA3Nzg0OTYyNTk2OTU2NDg3MzI2NDE2OTUyMTE0Njc5MzQ2MTc4OTA4NzIwMjg1MjIxNTU0NjMwMDA1NzMxMjMwNTAzOTUzMjE3MTU1OTc0OTA3NjI0MDkxODkzNTM1MzQ0MjgwOTY2MTE4MTQwMzYyMTExMjIyMDE3Nzc5NzA1MDQyMzE0NDg1OTA0MjMxNDY5MzI5NTI3NDc1Njk3OTg3MjUxODIxMzQxMDk2NjYxNTgwNTM0MjgxNTQ0Mjk1MTk1MTYyMzI1NTAyNDAxNjAxODYwNjEwMTI1MDM1NTk3MTY3NTI4NTY4MTE0OTIyMjkzMTU2Nzk4NjIxMTU4MTgyNjU5NjcwNTgyNDA0MjAxMDk5MzI4NzQ3NzQwNzkzNDYyMTYwMDk0MzEyODQyNDA0NTg4NDM1NTAxNzQ2MjMxNDgzMTQ4MTQyMjc0NDYzNjg4ODg0ODIyNjQyMzYyMzU5MzYwNTI2OSwiTlNxdWFyZSI6MzU2OTUyMDY2NjM0ODY5OTgwMTQ5NTYyMTM5NzQ1OTg0OTYyNzYzMzgwMzE3MjE0OTEyNDY4NTMwNjg2NDk0NjY0MTM5ODEyMDE3MTM2MTkyNzAwODcxOTE3MDYyODQxMzg1Mzg1NTEyNzUzNDI5MTY2Mjc0Njc3MDc2MjE1ODU5NDI2ODM3MDcyODg0MjM4OTc5MTY3ODQ4Nzg4NjIwNDA5NzIzMzk0OTAwNDU3MDQyNDg5NjA1MDQ3NzMyOTUxODg1MTk0NTU3MTcwNjk2NDY0ODg1MDU5MDM1OTQxNDMyMjE3ODQ4NTE0NTQ5ODA0NjMyMDExODgxMzk1NjE1NDIzMjAyOTQyMzExNjQ1NTcyNDQzMDE2MDIxODE1MTM3ODk3ODU3MzQyMzk3NTk4MDk3NjkyNTM3NDM0NzI1ODkwNzQ1NjM0OTI5MTI5MDk2NTY2NDg3MTUwNTU0NDIzNDI4MjEwODYwNDQ1OTY0ODQxNjk1NTA4NDc5NTQ4MTQwMzU1NDY5MDQxMjQyNDQ5NzI0MDQyOTc5MzYxODM4MjY5NjEyMTk5NTM5ODcxNzMyNjgxMTc4Mzc1NzY2Mzg2MTU3NzY3NDY1NjA3MzIzNzMzNTQ5MjkyMjg1OTI0ODk4NTMwMjU5NzgwOTk0NjcxNDAyNDkwMjc0MjEzMjcyMDIyNTcxODI5NDM3MTcxNDcxMzE0ODE5MjMyNzM3NDY1MjQwMjI4Mjc4NjU3Mjk0NzI1MzEyMzM1MTkyMzgxMDk3NDg5NjYzOTk2NDUzMTQ3MzY1Mjk0NzY5MDY0MTQ4MDYxMDQzOTk1NDYwODg1MTgxNDg4Njc3NTQyNjIyNjgxMTk4MzA3MDQ0OTc3MDE5OTg2OTYzMjIwMjMwMDc0MTc4NTA1NzM2OTUwMjYyMTIxNTQ3MjIyNTUxNzQ2MjEwODYwNDg1OTA4ODEyMDMyNTM3MTMwODI5MDg4NzEwMjY4NTk3MzU3NDY3NTYzMTE5MDU1NDUyMjQ2OTg2MzAxMjY2MjY2NzI4NjIxMTk5NDE4MzYzOTQyNTk4MzQ0MzQ4MjY2MDQ5Mzc4NDY4ODE0NDQxMDk2NzM0NzcyNzc3NDM2MTk2MDkzMjc5MTIzNDQ4OTkwMDQ5NTg5MTk5NTY1MDYwNjQ3MTUyOTIwMjk2OTgxNTAzNDIwODQ2Mjc3MjM1NzkwNTMwMTU0OTIxOTY1MjI0ODY2MjIwOTk4OTI3MjU0NzM3ODU0MjgxNDgxODQzNzk0NTc2MTA0OTg3ODU2MTEzNTc1NjQwNTAwODg5MDI4MzA0NzkzNDA0NDUxNjA3MTk3NjU5NDU5MzY2MzkwNzY5MjUyMzQ5NjYyMDI5NzM2NjQ4OTg2ODIzODQ1MTU1MDI0NDQxMTE5NjQ3OTU5MDA3NTkxODkzNzI1NTQ5NDAxNzE3MzkxMTA1OTI1OTczNDI3ODQyNTQwNzg1NTY3NDY5MTgyNDc0NDMwMzYwODcxMDY0NjA1Mzg3NTEwMDYxMjgxNzk5NTUxMTIyODM4NjQ0NzA4MTcwNzU4NDk2NDk5MDU4MDYyMTE3NDUyMzQ1Njk1ODA0NTg1NzgyNzIyNTQxOTc3Njg5MjAxNTM4ODY0NjMwMTA2Mzg5Mzg0NTYyMzYxLCJHIjoxODg5MzE3NTEzMzc1ODQ4NTg2MjE3NTg4MDk2MjQxMzE0NDg4NjIwMDQ5ODIxNjI3MDE0MDI4NDI1MDg4OTYwNjg5MTc0NDc1MDYwMDI5Mjg2OTE5OTI0NTMxMDYzOTYxNDMxMTEyOTMzOTM1MTAxMDIzMTQ5NjY0MjQzMTk4MzQzMDkxNjA1NDM4NjgyMjU4NjM3NjYxOTg0NTYzODc5MTg5OTA5NzczNDU0NzM5MDg1OTY1MDU3NjY1OTE5MzE1MjQyMTU2ODMxOTc2MDA2MDYyMjM4NTA1NzEwMTAyOTU2Mjk0NTU5MjI3NzY2NTI5OTgyOTY1MzQ2NTY0MTQzNjA3Nzg0OTYyNTk2OTU2NDg3MzI2NDE2OTUyMTE0Njc5MzQ2MTc4OTA4NzIwMjg1MjIxNTU0NjMwMDA1NzMxMjMwNTAzOTUzMjE3MTU1OTc0OTA3NjI0MDkxODkzNTM1MzQ0MjgwOTY2MTE4MTQwMzYyMTExMjIyMDE3Nzc5NzA1MDQyMzE0NDg1OTA0MjMxNDY5MzI5NTI3NDc1Njk3OTg3MjUxODIxMzQxMDk2NjYxNTgwNTM0MjgxNTQ0Mjk1MTk1MTYyMzI1NTAyNDAxNjAxODYwNjEwMTI1MDM1NTk3MTY3NTI4NTY4MTE0OTIyMjkzMTU2Nzk4NjIxMTU4MTgyNjU5NjcwNTgyNDA0MjAxMDk5MzI4NzQ3NzQwNzkzNDYyMTYwMDk0MzEyODQyNDA0NTg4NDM1NTAxNzQ2MjMxNDgzMTQ4MTQyMjc0NDYzNjg4ODg0ODIyNjQyMzYyMzU5MzYwNTI3MCwiTGVuZ3RoIjoxMDI0fQ=="
+
 func MockCandidate() (*chaincode.Candidate, []byte) {
 	id := chaincode.Asset{"c-0"}
 
@@ -623,9 +2138,9 @@ func MockCandidate() (*chaincode.Candidate, []byte) {
 		Asset:      id,
 		ElectionID: "e-0",
 		Name:       "mockCandidate",
-		PublicKey:  "eyJOIjozNDMxNzM1NTkxLCJOU3F1YXJlIjoxMTc3NjgwOTE2NjUzNjExOTI4MSwiRyI6MzQzMTczNTU5MiwiTGVuZ3RoIjoxNn0=",
+		PublicKey:  mockPublicKey,
 	}
-	if err := mock.Init(); err != nil {
+	if err := mock.Init(MockRandomness()); err != nil {
 		log.Fatal(err)
 	}
 
@@ -644,8 +2159,8 @@ func MockElection() (*chaincode.Election, []byte) {
 		Asset:      id,
 		Candidates: []string{},
 		Name:       "mockElection",
-		EndTime:    "2024-01-01 23:59:59",
-		StartTime:  "2024-01-01 00:00:00",
+		EndTime:    "2024-01-01T23:59:59Z",
+		StartTime:  "2024-01-01T00:00:00Z",
 	}
 
 	mockData, err := json.Marshal(mock)
@@ -655,3 +2170,39 @@ func MockElection() (*chaincode.Election, []byte) {
 
 	return &mock, mockData
 }
+
+// Registers the keys of an asset keyed under its election, which is looked up with the election index.
+// The asset's composite key is its ID & the key of its index entry is its ID prefixed with index-.
+func MockAssetKeys(mockStub *mocks.ChaincodeStubInterface, objectType, electionID, id string) {
+	mockStub.On("CreateCompositeKey", objectType, []string{electionID, id}).Return(id, nil)
+	mockStub.On("CreateCompositeKey", objectType+"~ElectionID", []string{id}).Return("index-"+id, nil)
+	mockStub.On("GetState", "index-"+id).Return([]byte(electionID), nil)
+}
+
+// Registers the keys of an asset keyed under its election that has not been created, including the write of its index entry
+func MockNewAssetKeys(mockStub *mocks.ChaincodeStubInterface, objectType, electionID, id string) {
+	mockStub.On("CreateCompositeKey", objectType, []string{electionID, id}).Return(id, nil)
+	mockStub.On("CreateCompositeKey", objectType+"~ElectionID", []string{id}).Return("index-"+id, nil)
+	mockStub.On("GetState", "index-"+id).Return(nil, nil)
+	mockStub.On("PutState", "index-"+id, []byte(electionID)).Return(nil)
+}
+
+// MSP of the clients returned by MockClientIdentity
+const mockMSPID = "Org1MSP"
+
+// Client identity enrolled as id with roles by mockMSPID
+func MockClientIdentity(id string, roles ...chaincode.Role) *mocks.ClientIdentity {
+	mock := &mocks.ClientIdentity{}
+
+	mock.On("GetMSPID").Return(mockMSPID, nil)
+
+	values := make([]string, len(roles))
+	for i, role := range roles {
+		values[i] = string(role)
+	}
+
+	mock.On("GetAttributeValue", chaincode.RoleAttribute).Return(strings.Join(values, ","), len(roles) != 0, nil)
+	mock.On("GetAttributeValue", chaincode.EnrollmentIDAttribute).Return(id, true, nil)
+
+	return mock
+}
@@ -3,18 +3,21 @@ package chaincode
 import (
 	"errors"
 	"fmt"
-	"log"
+	"io"
 	"math/big"
 	"reflect"
+	"slices"
+	"strings"
 	"time"
 
 	paillier "github.com/direnbharwani/evote-capstone/paillier"
+	scheme "github.com/direnbharwani/evote-capstone/scheme"
 )
 
 // ITYPES is a union set type constraint
 // that enforces only allowable types are passed to smart contract methods.
 type ITYPES interface {
-	Ballot | Candidate | Election
+	Ballot | Candidate | Election | Tally
 
 	Type() string
 	Validate() error
@@ -66,6 +69,31 @@ func (e *WorldStateInteractionError) Error() string {
 	return fmt.Sprintf("unable to interact with world state for %s: %s", e.Key, e.ErrorMessage)
 }
 
+type ElectionStatusError struct {
+	ElectionID string
+	Status     ElectionStatus
+	Action     string
+}
+
+func (e *ElectionStatusError) Error() string {
+	return fmt.Sprintf("election %s is %s! unable to %s", e.ElectionID, e.Status, e.Action)
+}
+
+type AccessDeniedError struct {
+	ClientID string
+	Function string
+	Allowed  []Role
+}
+
+func (e *AccessDeniedError) Error() string {
+	roles := make([]string, len(e.Allowed))
+	for i, role := range e.Allowed {
+		roles[i] = string(role)
+	}
+
+	return fmt.Sprintf("client %s is not allowed to call %s! requires role %s", e.ClientID, e.Function, strings.Join(roles, " or "))
+}
+
 type WorldStateReadFailureError struct {
 	Key string
 }
@@ -74,18 +102,50 @@ func (e *WorldStateReadFailureError) Error() string {
 	return fmt.Sprintf("cannot read world state with key %s", e.Key)
 }
 
+// =============================================================================
+// Roles
+// =============================================================================
+
+// Role of a client, read from the RoleAttribute of its certificate
+type Role string
+
+const (
+	// Manages elections & candidates, and moves elections through their lifecycle
+	RoleElectionAdmin Role = "election-admin"
+	// Issues & manages the ballots of registered voters
+	RoleRegistrar Role = "registrar"
+	// Casts votes on the ballots issued to them
+	RoleVoter Role = "voter"
+	// Reads the history of assets to audit elections
+	RoleAuditor Role = "auditor"
+)
+
 // =============================================================================
 // Election
 // =============================================================================
 
 // Defines an election
 // Asset ID for Elections are prefixed with e-
+// PublicKeyFingerprint is optional. If set, only candidates with a matching public key can be added to ballots.
+// If PackedCounts is set, ballots store the counts of all candidates in a single packed ciphertext,
+// which requires the maximum number of voters (MaxVoters) to be known upfront.
+// If MaxVoters is set, no more than MaxVoters ballots can be issued for the election.
+// Scheme is the name of the homomorphic scheme used to encrypt counts. Elections without a Scheme use Paillier,
+// which is the only scheme ballots support for now.
+// StartTime & EndTime are stored as RFC 3339 in UTC, so that they can be compared as strings by rich queries.
+// Legacy times in time.DateTime format are still read.
+// Status follows the lifecycle Draft -> Open -> Closed -> Tallied -> Archived. Elections without a Status are Draft.
 type Election struct {
-	Asset      Asset    `json:"Asset"`
-	Candidates []string `json:"Candidates"`
-	EndTime    string   `json:"EndTime"`
-	Name       string   `json:"Name"`
-	StartTime  string   `json:"StartTime"`
+	Asset                Asset          `json:"Asset"`
+	Candidates           []string       `json:"Candidates"`
+	EndTime              string         `json:"EndTime"`
+	MaxVoters            int64          `json:"MaxVoters"`
+	Name                 string         `json:"Name"`
+	PackedCounts         bool           `json:"PackedCounts"`
+	PublicKeyFingerprint string         `json:"PublicKeyFingerprint"`
+	Scheme               string         `json:"Scheme"`
+	StartTime            string         `json:"StartTime"`
+	Status               ElectionStatus `json:"Status"`
 }
 
 func (e Election) Type() string {
@@ -99,14 +159,14 @@ func (e Election) Validate() error {
 		return &ObjectValidationError{"missing ID", objectType}
 	}
 
-	startTime, err := time.Parse(time.DateTime, e.StartTime)
+	startTime, err := parseElectionTime(e.StartTime)
 	if err != nil {
-		return &ObjectValidationError{err.Error(), objectType}
+		return &ObjectValidationError{"StartTime " + err.Error(), objectType}
 	}
 
-	endTime, err := time.Parse(time.DateTime, e.EndTime)
+	endTime, err := parseElectionTime(e.EndTime)
 	if err != nil {
-		return &ObjectValidationError{err.Error(), objectType}
+		return &ObjectValidationError{"EndTime " + err.Error(), objectType}
 	}
 
 	// EndTime must be after StartTime
@@ -114,6 +174,24 @@ func (e Election) Validate() error {
 		return &ObjectValidationError{"EndTime must be after StartTime", objectType}
 	}
 
+	if _, err := scheme.Get(e.Scheme); err != nil {
+		return &ObjectValidationError{err.Error(), objectType}
+	}
+
+	if e.MaxVoters < 0 {
+		return &ObjectValidationError{"MaxVoters must not be negative", objectType}
+	}
+
+	if e.PackedCounts && e.MaxVoters < 1 {
+		return &ObjectValidationError{"MaxVoters must be set for elections with PackedCounts", objectType}
+	}
+
+	switch e.CurrentStatus() {
+	case StatusDraft, StatusOpen, StatusClosed, StatusTallied, StatusArchived:
+	default:
+		return &ObjectValidationError{fmt.Sprintf("unknown Status %s", e.Status), objectType}
+	}
+
 	return nil
 }
 
@@ -142,43 +220,207 @@ func (e Election) IsEqual(other interface{}) bool {
 		return false
 	}
 
+	if e.PublicKeyFingerprint != otherObj.PublicKeyFingerprint {
+		return false
+	}
+
+	if e.MaxVoters != otherObj.MaxVoters || e.PackedCounts != otherObj.PackedCounts || e.Scheme != otherObj.Scheme {
+		return false
+	}
+
+	if e.Status != otherObj.Status {
+		return false
+	}
+
 	return true
 }
 
-func (e Election) IsActive() bool {
-	loc, err := time.LoadLocation("Asia/Singapore")
+// Returns whether now is between StartTime & EndTime.
+// now should be the transaction timestamp in chaincode, so that every endorsing peer agrees regardless of its clock.
+func (e Election) IsActive(now time.Time) (bool, error) {
+	start, err := parseElectionTime(e.StartTime)
 	if err != nil {
-		log.Fatal(err)
+		return false, fmt.Errorf("election %s StartTime %w", e.Asset.ID, err)
 	}
 
-	now := time.Now().In(loc)
-
-	start, err := time.Parse(time.DateTime, e.StartTime)
+	end, err := parseElectionTime(e.EndTime)
 	if err != nil {
-		log.Fatal(err)
+		return false, fmt.Errorf("election %s EndTime %w", e.Asset.ID, err)
+	}
+
+	return (now.After(start) && now.Before(end)), nil
+}
+
+// Parses an election time as RFC 3339.
+// Legacy times in time.DateTime format have no zone and are read as UTC, which is how they were always compared.
+func parseElectionTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	if t, err := time.Parse(time.DateTime, value); err == nil {
+		return t.UTC(), nil
+	}
+
+	return time.Time{}, fmt.Errorf("%q must be an RFC 3339 time, e.g. %s", value, time.RFC3339)
+}
+
+// Rewrites StartTime & EndTime as RFC 3339 in UTC, converting legacy times
+func (e *Election) normaliseTimes() error {
+	for _, value := range []*string{&e.StartTime, &e.EndTime} {
+		t, err := parseElectionTime(*value)
+		if err != nil {
+			return err
+		}
+
+		*value = t.UTC().Format(time.RFC3339)
+	}
+
+	return nil
+}
+
+// Returns the status of the election, where elections without a Status are Draft
+func (e Election) CurrentStatus() ElectionStatus {
+	if e.Status == "" {
+		return StatusDraft
+	}
+
+	return e.Status
+}
+
+// Moves the election to the status after its current status.
+// Returns an error if the election is not in the expected status.
+func (e *Election) transition(from ElectionStatus) error {
+	current := e.CurrentStatus()
+	next := electionTransitions[from]
+
+	if current != from {
+		return &ElectionStatusError{e.Asset.ID, current, fmt.Sprintf("change status to %s", next)}
 	}
 
-	end, err := time.Parse(time.DateTime, e.EndTime)
+	e.Status = next
+	return nil
+}
+
+// Ensures the election is in one of the allowed statuses for action
+func (e Election) checkStatus(action string, allowed ...ElectionStatus) error {
+	current := e.CurrentStatus()
+
+	for _, status := range allowed {
+		if current == status {
+			return nil
+		}
+	}
+
+	return &ElectionStatusError{e.Asset.ID, current, action}
+}
+
+// Ensures the candidate's count is encrypted with the election's public key, if the election specifies one
+func (e Election) checkPublicKey(candidate Candidate) error {
+	if e.PublicKeyFingerprint == "" {
+		return nil
+	}
+
+	publicKey, err := paillier.Base64Decode[paillier.PublicKey](candidate.PublicKey)
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 
-	return (now.After(start) && now.Before(end))
+	if publicKey.Fingerprint() != e.PublicKeyFingerprint {
+		return fmt.Errorf("candidate %s public key does not match election %s", candidate.Asset.ID, e.Asset.ID)
+	}
+
+	return nil
+}
+
+// Returns true if counts are encrypted with Paillier, which ballots require for their proofs
+func (e Election) usesPaillier() bool {
+	return e.Scheme == "" || e.Scheme == scheme.PaillierName
+}
+
+// Ensures ballots can be issued for the election. Ballots only encrypt & prove counts with Paillier, so elections
+// with another scheme are rejected until ballots & count-votes support it.
+func (e Election) checkScheme(action string) error {
+	if !e.usesPaillier() {
+		return fmt.Errorf("unable to %s %s! ballots do not support the %s scheme yet", action, e.Asset.ID, e.Scheme)
+	}
+
+	return nil
+}
+
+// Returns the base for packing the counts of the election's candidates, i.e. MaxVoters + 1.
+// Returns an empty string if the election does not use packed counts.
+func (e Election) packingBase() string {
+	if !e.PackedCounts {
+		return ""
+	}
+
+	return new(big.Int).Add(big.NewInt(e.MaxVoters), big.NewInt(1)).String()
+}
+
+// =============================================================================
+// Election Status
+// =============================================================================
+
+type ElectionStatus string
+
+const (
+	// Candidates can be added, updated & removed
+	StatusDraft ElectionStatus = "Draft"
+	// Votes can be cast while the election is within StartTime & EndTime. Candidates are fixed.
+	StatusOpen ElectionStatus = "Open"
+	// Votes can no longer be cast. Ballots are fixed.
+	StatusClosed ElectionStatus = "Closed"
+	// The tally of the election has been computed on the ledger & published
+	StatusTallied ElectionStatus = "Tallied"
+	// The election is kept for record only
+	StatusArchived ElectionStatus = "Archived"
+)
+
+// Status that each status moves to, in the order of the lifecycle
+var electionTransitions = map[ElectionStatus]ElectionStatus{
+	StatusDraft:   StatusOpen,
+	StatusOpen:    StatusClosed,
+	StatusClosed:  StatusTallied,
+	StatusTallied: StatusArchived,
 }
 
 // =============================================================================
 // Candidate
 // =============================================================================
 
+// Minimum size of the modulus N of public keys stored on the ledger.
+// Smaller keys can be factorised, which would reveal every count encrypted with them.
+const MinPublicKeyBits = 2048
+
+// Decodes the base64 encoded public key of an asset, which must be well-formed & at least MinPublicKeyBits in size
+func decodeLedgerPublicKey(publicKeyBase64 string) (*paillier.PublicKey, error) {
+	publicKey, err := paillier.Base64Decode[paillier.PublicKey](publicKeyBase64)
+	if err != nil {
+		return nil, err
+	}
+	if err = paillier.ValidatePublicKey(publicKey); err != nil {
+		return nil, err
+	}
+
+	if publicKey.N.BitLen() < MinPublicKeyBits {
+		return nil, fmt.Errorf("public key N must be at least %d bits but is %d bits", MinPublicKeyBits, publicKey.N.BitLen())
+	}
+
+	return publicKey, nil
+}
+
 // Defines a electoral candidate with a public key for encrypting the count.
 // The private key is omitted such that the count cannot be decrypted.
 // Asset ID for Candidates are prefixed with c-
 type Candidate struct {
-	Asset      Asset  `json:"Asset"`
-	Count      string `json:"Count"`
-	ElectionID string `json:"ElectionID"`
-	Name       string `json:"Name"`
-	PublicKey  string `json:"PublicKey"`
+	Asset                Asset  `json:"Asset"`
+	Count                string `json:"Count"`
+	ElectionID           string `json:"ElectionID"`
+	Name                 string `json:"Name"`
+	Proof                string `json:"Proof"`
+	PublicKey            string `json:"PublicKey"`
+	PublicKeyFingerprint string `json:"PublicKeyFingerprint"`
 }
 
 func (c Candidate) Type() string {
@@ -200,6 +442,22 @@ func (c Candidate) Validate() error {
 		return &ObjectValidationError{"missing Public Key", objectType}
 	}
 
+	publicKey, err := decodeLedgerPublicKey(c.PublicKey)
+	if err != nil {
+		return &ObjectValidationError{fmt.Sprintf("invalid Public Key: %v", err), objectType}
+	}
+
+	if c.PublicKeyFingerprint != "" && c.PublicKeyFingerprint != publicKey.Fingerprint() {
+		return &ObjectValidationError{"PublicKeyFingerprint does not match Public Key", objectType}
+	}
+
+	// Count is only set once the candidate has been initialised
+	if c.Count != "" {
+		if err := c.VerifyCount(); err != nil {
+			return &ObjectValidationError{err.Error(), objectType}
+		}
+	}
+
 	return nil
 }
 
@@ -217,43 +475,80 @@ func (c Candidate) IsEqual(other interface{}) bool {
 		return false
 	}
 
-	if c.PublicKey != otherObj.PublicKey {
+	if c.Proof != otherObj.Proof || c.PublicKey != otherObj.PublicKey || c.PublicKeyFingerprint != otherObj.PublicKeyFingerprint {
 		return false
 	}
 
 	return true
 }
 
-func (c *Candidate) Init() error {
+// Sets the count to an encryption of 0 with a proof that it is well-formed.
+// The randomness of the encryption & proof is drawn from random.
+func (c *Candidate) Init(random io.Reader) error {
+	_, err := c.encryptCount(random, big.NewInt(0))
+	return err
+}
+
+// Verifies the proof that the count encrypts either 0 or 1
+func (c Candidate) VerifyCount() error {
 	publicKey, err := paillier.Base64Decode[paillier.PublicKey](c.PublicKey)
 	if err != nil {
 		return err
 	}
 
-	zeroCount, err := paillier.Encrypt(publicKey, big.NewInt(0))
+	count, ok := new(big.Int).SetString(c.Count, 10)
+	if !ok {
+		return errors.New("failed to parse candidate count")
+	}
+
+	if err = paillier.ValidateCiphertext(publicKey, count); err != nil {
+		return fmt.Errorf("candidate %s count is invalid: %v", c.Asset.ID, err)
+	}
+
+	if c.Proof == "" {
+		return fmt.Errorf("candidate %s count is missing a proof", c.Asset.ID)
+	}
+
+	proof, err := paillier.Base64Decode[paillier.BinaryProof](c.Proof)
 	if err != nil {
 		return err
 	}
 
-	c.Count = zeroCount.String()
+	if !paillier.VerifyBinary(publicKey, count, proof) {
+		return fmt.Errorf("candidate %s count is not well-formed", c.Asset.ID)
+	}
 
 	return nil
 }
 
-func (c *Candidate) IncrementCount() error {
+// Replaces the count with a fresh encryption of value (0 or 1) and its proof.
+// Returns the randomness used for encryption, which is required for proofs across a ballot.
+func (c *Candidate) encryptCount(random io.Reader, value *big.Int) (*big.Int, error) {
 	publicKey, err := paillier.Base64Decode[paillier.PublicKey](c.PublicKey)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	count, ok := new(big.Int).SetString(c.Count, 10)
-	if !ok {
-		return errors.New("failed to parse candidate count")
+	count, randomness, err := paillier.EncryptWithReader(random, publicKey, value)
+	if err != nil {
+		return nil, err
 	}
 
-	c.Count = paillier.AddEncryptedWithPlain(publicKey, count, big.NewInt(1)).String()
+	proof, err := paillier.ProveBinaryWithReader(random, publicKey, count, value, randomness)
+	if err != nil {
+		return nil, err
+	}
 
-	return nil
+	proofBase64, err := paillier.Base64Encode(proof)
+	if err != nil {
+		return nil, err
+	}
+
+	c.Count = count.String()
+	c.Proof = proofBase64
+	c.PublicKeyFingerprint = publicKey.Fingerprint()
+
+	return randomness, nil
 }
 
 // =============================================================================
@@ -262,19 +557,28 @@ func (c *Candidate) IncrementCount() error {
 
 // Defines a Ballot that is assigned to a voter
 // Asset ID for Ballots are prefixed with b-
+//
+// If PackingBase is set, the counts of all candidates are packed into PackedCount,
+// where a vote for the i-th candidate encrypts PackingBase^i. The candidates' own counts are left empty.
+// PackedProof proves that PackedCount encrypts a vote for exactly one candidate if the ballot has been voted, or 0 otherwise.
 type Ballot struct {
-	Asset      Asset       `json:"Asset"`
-	Candidates []Candidate `json:"Candidates"`
-	ElectionID string      `json:"ElectionID"`
-	VoterID    string      `json:"VoterID"`
-	Voted      bool        `json:"Voted"`
+	Asset       Asset       `json:"Asset"`
+	Candidates  []Candidate `json:"Candidates"`
+	ElectionID  string      `json:"ElectionID"`
+	PackedCount string      `json:"PackedCount"`
+	PackedProof string      `json:"PackedProof"`
+	PackingBase string      `json:"PackingBase"`
+	SumProof    string      `json:"SumProof"`
+	VoterID     string      `json:"VoterID"`
+	Voted       bool        `json:"Voted"`
 }
 
 func (b Ballot) Type() string {
 	return reflect.TypeOf(b).String()
 }
 
-// Checks if BallotID, VoterID & ElectionID are not empty strings
+// Checks if BallotID, VoterID & ElectionID are not empty strings.
+// If the ballot has candidates, their counts must also be well-formed.
 func (b Ballot) Validate() error {
 	objectType := reflect.TypeOf(b).String()
 
@@ -286,6 +590,12 @@ func (b Ballot) Validate() error {
 		return &ObjectValidationError{"missing ElectionID", objectType}
 	}
 
+	if len(b.Candidates) > 0 {
+		if err := b.VerifyCounts(); err != nil {
+			return &ObjectValidationError{err.Error(), objectType}
+		}
+	}
+
 	return nil
 }
 
@@ -314,31 +624,717 @@ func (b Ballot) IsEqual(other interface{}) bool {
 		return false
 	}
 
+	if b.SumProof != otherObj.SumProof {
+		return false
+	}
+
+	if b.PackedCount != otherObj.PackedCount || b.PackedProof != otherObj.PackedProof || b.PackingBase != otherObj.PackingBase {
+		return false
+	}
+
 	return true
 }
 
-func (b *Ballot) Vote(candidateID string) error {
+// Returns true if the ballots have the same candidates, counts, proofs & Voted, i.e. the fields changed by casting a vote
+func (b Ballot) hasSameVotes(other Ballot) bool {
+	if b.Voted != other.Voted || len(b.Candidates) != len(other.Candidates) {
+		return false
+	}
+
+	for i := range b.Candidates {
+		if !b.Candidates[i].IsEqual(other.Candidates[i]) {
+			return false
+		}
+	}
+
+	if b.SumProof != other.SumProof {
+		return false
+	}
+
+	return b.PackedCount == other.PackedCount && b.PackedProof == other.PackedProof && b.PackingBase == other.PackingBase
+}
+
+// Sets the count of all candidates to an encryption of 0 with proofs that the ballot is well-formed.
+// The randomness of the encryptions & proofs is drawn from random.
+func (b *Ballot) Init(random io.Reader) error {
+	_, err := b.encryptCounts(random, "")
+	return err
+}
+
+// Verifies that every candidate's count encrypts either 0 or 1,
+// and that the counts sum to 1 if the ballot has been voted, or 0 otherwise.
+// For packed ballots, the packed count is verified instead.
+func (b Ballot) VerifyCounts() error {
+	if b.PackingBase != "" {
+		return b.verifyPackedCount()
+	}
+
+	publicKey, err := paillier.Base64Decode[paillier.PublicKey](b.Candidates[0].PublicKey)
+	if err != nil {
+		return err
+	}
+
+	counts := []*big.Int{}
+	for _, c := range b.Candidates {
+		if c.PublicKey != b.Candidates[0].PublicKey {
+			return fmt.Errorf("candidate %s has a different public key", c.Asset.ID)
+		}
+
+		if err := c.VerifyCount(); err != nil {
+			return err
+		}
+
+		count, _ := new(big.Int).SetString(c.Count, 10)
+		counts = append(counts, count)
+	}
+
+	if b.SumProof == "" {
+		return fmt.Errorf("ballot %s is missing a sum proof", b.Asset.ID)
+	}
+
+	sumProof, err := paillier.Base64Decode[paillier.SumProof](b.SumProof)
+	if err != nil {
+		return err
+	}
+
+	sum := big.NewInt(0)
+	if b.Voted {
+		sum = big.NewInt(1)
+	}
+
+	if !paillier.VerifySum(publicKey, counts, sum, sumProof) {
+		return fmt.Errorf("ballot %s counts do not sum to %d", b.Asset.ID, sum)
+	}
+
+	return nil
+}
+
+// Verifies that the packed count encrypts a vote for one candidate if the ballot has been voted, or 0 otherwise
+func (b Ballot) verifyPackedCount() error {
+	publicKey, packing, err := b.packing()
+	if err != nil {
+		return err
+	}
+
+	for _, c := range b.Candidates {
+		if c.PublicKey != b.Candidates[0].PublicKey {
+			return fmt.Errorf("candidate %s has a different public key", c.Asset.ID)
+		}
+	}
+
+	count, ok := new(big.Int).SetString(b.PackedCount, 10)
+	if !ok {
+		return errors.New("failed to parse packed count")
+	}
+
+	if err = paillier.ValidateCiphertext(publicKey, count); err != nil {
+		return fmt.Errorf("ballot %s packed count is invalid: %v", b.Asset.ID, err)
+	}
+
+	if b.PackedProof == "" {
+		return fmt.Errorf("ballot %s packed count is missing a proof", b.Asset.ID)
+	}
+
+	proof, err := paillier.Base64Decode[paillier.MembershipProof](b.PackedProof)
+	if err != nil {
+		return err
+	}
+
+	if !paillier.VerifyMembership(publicKey, count, packedValues(packing, b.Voted), proof) {
+		return fmt.Errorf("ballot %s packed count is not well-formed", b.Asset.ID)
+	}
+
+	return nil
+}
+
+// Returns the public key & packing of a packed ballot
+func (b Ballot) packing() (*paillier.PublicKey, *paillier.Packing, error) {
+	if len(b.Candidates) == 0 {
+		return nil, nil, fmt.Errorf("ballot %s has no candidates to pack", b.Asset.ID)
+	}
+
+	publicKey, err := paillier.Base64Decode[paillier.PublicKey](b.Candidates[0].PublicKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	base, ok := new(big.Int).SetString(b.PackingBase, 10)
+	if !ok {
+		return nil, nil, errors.New("failed to parse packing base")
+	}
+
+	packing := &paillier.Packing{Base: base, NumSlots: len(b.Candidates)}
+	if err = packing.Validate(publicKey); err != nil {
+		return nil, nil, err
+	}
+
+	return publicKey, packing, nil
+}
+
+// Returns the values a packed count may encrypt.
+// A voted ballot encrypts the slot of one candidate, while an unvoted ballot encrypts 0.
+func packedValues(packing *paillier.Packing, voted bool) []*big.Int {
+	if voted {
+		return packing.Slots()
+	}
+
+	return []*big.Int{big.NewInt(0)}
+}
+
+// Plaintext & randomness of an encrypted count, which the voter's client proves knowledge of when casting a vote
+type countOpening struct {
+	value      *big.Int
+	randomness *big.Int
+}
+
+// Re-encrypts the count of every candidate such that only candidateID encrypts 1.
+// No candidate encrypts 1 if candidateID is empty.
+// Returns the opening of every count, or of the packed count for packed ballots.
+func (b *Ballot) encryptCounts(random io.Reader, candidateID string) ([]countOpening, error) {
+	if b.PackingBase != "" {
+		return b.encryptPackedCount(random, candidateID)
+	}
+
+	if len(b.Candidates) == 0 {
+		b.SumProof = ""
+		return nil, nil
+	}
+
+	publicKey, err := paillier.Base64Decode[paillier.PublicKey](b.Candidates[0].PublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	sum := big.NewInt(0)
+	counts := []*big.Int{}
+	randomness := []*big.Int{}
+	openings := []countOpening{}
+
+	for i := range b.Candidates {
+		value := big.NewInt(0)
+		if b.Candidates[i].Asset.ID == candidateID {
+			value = big.NewInt(1)
+			sum = big.NewInt(1)
+		}
+
+		r, err := b.Candidates[i].encryptCount(random, value)
+		if err != nil {
+			return nil, err
+		}
+
+		count, _ := new(big.Int).SetString(b.Candidates[i].Count, 10)
+		counts = append(counts, count)
+		randomness = append(randomness, r)
+		openings = append(openings, countOpening{value, r})
+	}
+
+	sumProof, err := paillier.ProveSumWithReader(random, publicKey, counts, randomness, sum)
+	if err != nil {
+		return nil, err
+	}
+
+	if b.SumProof, err = paillier.Base64Encode(sumProof); err != nil {
+		return nil, err
+	}
+
+	return openings, nil
+}
+
+// Replaces the packed count with a fresh encryption of the slot of candidateID and its proof.
+// The packed count encrypts 0 if candidateID is empty.
+func (b *Ballot) encryptPackedCount(random io.Reader, candidateID string) ([]countOpening, error) {
+	publicKey, packing, err := b.packing()
+	if err != nil {
+		return nil, err
+	}
+
+	value := big.NewInt(0)
+	for i := range b.Candidates {
+		// Counts of individual candidates are not used by packed ballots
+		b.Candidates[i].Count = ""
+		b.Candidates[i].Proof = ""
+
+		if b.Candidates[i].Asset.ID == candidateID {
+			if value, err = packing.Slot(i); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	count, randomness, err := paillier.EncryptWithReader(random, publicKey, value)
+	if err != nil {
+		return nil, err
+	}
+
+	proof, err := paillier.ProveMembershipWithReader(random, publicKey, count, value, randomness, packedValues(packing, candidateID != ""))
+	if err != nil {
+		return nil, err
+	}
+
+	if b.PackedProof, err = paillier.Base64Encode(proof); err != nil {
+		return nil, err
+	}
+
+	b.PackedCount = count.String()
+	b.SumProof = ""
+
+	return []countOpening{{value, randomness}}, nil
+}
+
+// Casts the ballot's vote for candidateID.
+// The randomness of the re-encrypted counts & proofs is drawn from random.
+func (b *Ballot) Vote(random io.Reader, candidateID string) error {
+	_, err := b.vote(random, candidateID)
+	return err
+}
+
+// Casts the ballot's vote for candidateID, returning the openings of the re-encrypted counts
+func (b *Ballot) vote(random io.Reader, candidateID string) ([]countOpening, error) {
 	if b.Voted {
 		errorMessage := fmt.Sprintf("ballot %s has already been cast! unable to vote", b.Asset.ID)
-		return errors.New(errorMessage)
+		return nil, errors.New(errorMessage)
 	}
 
 	candidateFound := false
-	for i, c := range b.Candidates {
+	for _, c := range b.Candidates {
 		if c.Asset.ID == candidateID {
 			candidateFound = true
-
-			b.Candidates[i].IncrementCount()
-			b.Voted = true
-
 			break
 		}
 	}
 
 	if !candidateFound {
 		errorMessage := fmt.Sprintf("candidate %s is not found in ballot %s!", candidateID, b.Asset.ID)
+		return nil, errors.New(errorMessage)
+	}
+
+	// All counts are re-encrypted instead of incrementing the count of candidateID,
+	// since the proofs of the ballot require the randomness of every count
+	openings, err := b.encryptCounts(random, candidateID)
+	if err != nil {
+		return nil, err
+	}
+	b.Voted = true
+
+	return openings, nil
+}
+
+// Encrypts a vote for candidateID as the arguments of CastEncryptedVote, leaving the ballot unchanged.
+// This is run by the voter's client, such that the choice of the voter is never sent to the chaincode.
+// The randomness of the encrypted counts & proofs is drawn from random.
+func (b Ballot) EncryptVote(random io.Reader, candidateID string) ([]string, []string, error) {
+	voted := b
+	voted.Candidates = slices.Clone(b.Candidates)
+
+	openings, err := voted.vote(random, candidateID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	encryptedCounters := []string{}
+	proofs := []string{}
+
+	if voted.PackingBase != "" {
+		encryptedCounters = append(encryptedCounters, voted.PackedCount)
+		proofs = append(proofs, voted.PackedProof)
+	} else {
+		for _, c := range voted.Candidates {
+			encryptedCounters = append(encryptedCounters, c.Count)
+			proofs = append(proofs, c.Proof)
+		}
+		proofs = append(proofs, voted.SumProof)
+	}
+
+	publicKey, err := paillier.Base64Decode[paillier.PublicKey](voted.Candidates[0].PublicKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The well-formedness proofs are not bound to the ballot, so knowledge of every count is proven under the
+	// ballot's ID. Counts & proofs copied from another ballot cannot be cast without the other voter's randomness.
+	for i, encryptedCounter := range encryptedCounters {
+		count, _ := new(big.Int).SetString(encryptedCounter, 10)
+
+		proof, err := paillier.ProvePlaintextKnowledgeWithReader(random, publicKey, count, openings[i].value, openings[i].randomness, b.Asset.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		proofBase64, err := paillier.Base64Encode(proof)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		proofs = append(proofs, proofBase64)
+	}
+
+	return encryptedCounters, proofs, nil
+}
+
+// Casts the ballot's vote with counts encrypted by the voter's client, as returned by EncryptVote.
+// encryptedCounters are the counts of the ballot's candidates in order, and proofs are the proof of each count,
+// followed by the proof that the counts sum to 1 & the proof of knowledge of each count under the ballot's ID.
+// Packed ballots have a single packed count, followed by its proof & its proof of knowledge instead.
+// Returns an error if the counts are not well-formed or not bound to the ballot, in which case the ballot is unchanged.
+func (b *Ballot) CastEncrypted(encryptedCounters []string, proofs []string) error {
+	if b.Voted {
+		errorMessage := fmt.Sprintf("ballot %s has already been cast! unable to vote", b.Asset.ID)
 		return errors.New(errorMessage)
 	}
 
+	voted := *b
+	voted.Candidates = slices.Clone(b.Candidates)
+	voted.Voted = true
+
+	var knowledgeProofs []string
+
+	if voted.PackingBase != "" {
+		if len(encryptedCounters) != 1 || len(proofs) != 2 {
+			return fmt.Errorf("packed ballot %s requires 1 encrypted count & 2 proofs", b.Asset.ID)
+		}
+
+		voted.PackedCount = encryptedCounters[0]
+		voted.PackedProof = proofs[0]
+		knowledgeProofs = proofs[1:]
+	} else {
+		numCandidates := len(voted.Candidates)
+		if len(encryptedCounters) != numCandidates || len(proofs) != 2*numCandidates+1 {
+			return fmt.Errorf("ballot %s requires %d encrypted counts & %d proofs", b.Asset.ID, numCandidates, 2*numCandidates+1)
+		}
+
+		for i := range voted.Candidates {
+			voted.Candidates[i].Count = encryptedCounters[i]
+			voted.Candidates[i].Proof = proofs[i]
+		}
+		voted.SumProof = proofs[numCandidates]
+		knowledgeProofs = proofs[numCandidates+1:]
+	}
+
+	if err := voted.VerifyCounts(); err != nil {
+		return fmt.Errorf("encrypted vote for ballot %s is invalid: %v", b.Asset.ID, err)
+	}
+
+	if err := voted.verifyCountKnowledge(encryptedCounters, knowledgeProofs); err != nil {
+		return fmt.Errorf("encrypted vote for ballot %s is invalid: %v", b.Asset.ID, err)
+	}
+
+	*b = voted
+
+	return nil
+}
+
+// Verifies the proofs of knowledge of the encrypted counts, which must be bound to the ballot's ID
+func (b Ballot) verifyCountKnowledge(encryptedCounters []string, proofs []string) error {
+	publicKey, err := paillier.Base64Decode[paillier.PublicKey](b.Candidates[0].PublicKey)
+	if err != nil {
+		return err
+	}
+
+	for i, encryptedCounter := range encryptedCounters {
+		count, ok := new(big.Int).SetString(encryptedCounter, 10)
+		if !ok {
+			return errors.New("failed to parse encrypted count")
+		}
+
+		proof, err := paillier.Base64Decode[paillier.KnowledgeProof](proofs[i])
+		if err != nil {
+			return err
+		}
+
+		if !paillier.VerifyPlaintextKnowledge(publicKey, count, b.Asset.ID, proof) {
+			return fmt.Errorf("encrypted count %d is not bound to ballot %s", i, b.Asset.ID)
+		}
+	}
+
 	return nil
 }
+
+// =============================================================================
+// Tally
+// =============================================================================
+
+// Defines the encrypted tally of a Closed election, computed on the ledger from the counts of every voted ballot
+// such that everyone decrypts the same counts.
+// Asset ID for Tallies is the ID of their election.
+//
+// Counts are in the order of the election's candidates. If PackingBase is set, the counts of all candidates are
+// packed into PackedCount instead, and the Count of each candidate is left empty.
+// Counts start from 1, the encryption of 0 without randomness, so that candidates without votes have a valid count.
+type Tally struct {
+	Asset       Asset        `json:"Asset"`
+	Counts      []TallyCount `json:"Counts"`
+	ElectionID  string       `json:"ElectionID"`
+	NumBallots  int64        `json:"NumBallots"`
+	PackedCount string       `json:"PackedCount"`
+	PackingBase string       `json:"PackingBase"`
+	PublicKey   string       `json:"PublicKey"`
+}
+
+// Encrypted count of a candidate in a Tally
+type TallyCount struct {
+	CandidateID string `json:"CandidateID"`
+	Count       string `json:"Count"`
+	Name        string `json:"Name"`
+}
+
+func (t Tally) Type() string {
+	return reflect.TypeOf(t).String()
+}
+
+// Checks if the tally belongs to an election and its counts are valid ciphertexts of its public key
+func (t Tally) Validate() error {
+	objectType := reflect.TypeOf(t).String()
+
+	if t.Asset.ID == "" {
+		return &ObjectValidationError{"missing ID", objectType}
+	}
+
+	if t.ElectionID == "" {
+		return &ObjectValidationError{"missing ElectionID", objectType}
+	}
+
+	if t.Asset.ID != t.ElectionID {
+		return &ObjectValidationError{"ID must be the ID of its election", objectType}
+	}
+
+	publicKey, err := decodeLedgerPublicKey(t.PublicKey)
+	if err != nil {
+		return &ObjectValidationError{fmt.Sprintf("invalid Public Key: %v", err), objectType}
+	}
+
+	if t.PackingBase != "" {
+		if err = validateCount(publicKey, t.PackedCount); err != nil {
+			return &ObjectValidationError{"PackedCount " + err.Error(), objectType}
+		}
+
+		return nil
+	}
+
+	for _, count := range t.Counts {
+		if err = validateCount(publicKey, count.Count); err != nil {
+			return &ObjectValidationError{fmt.Sprintf("count of candidate %s %v", count.CandidateID, err), objectType}
+		}
+	}
+
+	return nil
+}
+
+func (t Tally) IsEqual(other interface{}) bool {
+	otherObj, ok := other.(Tally)
+	if !ok {
+		return false
+	}
+
+	if t.Asset != otherObj.Asset {
+		return false
+	}
+
+	if len(t.Counts) != len(otherObj.Counts) {
+		return false
+	}
+	for i := range t.Counts {
+		if t.Counts[i] != otherObj.Counts[i] {
+			return false
+		}
+	}
+
+	if t.ElectionID != otherObj.ElectionID || t.NumBallots != otherObj.NumBallots || t.PublicKey != otherObj.PublicKey {
+		return false
+	}
+
+	if t.PackedCount != otherObj.PackedCount || t.PackingBase != otherObj.PackingBase {
+		return false
+	}
+
+	return true
+}
+
+// Sets the counts of an empty tally for the candidates of election, which must all share the same public key
+func (t *Tally) Init(election Election, candidates []Candidate) error {
+	if len(candidates) == 0 {
+		return fmt.Errorf("election %s has no candidates to tally", election.Asset.ID)
+	}
+
+	t.Asset = Asset{ID: election.Asset.ID}
+	t.ElectionID = election.Asset.ID
+	t.NumBallots = 0
+	t.PackingBase = election.packingBase()
+	t.PublicKey = candidates[0].PublicKey
+
+	t.Counts = []TallyCount{}
+	for _, candidate := range candidates {
+		if candidate.PublicKey != t.PublicKey {
+			return fmt.Errorf("candidate %s has a different public key", candidate.Asset.ID)
+		}
+
+		count := TallyCount{CandidateID: candidate.Asset.ID, Name: candidate.Name}
+		if t.PackingBase == "" {
+			count.Count = "1"
+		}
+
+		t.Counts = append(t.Counts, count)
+	}
+
+	t.PackedCount = ""
+	if t.PackingBase != "" {
+		t.PackedCount = "1"
+	}
+
+	return nil
+}
+
+// Adds the counts of a voted ballot to the tally.
+// The ballot must have the candidates of the tally in the same order, encrypted with the tally's public key.
+func (t *Tally) Add(ballot Ballot) error {
+	if !ballot.Voted {
+		return fmt.Errorf("ballot %s has not been cast! unable to tally", ballot.Asset.ID)
+	}
+
+	if ballot.ElectionID != t.ElectionID {
+		return fmt.Errorf("ballot %s is not part of election %s", ballot.Asset.ID, t.ElectionID)
+	}
+
+	if len(ballot.Candidates) != len(t.Counts) || ballot.PackingBase != t.PackingBase {
+		return fmt.Errorf("ballot %s has different candidates from the tally of election %s", ballot.Asset.ID, t.ElectionID)
+	}
+
+	for i, candidate := range ballot.Candidates {
+		if candidate.Asset.ID != t.Counts[i].CandidateID {
+			return fmt.Errorf("ballot %s has different candidates from the tally of election %s", ballot.Asset.ID, t.ElectionID)
+		}
+
+		if candidate.PublicKey != t.PublicKey {
+			return fmt.Errorf("candidate %s in ballot %s has a different public key", candidate.Asset.ID, ballot.Asset.ID)
+		}
+	}
+
+	publicKey, err := paillier.Base64Decode[paillier.PublicKey](t.PublicKey)
+	if err != nil {
+		return err
+	}
+
+	if t.PackingBase != "" {
+		if t.PackedCount, err = addCounts(publicKey, t.PackedCount, ballot.PackedCount); err != nil {
+			return fmt.Errorf("unable to tally ballot %s: %v", ballot.Asset.ID, err)
+		}
+	} else {
+		// Counts are only replaced once every count of the ballot has been added
+		counts := make([]string, len(t.Counts))
+		for i, candidate := range ballot.Candidates {
+			if counts[i], err = addCounts(publicKey, t.Counts[i].Count, candidate.Count); err != nil {
+				return fmt.Errorf("unable to tally ballot %s: %v", ballot.Asset.ID, err)
+			}
+		}
+
+		for i := range t.Counts {
+			t.Counts[i].Count = counts[i]
+		}
+	}
+
+	t.NumBallots++
+
+	return nil
+}
+
+// Ensures count is a ciphertext of publicKey in decimal
+func validateCount(publicKey *paillier.PublicKey, count string) error {
+	value, ok := new(big.Int).SetString(count, 10)
+	if !ok {
+		return errors.New("is not a number")
+	}
+
+	return paillier.ValidateCiphertext(publicKey, value)
+}
+
+// Homomorphically adds two encrypted counts in decimal
+func addCounts(publicKey *paillier.PublicKey, lhs, rhs string) (string, error) {
+	if err := validateCount(publicKey, lhs); err != nil {
+		return "", err
+	}
+
+	if err := validateCount(publicKey, rhs); err != nil {
+		return "", err
+	}
+
+	lhsValue, _ := new(big.Int).SetString(lhs, 10)
+	rhsValue, _ := new(big.Int).SetString(rhs, 10)
+
+	return paillier.AddEncrypted(publicKey, lhsValue, rhsValue).String(), nil
+}
+
+// =============================================================================
+// Pages
+// =============================================================================
+
+// Pages of the results of paginated queries.
+// Bookmark is passed to the next query to read the next page. The last page has fewer results than the page size
+// or an empty Bookmark.
+
+type BallotPage struct {
+	Bookmark string   `json:"Bookmark"`
+	Count    int32    `json:"Count"`
+	Results  []Ballot `json:"Results"`
+}
+
+type CandidatePage struct {
+	Bookmark string      `json:"Bookmark"`
+	Count    int32       `json:"Count"`
+	Results  []Candidate `json:"Results"`
+}
+
+type ElectionPage struct {
+	Bookmark string     `json:"Bookmark"`
+	Count    int32      `json:"Count"`
+	Results  []Election `json:"Results"`
+}
+
+// =============================================================================
+// Events
+// =============================================================================
+
+// Name of a chaincode event. The payload of an event is its event type of the same name, encoded as JSON.
+// Fabric only keeps the last event set by a transaction, so each transaction emits at most one event.
+type EventName string
+
+const (
+	EventElectionCreated EventName = "ElectionCreated"
+	EventCandidateAdded  EventName = "CandidateAdded"
+	EventBallotIssued    EventName = "BallotIssued"
+	EventVoteCast        EventName = "VoteCast"
+	EventElectionClosed  EventName = "ElectionClosed"
+	EventTallyPublished  EventName = "TallyPublished"
+)
+
+type ElectionCreatedEvent struct {
+	ElectionID string `json:"ElectionID"`
+	EndTime    string `json:"EndTime"`
+	Name       string `json:"Name"`
+	StartTime  string `json:"StartTime"`
+}
+
+type CandidateAddedEvent struct {
+	CandidateID string `json:"CandidateID"`
+	ElectionID  string `json:"ElectionID"`
+	Name        string `json:"Name"`
+}
+
+// The voter of the ballot is not included, so that events cannot be linked to voters
+type BallotIssuedEvent struct {
+	BallotID   string `json:"BallotID"`
+	ElectionID string `json:"ElectionID"`
+}
+
+// Only identifies the ballot, never the candidate voted for.
+// The election of the ballot is given by the BallotIssuedEvent of the ballot.
+type VoteCastEvent struct {
+	BallotID string `json:"BallotID"`
+}
+
+type ElectionClosedEvent struct {
+	ElectionID string `json:"ElectionID"`
+}
+
+type TallyPublishedEvent struct {
+	ElectionID string `json:"ElectionID"`
+	NumBallots int64  `json:"NumBallots"`
+}
@@ -1,6 +1,30 @@
 package chaincode
 
-import "encoding/json"
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+
+	paillier "github.com/direnbharwani/evote-capstone/paillier"
+)
+
+// Key of the transient data holding the client's secret seed for the randomness of a transaction
+const RandomnessSeedKey = "randomnessSeed"
+
+// Minimum size of the secret seed, which must contain at least 256 bits of entropy
+const minRandomnessSeedSize = 32
+
+// Attribute of client certificates holding the roles of the client, separated by commas
+const RoleAttribute = "evote.role"
+
+// Attribute added to certificates by Fabric CA, holding the name the client was enrolled with
+const EnrollmentIDAttribute = "hf.EnrollmentID"
 
 func ParseJSON[T ITYPES](data string) (T, error) {
 	var emptyObject T
@@ -15,3 +39,108 @@ func ParseJSON[T ITYPES](data string) (T, error) {
 
 	return result, nil
 }
+
+// Returns the source of randomness for encrypting counts in a transaction.
+// If the client provides a secret seed as transient data, randomness is derived from the transaction ID & the seed
+// with a DRBG, so every endorsing peer encrypts identical counts and the endorsements match.
+// Transient data is not written to the ledger, which keeps the randomness secret. The transaction ID alone is public
+// and would reveal the encrypted votes, so crypto/rand is used if no seed is provided. Such transactions can only be
+// endorsed by a single peer.
+func transactionRandomness(ctx contractapi.TransactionContextInterface) (io.Reader, error) {
+	transient, err := ctx.GetStub().GetTransient()
+	if err != nil {
+		return nil, err
+	}
+
+	seed, found := transient[RandomnessSeedKey]
+	if !found {
+		return rand.Reader, nil
+	}
+
+	if len(seed) < minRandomnessSeedSize {
+		return nil, fmt.Errorf("%s must be at least %d bytes", RandomnessSeedKey, minRandomnessSeedSize)
+	}
+
+	return paillier.NewDRBG(append([]byte(ctx.GetStub().GetTxID()), seed...))
+}
+
+// Returns the timestamp of the transaction, which is set by the client & identical on every endorsing peer,
+// unlike the clock of each peer
+func transactionTime(ctx contractapi.TransactionContextInterface) (time.Time, error) {
+	timestamp, err := ctx.GetStub().GetTxTimestamp()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return timestamp.AsTime(), nil
+}
+
+// Sets the event of the transaction to name, with payload encoded as JSON
+func emitEvent(ctx contractapi.TransactionContextInterface, name EventName, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	if err = ctx.GetStub().SetEvent(string(name), data); err != nil {
+		return fmt.Errorf("unable to emit %s event: %w", name, err)
+	}
+
+	return nil
+}
+
+// Ensures the client invoking function has one of the allowed roles
+func requireRole(ctx contractapi.TransactionContextInterface, function string, allowed ...Role) error {
+	value, found, err := ctx.GetClientIdentity().GetAttributeValue(RoleAttribute)
+	if err != nil {
+		return err
+	}
+
+	if found {
+		for _, role := range strings.Split(value, ",") {
+			if slices.Contains(allowed, Role(strings.TrimSpace(role))) {
+				return nil
+			}
+		}
+	}
+
+	id, err := clientID(ctx)
+	if err != nil {
+		return err
+	}
+
+	return &AccessDeniedError{ClientID: id, Function: function, Allowed: allowed}
+}
+
+// Returns the ID of a client within an MSP, e.g. the VoterID of ballots assigned to the client
+func MSPQualifiedID(mspID string, id string) string {
+	return mspID + ":" + id
+}
+
+// Returns the ID of the client invoking the transaction, which is the name it was enrolled with.
+// Clients enrolled without Fabric CA are identified by their unique ID within their MSP instead.
+// Enrollment IDs are only unique within the CA of an organisation, so the ID is qualified by the client's MSP ID.
+func clientID(ctx contractapi.TransactionContextInterface) (string, error) {
+	identity := ctx.GetClientIdentity()
+
+	mspID, err := identity.GetMSPID()
+	if err != nil {
+		return "", err
+	}
+
+	enrollmentID, found, err := identity.GetAttributeValue(EnrollmentIDAttribute)
+	if err != nil {
+		return "", err
+	}
+
+	if found && enrollmentID != "" {
+		return MSPQualifiedID(mspID, enrollmentID), nil
+	}
+
+	id, err := identity.GetID()
+	if err != nil {
+		return "", err
+	}
+
+	return MSPQualifiedID(mspID, id), nil
+}
@@ -0,0 +1,455 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"time"
+
+	paillier "github.com/direnbharwani/evote-capstone/paillier"
+)
+
+// =============================================================================
+// Generate
+// =============================================================================
+
+// Generates a key pair, or a threshold public key with a file per key share.
+// A keystore of the private key is also written if KEYSTORE_PASSPHRASE is set.
+func generate(args []string) error {
+	var (
+		flags     = flag.NewFlagSet("generate", flag.ExitOnError)
+		length    = flags.Int("length", 2048, "bit length of each prime")
+		format    = flags.String("format", FormatBase64, "output format of keys, base64 or json")
+		out       = flags.String("out", "keys", "directory to write keys to")
+		numShares = flags.Int("shares", 0, "number of key shares for threshold decryption, 0 for a single private key")
+		threshold = flags.Int("threshold", 0, "number of key shares required to decrypt")
+	)
+	flags.Parse(args)
+
+	if *format != FormatBase64 && *format != FormatJSON {
+		return fmt.Errorf("unsupported format %s", *format)
+	}
+
+	if err := os.MkdirAll(*out, 0755); err != nil {
+		return err
+	}
+
+	now := time.Now().Unix()
+
+	if *numShares > 0 {
+		return generateThreshold(*out, now, *length, *threshold, *numShares, *format)
+	}
+
+	publicKey, privateKey, err := paillier.GenerateKeys(*length)
+	if err != nil {
+		return err
+	}
+
+	public, err := encodeKey(publicKey, *format)
+	if err != nil {
+		return err
+	}
+
+	private, err := encodeKey(privateKey, *format)
+	if err != nil {
+		return err
+	}
+
+	fileName := filepath.Join(*out, fmt.Sprintf("paillierKeyPair_%d.json", now))
+	if err := writeKeyFile(fileName, KeyFile{Public: public, Private: private}, 0600); err != nil {
+		return err
+	}
+
+	fmt.Printf("Fingerprint: %s\n", publicKey.Fingerprint())
+	fmt.Printf("Wrote %s\n", fileName)
+
+	// Write a password-protected keystore of the private key if a passphrase is provided
+	passphrase := os.Getenv(PassphraseEnv)
+	if passphrase == "" {
+		return nil
+	}
+
+	keystore, err := paillier.LockPrivateKey(publicKey, privateKey, []byte(passphrase))
+	if err != nil {
+		return err
+	}
+
+	fileName = filepath.Join(*out, fmt.Sprintf("paillierKeystore_%d.json", now))
+	if err := paillier.SaveKeystore(fileName, keystore); err != nil {
+		return err
+	}
+
+	fmt.Printf("Wrote %s\n", fileName)
+
+	return nil
+}
+
+// Writes the threshold public key and each key share to separate files, so shares can be handed to each trustee.
+// Each share file also contains the public key to decrypt with.
+func generateThreshold(out string, now int64, length, threshold, numShares int, format string) error {
+	publicKey, shares, err := paillier.GenerateThresholdKeys(length, threshold, numShares)
+	if err != nil {
+		return err
+	}
+
+	public, err := encodeKey(publicKey, format)
+	if err != nil {
+		return err
+	}
+
+	fileName := filepath.Join(out, fmt.Sprintf("paillierThresholdKey_%d.json", now))
+	if err := writeKeyFile(fileName, KeyFile{Public: public}, 0644); err != nil {
+		return err
+	}
+
+	fmt.Printf("Fingerprint: %s\n", publicKey.Fingerprint())
+	fmt.Printf("Wrote %s\n", fileName)
+
+	for _, share := range shares {
+		encodedShare, err := encodeKey(share, format)
+		if err != nil {
+			return err
+		}
+
+		fileName := filepath.Join(out, fmt.Sprintf("paillierKeyShare_%d_%d.json", now, share.Index))
+		if err := writeKeyFile(fileName, KeyFile{Public: public, Share: encodedShare}, 0600); err != nil {
+			return err
+		}
+
+		fmt.Printf("Wrote %s\n", fileName)
+	}
+
+	return nil
+}
+
+// =============================================================================
+// Inspect
+// =============================================================================
+
+// Prints the details of every key provided and validates them.
+// Returns an error if any key is invalid, after all keys have been inspected.
+func inspect(args []string) error {
+	var (
+		flags    = flag.NewFlagSet("inspect", flag.ExitOnError)
+		keyFlags = &keyFlags{}
+	)
+	keyFlags.register(flags, true)
+	flags.StringVar(&keyFlags.thresholdPublic, "threshold-public", "", "threshold public key as base64 or JSON, or @path to read it from a file")
+	flags.Parse(args)
+
+	keys, err := keyFlags.load()
+	if err != nil {
+		return err
+	}
+
+	if keys.Public == nil && keys.Keystore == nil {
+		return errors.New("a key file, public key or keystore is required")
+	}
+
+	valid := true
+	report := func(name string, err error) {
+		if err != nil {
+			valid = false
+			fmt.Printf("  %s: invalid! %v\n", name, err)
+			return
+		}
+
+		fmt.Printf("  %s: valid\n", name)
+	}
+
+	publicKeyErr := paillier.ValidatePublicKey(keys.Public)
+
+	if keys.Public != nil {
+		fmt.Println("Public Key")
+
+		// The fingerprint & size of N can only be computed if the key has all of its values
+		if publicKeyErr == nil {
+			fmt.Printf("  Fingerprint: %s\n", keys.Public.Fingerprint())
+		}
+
+		fmt.Printf("  Length: %d bits per prime\n", keys.Public.Length)
+
+		if keys.Public.N != nil {
+			fmt.Printf("  N: %d bits\n", keys.Public.N.BitLen())
+		}
+
+		report("Validation", publicKeyErr)
+	}
+
+	if keys.ThresholdPublic != nil {
+		fmt.Println("Threshold")
+		fmt.Printf("  Shares required: %d of %d\n", keys.ThresholdPublic.Threshold, keys.ThresholdPublic.NumShares)
+
+		report("Validation", paillier.ValidateThresholdPublicKey(keys.ThresholdPublic))
+	}
+
+	if keys.Share != nil {
+		fmt.Println("Key Share")
+		fmt.Printf("  Index: %d\n", keys.Share.Index)
+
+		report("Validation", validateShare(keys.ThresholdPublic, keys.Share))
+	}
+
+	if keys.Private != nil {
+		fmt.Println("Private Key")
+
+		if keys.Public == nil || publicKeyErr != nil {
+			report("Validation", errors.New("a valid public key is required to validate the private key"))
+		} else {
+			report("Validation", paillier.ValidatePrivateKey(keys.Public, keys.Private))
+		}
+	}
+
+	if keys.Keystore != nil {
+		fmt.Println("Keystore")
+		fmt.Printf("  Version: %d\n", keys.Keystore.Version)
+		fmt.Printf("  KDF: %s (N=%d, r=%d, p=%d)\n", keys.Keystore.KDF, keys.Keystore.KDFParams.N, keys.Keystore.KDFParams.R, keys.Keystore.KDFParams.P)
+		fmt.Printf("  Cipher: %s\n", keys.Keystore.Cipher)
+		fmt.Printf("  Fingerprint: %s\n", keys.Keystore.PublicKeyFingerprint)
+
+		if keys.Public != nil && publicKeyErr == nil && keys.Public.Fingerprint() != keys.Keystore.PublicKeyFingerprint {
+			report("Validation", errors.New("keystore does not belong to the public key"))
+		} else if os.Getenv(PassphraseEnv) == "" {
+			fmt.Printf("  Validation: skipped, set %s to unlock the keystore\n", PassphraseEnv)
+		} else {
+			report("Validation", validateKeystore(keys))
+		}
+	}
+
+	if !valid {
+		return errors.New("keys are invalid")
+	}
+
+	return nil
+}
+
+func validateShare(publicKey *paillier.ThresholdPublicKey, share *paillier.KeyShare) error {
+	if share.Share == nil || share.Share.Sign() != 1 {
+		return errors.New("key share is missing a value")
+	}
+
+	if publicKey == nil {
+		return nil
+	}
+
+	if share.Index < 1 || share.Index > publicKey.NumShares {
+		return fmt.Errorf("index must satisfy 1 <= index <= %d", publicKey.NumShares)
+	}
+
+	if share.Length != publicKey.Length {
+		return errors.New("key share Length does not match the public key")
+	}
+
+	if publicKey.Delta == nil || publicKey.VerificationBase == nil || int64(len(publicKey.VerificationKeys)) != publicKey.NumShares {
+		return errors.New("threshold public key has no verification keys")
+	}
+
+	// v_i = v^(delta * s_i) % n^2
+	exponent := new(big.Int).Mul(publicKey.Delta, share.Share)
+	if new(big.Int).Exp(publicKey.VerificationBase, exponent, publicKey.NSquare).Cmp(publicKey.VerificationKeys[share.Index-1]) != 0 {
+		return errors.New("key share does not match its verification key")
+	}
+
+	return nil
+}
+
+// Unlocks the keystore and validates the private key against the public key if provided
+func validateKeystore(keys *keySet) error {
+	privateKey, err := paillier.UnlockPrivateKey(keys.Keystore, []byte(os.Getenv(PassphraseEnv)))
+	if err != nil {
+		return err
+	}
+
+	if keys.Public == nil || paillier.ValidatePublicKey(keys.Public) != nil {
+		return nil
+	}
+
+	return paillier.ValidatePrivateKey(keys.Public, privateKey)
+}
+
+// =============================================================================
+// Encrypt, Decrypt & Add
+// =============================================================================
+
+// Encrypts a plaintext and prints the ciphertext
+func encrypt(args []string) error {
+	var (
+		flags    = flag.NewFlagSet("encrypt", flag.ExitOnError)
+		keyFlags = &keyFlags{}
+		s        = flags.Int("s", 1, "Damgård–Jurik parameter, 1 for Paillier")
+		bind     = flags.Bool("bind", false, "prefix the ciphertext with the fingerprint of the public key")
+	)
+	keyFlags.register(flags, false)
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		return errors.New("usage: evote-keys encrypt [flags] value")
+	}
+
+	keys, err := keyFlags.load()
+	if err != nil {
+		return err
+	}
+
+	publicKey, err := keys.publicKey()
+	if err != nil {
+		return err
+	}
+
+	value, err := parseInt(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	var c *big.Int
+	if *s == 1 {
+		c, err = paillier.Encrypt(publicKey, value)
+	} else {
+		c, err = paillier.EncryptDJ(publicKey, *s, value)
+	}
+	if err != nil {
+		return err
+	}
+
+	printCiphertext(publicKey, c, *bind)
+
+	return nil
+}
+
+// Decrypts a ciphertext with the private key or keystore and prints the plaintext
+func decrypt(args []string) error {
+	var (
+		flags    = flag.NewFlagSet("decrypt", flag.ExitOnError)
+		keyFlags = &keyFlags{}
+		s        = flags.Int("s", 1, "Damgård–Jurik parameter, 1 for Paillier")
+	)
+	keyFlags.register(flags, true)
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		return errors.New("usage: evote-keys decrypt [flags] ciphertext")
+	}
+
+	keys, err := keyFlags.load()
+	if err != nil {
+		return err
+	}
+
+	publicKey, err := keys.publicKey()
+	if err != nil {
+		return err
+	}
+
+	privateKey, err := keys.privateKey(publicKey)
+	if err != nil {
+		return err
+	}
+
+	c, err := parseCiphertext(publicKey, flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	var m *big.Int
+	if *s == 1 {
+		m, err = paillier.Decrypt(publicKey, privateKey, c)
+	} else {
+		m, err = paillier.DecryptDJ(publicKey, privateKey, *s, c)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(m)
+
+	return nil
+}
+
+// Adds ciphertexts homomorphically and prints the ciphertext of the sum
+func add(args []string) error {
+	var (
+		flags    = flag.NewFlagSet("add", flag.ExitOnError)
+		keyFlags = &keyFlags{}
+		s        = flags.Int("s", 1, "Damgård–Jurik parameter, 1 for Paillier")
+		bind     = flags.Bool("bind", false, "prefix the ciphertext with the fingerprint of the public key")
+	)
+	keyFlags.register(flags, false)
+	flags.Parse(args)
+
+	if flags.NArg() < 1 {
+		return errors.New("usage: evote-keys add [flags] ciphertext...")
+	}
+
+	if *s < 1 {
+		return errors.New("s must be at least 1")
+	}
+
+	keys, err := keyFlags.load()
+	if err != nil {
+		return err
+	}
+
+	publicKey, err := keys.publicKey()
+	if err != nil {
+		return err
+	}
+
+	var sum *big.Int
+	for _, arg := range flags.Args() {
+		c, err := parseCiphertext(publicKey, arg)
+		if err != nil {
+			return err
+		}
+
+		if sum == nil {
+			sum = c
+			continue
+		}
+
+		sum = paillier.AddEncryptedDJ(publicKey, *s, sum, c)
+	}
+
+	printCiphertext(publicKey, sum, *bind)
+
+	return nil
+}
+
+// =============================================================================
+// Helpers
+// =============================================================================
+
+func parseInt(value string) (*big.Int, error) {
+	result, ok := new(big.Int).SetString(value, 10)
+	if !ok {
+		return nil, fmt.Errorf("failed to parse %s as an integer", value)
+	}
+
+	return result, nil
+}
+
+// Parses a ciphertext, which may be bound to a key as <fingerprint>:<value>.
+// Bound ciphertexts must be bound to publicKey.
+func parseCiphertext(publicKey *paillier.PublicKey, value string) (*big.Int, error) {
+	var ciphertext paillier.Ciphertext
+	if err := ciphertext.UnmarshalText([]byte(value)); err != nil {
+		return nil, err
+	}
+
+	if ciphertext.KeyID != "" && ciphertext.KeyID != publicKey.Fingerprint() {
+		return nil, &paillier.KeyMismatchError{ExpectedKeyID: publicKey.Fingerprint(), ActualKeyID: ciphertext.KeyID}
+	}
+
+	return ciphertext.Value, nil
+}
+
+func printCiphertext(publicKey *paillier.PublicKey, value *big.Int, bind bool) {
+	if bind {
+		fmt.Println(paillier.BindCiphertext(publicKey, value))
+		return
+	}
+
+	fmt.Println(value)
+}
@@ -0,0 +1,264 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	paillier "github.com/direnbharwani/evote-capstone/paillier"
+)
+
+const (
+	FormatBase64 = "base64"
+	FormatJSON   = "json"
+
+	PassphraseEnv = "KEYSTORE_PASSPHRASE"
+)
+
+// =============================================================================
+// Key Files
+// =============================================================================
+
+// Layout of the files written by generate.
+// Each key is either a base64 string or a JSON object, depending on the output format.
+type KeyFile struct {
+	Public  any `json:"Public,omitempty"`
+	Private any `json:"Private,omitempty"`
+	Share   any `json:"Share,omitempty"`
+}
+
+// KeyFile as read from disk, where each key is decoded once its type is known
+type rawKeyFile struct {
+	Public  json.RawMessage `json:"Public"`
+	Private json.RawMessage `json:"Private"`
+	Share   json.RawMessage `json:"Share"`
+}
+
+// Keys loaded from the flags shared by every command. Keys that were not provided are nil.
+type keySet struct {
+	Public          *paillier.PublicKey
+	Private         *paillier.PrivateKey
+	ThresholdPublic *paillier.ThresholdPublicKey
+	Share           *paillier.KeyShare
+	Keystore        *paillier.Keystore
+}
+
+// Flags used to provide keys to a command
+type keyFlags struct {
+	keys            string
+	public          string
+	private         string
+	thresholdPublic string
+	keystore        string
+}
+
+func (f *keyFlags) register(flags *flag.FlagSet, private bool) {
+	flags.StringVar(&f.keys, "keys", "", "path to a key file written by generate")
+	flags.StringVar(&f.public, "public", "", "public key as base64 or JSON, or @path to read it from a file")
+
+	if !private {
+		return
+	}
+
+	flags.StringVar(&f.private, "private", "", "private key as base64 or JSON, or @path to read it from a file")
+	flags.StringVar(&f.keystore, "keystore", "", "path to a keystore of the private key, unlocked with $"+PassphraseEnv)
+}
+
+// Loads every key that was provided.
+// Keys passed directly take precedence over the keys in the key file.
+func (f *keyFlags) load() (*keySet, error) {
+	keys := &keySet{}
+
+	if f.keys != "" {
+		if err := keys.loadFile(f.keys); err != nil {
+			return nil, err
+		}
+	}
+
+	if f.public != "" {
+		if err := decodeKeyArgument(f.public, &keys.Public); err != nil {
+			return nil, fmt.Errorf("failed to read public key: %w", err)
+		}
+	}
+
+	if f.thresholdPublic != "" {
+		if err := decodeKeyArgument(f.thresholdPublic, &keys.ThresholdPublic); err != nil {
+			return nil, fmt.Errorf("failed to read threshold public key: %w", err)
+		}
+
+		keys.Public = &keys.ThresholdPublic.PublicKey
+	}
+
+	if f.private != "" {
+		if err := decodeKeyArgument(f.private, &keys.Private); err != nil {
+			return nil, fmt.Errorf("failed to read private key: %w", err)
+		}
+	}
+
+	if f.keystore != "" {
+		keystore, err := paillier.LoadKeystore(f.keystore)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read keystore: %w", err)
+		}
+
+		keys.Keystore = keystore
+	}
+
+	return keys, nil
+}
+
+// Loads the keys of a file written by generate
+func (k *keySet) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var file rawKeyFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("failed to read key file %s: %w", path, err)
+	}
+
+	if len(file.Public) != 0 {
+		// Threshold public keys embed the public key, so the file is read as both
+		if err := decodeKey(file.Public, &k.Public); err != nil {
+			return fmt.Errorf("failed to read public key of %s: %w", path, err)
+		}
+
+		if err := decodeKey(file.Public, &k.ThresholdPublic); err != nil {
+			return fmt.Errorf("failed to read public key of %s: %w", path, err)
+		}
+
+		if k.ThresholdPublic.NumShares == 0 {
+			k.ThresholdPublic = nil
+		}
+	}
+
+	if len(file.Private) != 0 {
+		if err := decodeKey(file.Private, &k.Private); err != nil {
+			return fmt.Errorf("failed to read private key of %s: %w", path, err)
+		}
+	}
+
+	if len(file.Share) != 0 {
+		if err := decodeKey(file.Share, &k.Share); err != nil {
+			return fmt.Errorf("failed to read key share of %s: %w", path, err)
+		}
+	}
+
+	return nil
+}
+
+// Returns the private key, unlocking the keystore if no private key was provided.
+// Returns an error if the private key does not match the public key.
+func (k *keySet) privateKey(publicKey *paillier.PublicKey) (*paillier.PrivateKey, error) {
+	privateKey := k.Private
+
+	if privateKey == nil {
+		if k.Keystore == nil {
+			return nil, errors.New("a private key or keystore is required")
+		}
+
+		passphrase := os.Getenv(PassphraseEnv)
+		if passphrase == "" {
+			return nil, fmt.Errorf("%s must be set to unlock the keystore", PassphraseEnv)
+		}
+
+		var err error
+		if privateKey, err = paillier.UnlockPrivateKey(k.Keystore, []byte(passphrase)); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := paillier.ValidatePrivateKey(publicKey, privateKey); err != nil {
+		return nil, fmt.Errorf("invalid private key: %w", err)
+	}
+
+	return privateKey, nil
+}
+
+// Returns the public key or an error if it was not provided or is invalid
+func (k *keySet) publicKey() (*paillier.PublicKey, error) {
+	if k.Public == nil {
+		return nil, errors.New("a public key is required")
+	}
+
+	if err := paillier.ValidatePublicKey(k.Public); err != nil {
+		return nil, fmt.Errorf("invalid public key: %w", err)
+	}
+
+	return k.Public, nil
+}
+
+// =============================================================================
+// Encoding
+// =============================================================================
+
+// Decodes a key passed as a flag, which is read from a file if it starts with @
+func decodeKeyArgument[T paillier.ITYPES](argument string, key **T) error {
+	data := []byte(argument)
+
+	if path, found := strings.CutPrefix(argument, "@"); found {
+		var err error
+		if data, err = os.ReadFile(path); err != nil {
+			return err
+		}
+	}
+
+	data = bytes.TrimSpace(data)
+
+	// Keys in key files are JSON strings when base64 encoded
+	if len(data) != 0 && data[0] != '{' && data[0] != '"' {
+		data, _ = json.Marshal(string(data))
+	}
+
+	return decodeKey(data, key)
+}
+
+// Decodes a key that is either a JSON object or a JSON string containing the base64 encoded key
+func decodeKey[T paillier.ITYPES](data json.RawMessage, key **T) error {
+	var keyBase64 string
+	if err := json.Unmarshal(data, &keyBase64); err != nil {
+		result, err := paillier.DeserialiseFromJSON[T](data)
+		if err != nil {
+			return err
+		}
+
+		*key = result
+		return nil
+	}
+
+	result, err := paillier.Base64Decode[T](keyBase64)
+	if err != nil {
+		return err
+	}
+
+	*key = result
+	return nil
+}
+
+// Encodes a key in format, as a value to be written into a KeyFile
+func encodeKey[T paillier.ITYPES](key *T, format string) (any, error) {
+	switch format {
+	case FormatBase64:
+		return paillier.Base64Encode(key)
+	case FormatJSON:
+		return key, nil
+	default:
+		return nil, fmt.Errorf("unsupported format %s", format)
+	}
+}
+
+// Writes a KeyFile as indented JSON
+func writeKeyFile(path string, file KeyFile, perm os.FileMode) error {
+	data, err := json.MarshalIndent(file, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, append(data, '\n'), perm)
+}
@@ -0,0 +1,61 @@
+// evote-keys manages Paillier keys & ciphertexts offline, for key ceremonies and audits.
+//
+// Keys are read & written in the formats of the paillier package, i.e. base64 encoded JSON or plain JSON.
+// Any key flag accepts the key itself or @path to read it from a file.
+//
+// Usage:
+//
+//	evote-keys generate [-length 2048] [-format base64|json] [-out keys] [-shares n -threshold t]
+//	evote-keys inspect  [-keys file | -public key [-private key] | -threshold-public key] [-keystore file]
+//	evote-keys encrypt  (-keys file | -public key) [-s 1] value
+//	evote-keys decrypt  (-keys file | -public key -private key | -public key -keystore file) [-s 1] ciphertext
+//	evote-keys add      (-keys file | -public key) [-s 1] ciphertext...
+//
+// The passphrase of keystores is read from KEYSTORE_PASSPHRASE, so that it does not appear in the shell history.
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+const usage = `evote-keys manages Paillier keys & ciphertexts offline.
+
+Usage:
+  evote-keys <command> [flags] [arguments]
+
+Commands:
+  generate  generate a key pair, or a threshold key with key shares
+  inspect   print the fingerprint & length of keys and validate them
+  encrypt   encrypt a value
+  decrypt   decrypt a ciphertext
+  add       add ciphertexts
+
+Run "evote-keys <command> -h" for the flags of a command.
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	commands := map[string]func(args []string) error{
+		"generate": generate,
+		"inspect":  inspect,
+		"encrypt":  encrypt,
+		"decrypt":  decrypt,
+		"add":      add,
+	}
+
+	command, found := commands[os.Args[1]]
+	if !found {
+		fmt.Fprintf(os.Stderr, "unknown command %s\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+
+	if err := command(os.Args[2:]); err != nil {
+		fmt.Fprintf(os.Stderr, "evote-keys %s: %v\n", os.Args[1], err)
+		os.Exit(1)
+	}
+}
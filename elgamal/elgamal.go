@@ -0,0 +1,370 @@
+// Implementation of exponential ElGamal in Go.
+// Plaintexts are encoded in the exponent as g^m over the prime-order subgroup of Z*_p,
+// where p = 2q + 1 is a safe prime, so multiplying ciphertexts adds their plaintexts.
+// Decryption requires a discrete logarithm, which is only feasible for small plaintexts such as tallies.
+
+package elgamal
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+type ITYPES interface {
+	PublicKey | PrivateKey | Ciphertext
+
+	IsEqual(other interface{}) bool
+}
+
+// =============================================================================
+// Operations
+// =============================================================================
+
+// Generates a pair of public and private keys over a safe prime p of the given bit length.
+// g generates the subgroup of quadratic residues, which has prime order q = (p - 1) / 2.
+func GenerateKeys(length int) (*PublicKey, *PrivateKey, error) {
+	if length < 16 {
+		return nil, nil, errors.New("length must be greater than 16")
+	}
+
+	p, q, err := generateSafePrime(length)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The square of any element other than 1 & p - 1 generates the subgroup of order q
+	one := new(big.Int).SetInt64(1)
+
+	var g *big.Int
+	for {
+		a, err := randomExponent(p)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		g = new(big.Int).Exp(a, big.NewInt(2), p)
+		if g.Cmp(one) != 0 {
+			break
+		}
+	}
+
+	// h = g^x % p
+	x, err := randomExponent(q)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		h             = new(big.Int).Exp(g, x, p)
+		newPublicKey  = &PublicKey{p, q, g, h, int64(length)}
+		newPrivateKey = &PrivateKey{x, int64(length)}
+	)
+
+	return newPublicKey, newPrivateKey, nil
+}
+
+// Encrypts a given value using the public key.
+// Returns an error if rng fails or if value does not satisfy 0 <= value < Q
+func Encrypt(publicKey *PublicKey, value *big.Int) (*Ciphertext, error) {
+	c, _, err := EncryptWithRandomness(publicKey, value)
+	return c, err
+}
+
+// Encrypts a given value using the public key and returns the random exponent r used alongside the ciphertext.
+// Returns an error if rng fails or if value does not satisfy 0 <= value < Q
+func EncryptWithRandomness(publicKey *PublicKey, value *big.Int) (*Ciphertext, *big.Int, error) {
+	if value.Sign() < 0 || value.Cmp(publicKey.Q) != -1 {
+		return nil, nil, errors.New("value is too large to encrypt")
+	}
+
+	r, err := randomExponent(publicKey.Q)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// c1 = g^r % p
+	// c2 = g^m * h^r % p
+	var (
+		c1 = new(big.Int).Exp(publicKey.G, r, publicKey.P)
+		gM = new(big.Int).Exp(publicKey.G, value, publicKey.P)
+		hR = new(big.Int).Exp(publicKey.H, r, publicKey.P)
+		c2 = new(big.Int).Mod(new(big.Int).Mul(gM, hR), publicKey.P)
+	)
+
+	return &Ciphertext{c1, c2}, r, nil
+}
+
+// Decrypts a given ciphertext using a matching set of public & private keys.
+// The plaintext is recovered from g^m with a discrete logarithm, so it must satisfy 0 <= m <= maxValue.
+// Decryption takes O(sqrt(maxValue)) time & memory.
+func Decrypt(publicKey *PublicKey, privateKey *PrivateKey, ciphertext *Ciphertext, maxValue int64) (*big.Int, error) {
+	if err := ValidateCiphertext(publicKey, ciphertext); err != nil {
+		return nil, err
+	}
+
+	// g^m = c2 * (c1^x)^-1 % p
+	s := new(big.Int).Exp(ciphertext.C1, privateKey.X, publicKey.P)
+
+	sInverse := new(big.Int).ModInverse(s, publicKey.P)
+	if sInverse == nil {
+		return nil, errors.New("ciphertext is not invertible")
+	}
+
+	gM := new(big.Int).Mod(new(big.Int).Mul(ciphertext.C2, sInverse), publicKey.P)
+
+	return discreteLog(publicKey, gM, maxValue)
+}
+
+// Checks that a public key is well-formed, i.e. p = 2q + 1 are both prime and g & h are in the subgroup of order q
+func ValidatePublicKey(publicKey *PublicKey) error {
+	if publicKey == nil || publicKey.P == nil || publicKey.Q == nil || publicKey.G == nil || publicKey.H == nil {
+		return errors.New("public key is missing values")
+	}
+
+	one := new(big.Int).SetInt64(1)
+
+	if new(big.Int).Add(new(big.Int).Lsh(publicKey.Q, 1), one).Cmp(publicKey.P) != 0 {
+		return errors.New("public key P must equal 2 * Q + 1")
+	}
+
+	if int64(publicKey.P.BitLen()) != publicKey.Length {
+		return errors.New("public key Length does not match the size of P")
+	}
+
+	if !publicKey.P.ProbablyPrime(20) || !publicKey.Q.ProbablyPrime(20) {
+		return errors.New("public key P & Q must be prime")
+	}
+
+	for _, element := range []*big.Int{publicKey.G, publicKey.H} {
+		if element.Cmp(one) != 1 || element.Cmp(publicKey.P) != -1 {
+			return errors.New("public key G & H must satisfy 1 < x < P")
+		}
+
+		if new(big.Int).Exp(element, publicKey.Q, publicKey.P).Cmp(one) != 0 {
+			return errors.New("public key G & H must have order Q")
+		}
+	}
+
+	return nil
+}
+
+// Checks that both components of a ciphertext are elements of the subgroup of order q
+func ValidateCiphertext(publicKey *PublicKey, ciphertext *Ciphertext) error {
+	if ciphertext == nil || ciphertext.C1 == nil || ciphertext.C2 == nil {
+		return errors.New("ciphertext is missing values")
+	}
+
+	one := new(big.Int).SetInt64(1)
+
+	for _, element := range []*big.Int{ciphertext.C1, ciphertext.C2} {
+		if element.Sign() != 1 || element.Cmp(publicKey.P) != -1 {
+			return errors.New("ciphertext must satisfy 0 < c < P")
+		}
+
+		if new(big.Int).Exp(element, publicKey.Q, publicKey.P).Cmp(one) != 0 {
+			return errors.New("ciphertext must be in the subgroup of order Q")
+		}
+	}
+
+	return nil
+}
+
+// =============================================================================
+// Homomorphic Operations
+// =============================================================================
+
+func AddEncryptedWithPlain(publicKey *PublicKey, encrypted *Ciphertext, plain *big.Int) *Ciphertext {
+	// m1 + m2 = (c1, c2 * g^m2 % p)
+	gM := new(big.Int).Exp(publicKey.G, plain, publicKey.P)
+
+	return &Ciphertext{
+		new(big.Int).Set(encrypted.C1),
+		new(big.Int).Mod(new(big.Int).Mul(encrypted.C2, gM), publicKey.P),
+	}
+}
+
+func AddEncrypted(publicKey *PublicKey, lhs, rhs *Ciphertext) *Ciphertext {
+	// m1 + m2 = (c1 * c1' % p, c2 * c2' % p)
+	return &Ciphertext{
+		new(big.Int).Mod(new(big.Int).Mul(lhs.C1, rhs.C1), publicKey.P),
+		new(big.Int).Mod(new(big.Int).Mul(lhs.C2, rhs.C2), publicKey.P),
+	}
+}
+
+// =============================================================================
+// Helpers
+// =============================================================================
+
+// Generates a safe prime p = 2q + 1 of the given bit length.
+// Returns both p & q
+func generateSafePrime(length int) (*big.Int, *big.Int, error) {
+	one := new(big.Int).SetInt64(1)
+
+	for {
+		q, err := rand.Prime(rand.Reader, length-1)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		p := new(big.Int).Lsh(q, 1)
+		p.Add(p, one)
+
+		if p.BitLen() == length && p.ProbablyPrime(20) {
+			return p, q, nil
+		}
+	}
+}
+
+// Selects a random number such that 0 < r < n
+func randomExponent(n *big.Int) (*big.Int, error) {
+	for {
+		r, err := rand.Int(rand.Reader, n)
+		if err != nil {
+			return nil, err
+		}
+
+		if r.Sign() > 0 {
+			return r, nil
+		}
+	}
+}
+
+// Finds 0 <= m <= maxValue such that g^m = target % p with the baby-step giant-step algorithm
+func discreteLog(publicKey *PublicKey, target *big.Int, maxValue int64) (*big.Int, error) {
+	if maxValue < 0 {
+		return nil, errors.New("maxValue must not be negative")
+	}
+
+	// m = i * step + j for 0 <= i, j < step
+	step := new(big.Int).Sqrt(big.NewInt(maxValue)).Int64() + 1
+
+	// Baby steps: g^j for 0 <= j < step
+	babySteps := make(map[string]int64, step)
+
+	gJ := new(big.Int).SetInt64(1)
+	for j := int64(0); j < step; j++ {
+		if _, found := babySteps[gJ.String()]; !found {
+			babySteps[gJ.String()] = j
+		}
+		gJ.Mul(gJ, publicKey.G).Mod(gJ, publicKey.P)
+	}
+
+	// Giant steps: target * g^(-i * step) for 0 <= i < step
+	giantStep := new(big.Int).Exp(publicKey.G, big.NewInt(step), publicKey.P)
+	giantStep.ModInverse(giantStep, publicKey.P)
+
+	gamma := new(big.Int).Set(target)
+	for i := int64(0); i < step; i++ {
+		if j, found := babySteps[gamma.String()]; found {
+			if m := i*step + j; m <= maxValue {
+				return big.NewInt(m), nil
+			}
+		}
+		gamma.Mul(gamma, giantStep).Mod(gamma, publicKey.P)
+	}
+
+	return nil, fmt.Errorf("plaintext is larger than %d", maxValue)
+}
+
+// Computes the SHA-256 hash of the given values.
+// Each value is length-prefixed to avoid ambiguous encodings.
+func hashValues(values ...*big.Int) []byte {
+	hash := sha256.New()
+
+	for _, v := range values {
+		data := v.Bytes()
+
+		var length [8]byte
+		binary.BigEndian.PutUint64(length[:], uint64(len(data)))
+
+		hash.Write(length[:])
+		hash.Write(data)
+	}
+
+	return hash.Sum(nil)
+}
+
+func isEqualOptional(lhs, rhs *big.Int) bool {
+	if lhs == nil || rhs == nil {
+		return lhs == rhs
+	}
+
+	return lhs.Cmp(rhs) == 0
+}
+
+// =============================================================================
+// Public Key
+// =============================================================================
+
+// P = 2Q + 1 is a safe prime of Length bits, G generates the subgroup of order Q & H = G^X % P
+type PublicKey struct {
+	P      *big.Int `json:"P"`
+	Q      *big.Int `json:"Q"`
+	G      *big.Int `json:"G"`
+	H      *big.Int `json:"H"`
+	Length int64    `json:"Length"`
+}
+
+func (k PublicKey) IsEqual(other interface{}) bool {
+	otherObj, ok := other.(PublicKey)
+	if !ok {
+		return false
+	}
+
+	if !isEqualOptional(k.P, otherObj.P) || !isEqualOptional(k.Q, otherObj.Q) {
+		return false
+	}
+
+	if !isEqualOptional(k.G, otherObj.G) || !isEqualOptional(k.H, otherObj.H) {
+		return false
+	}
+
+	return k.Length == otherObj.Length
+}
+
+// Returns a hex encoded SHA-256 hash of P, G & H that identifies the public key
+func (k PublicKey) Fingerprint() string {
+	return hex.EncodeToString(hashValues(k.P, k.G, k.H))
+}
+
+// =============================================================================
+// Private Key
+// =============================================================================
+
+type PrivateKey struct {
+	X      *big.Int `json:"X"`
+	Length int64    `json:"Length"`
+}
+
+func (k PrivateKey) IsEqual(other interface{}) bool {
+	otherObj, ok := other.(PrivateKey)
+	if !ok {
+		return false
+	}
+
+	return isEqualOptional(k.X, otherObj.X) && k.Length == otherObj.Length
+}
+
+// =============================================================================
+// Ciphertext
+// =============================================================================
+
+// Ciphertext is the pair (g^r, g^m * h^r) % p
+type Ciphertext struct {
+	C1 *big.Int `json:"C1"`
+	C2 *big.Int `json:"C2"`
+}
+
+func (c Ciphertext) IsEqual(other interface{}) bool {
+	otherObj, ok := other.(Ciphertext)
+	if !ok {
+		return false
+	}
+
+	return isEqualOptional(c.C1, otherObj.C1) && isEqualOptional(c.C2, otherObj.C2)
+}
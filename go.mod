@@ -13,6 +13,7 @@ require (
 	github.com/hyperledger/fabric-contract-api-go v1.2.2
 	github.com/hyperledger/fabric-protos-go v0.3.3
 	github.com/stretchr/testify v1.9.0
+	golang.org/x/crypto v0.18.0
 	google.golang.org/protobuf v1.33.0
 )
 
@@ -0,0 +1,136 @@
+package paillier
+
+import (
+	"errors"
+	"math/big"
+	"sync"
+)
+
+// =============================================================================
+// Accumulator
+// =============================================================================
+
+// Accumulator adds ciphertexts under a single public key concurrently, keeping a separate total for each key,
+// e.g. one per candidate. Ciphertexts are distributed across workers that each multiply their own partial totals,
+// which are multiplied together once all ciphertexts have been added.
+// Add & Feed are safe for concurrent use until Result is called.
+type Accumulator struct {
+	publicKey *PublicKey
+	keyID     string
+	input     chan AccumulatorEntry
+	shards    []map[string]*big.Int
+	wg        sync.WaitGroup
+
+	// Guards input from being closed while ciphertexts are being added
+	mutex  sync.RWMutex
+	closed bool
+}
+
+// Ciphertext to add to the total of Key
+type AccumulatorEntry struct {
+	Key        string
+	Ciphertext *Ciphertext
+}
+
+// Creates an Accumulator for publicKey with numWorkers goroutines
+func NewAccumulator(publicKey *PublicKey, numWorkers int) (*Accumulator, error) {
+	if publicKey == nil {
+		return nil, errors.New("public key is required")
+	}
+
+	if numWorkers < 1 {
+		return nil, errors.New("numWorkers must be at least 1")
+	}
+
+	a := &Accumulator{
+		publicKey: publicKey,
+		keyID:     publicKey.Fingerprint(),
+		input:     make(chan AccumulatorEntry, numWorkers),
+		shards:    make([]map[string]*big.Int, numWorkers),
+	}
+
+	for i := range a.shards {
+		a.shards[i] = map[string]*big.Int{}
+
+		a.wg.Add(1)
+		go a.accumulate(a.shards[i])
+	}
+
+	return a, nil
+}
+
+// Adds a ciphertext to the total of key.
+// Returns an error if the ciphertext is bound to another key or if Result has been called.
+func (a *Accumulator) Add(key string, ciphertext *Ciphertext) error {
+	if err := checkKeyID(a.keyID, ciphertext); err != nil {
+		return err
+	}
+
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+
+	if a.closed {
+		return errors.New("accumulator is closed")
+	}
+
+	a.input <- AccumulatorEntry{key, ciphertext}
+
+	return nil
+}
+
+// Adds every entry received from entries until it is closed.
+// Returns the first error encountered, after which the remaining entries are not added.
+func (a *Accumulator) Feed(entries <-chan AccumulatorEntry) error {
+	for entry := range entries {
+		if err := a.Add(entry.Key, entry.Ciphertext); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Stops accepting ciphertexts, waits for the workers to finish and returns the total of each key.
+// Only keys that had at least one ciphertext added are present.
+// Totals are deterministic, i.e. independent of the number of workers & the order ciphertexts were added.
+func (a *Accumulator) Result() map[string]*Ciphertext {
+	a.mutex.Lock()
+	if !a.closed {
+		a.closed = true
+		close(a.input)
+	}
+	a.mutex.Unlock()
+
+	a.wg.Wait()
+
+	// Multiply the partial totals of each worker
+	totals := map[string]*Ciphertext{}
+	for _, shard := range a.shards {
+		for key, partial := range shard {
+			total, found := totals[key]
+			if !found {
+				totals[key] = &Ciphertext{a.keyID, new(big.Int).Set(partial)}
+				continue
+			}
+
+			total.Value = AddEncrypted(a.publicKey, total.Value, partial)
+		}
+	}
+
+	return totals
+}
+
+// Multiplies every ciphertext received into the worker's partial totals
+func (a *Accumulator) accumulate(shard map[string]*big.Int) {
+	defer a.wg.Done()
+
+	for entry := range a.input {
+		partial, found := shard[entry.Key]
+		if !found {
+			shard[entry.Key] = new(big.Int).Set(entry.Ciphertext.Value)
+			continue
+		}
+
+		partial.Mul(partial, entry.Ciphertext.Value).Mod(partial, a.publicKey.NSquare)
+	}
+}
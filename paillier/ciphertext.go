@@ -0,0 +1,183 @@
+package paillier
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// =============================================================================
+// Ciphertext
+// =============================================================================
+
+// Ciphertext is an encrypted value bound to the fingerprint of the public key it was encrypted with.
+// Homomorphic operations on Ciphertexts return an error if the fingerprints do not match,
+// which prevents combining values encrypted under different keys.
+//
+// Ciphertexts are marshalled as text in the form "<KeyID>:<Value>", with Value in base 10.
+// A base 10 value without a KeyID can be unmarshalled for compatibility, but cannot be used
+// in operations until it is bound to a key with BindCiphertext.
+type Ciphertext struct {
+	KeyID string
+	Value *big.Int
+}
+
+// Binds a raw ciphertext value to a public key
+func BindCiphertext(publicKey *PublicKey, value *big.Int) *Ciphertext {
+	return &Ciphertext{publicKey.Fingerprint(), new(big.Int).Set(value)}
+}
+
+// Encrypts a given value into a Ciphertext bound to the public key
+func EncryptCiphertext(publicKey *PublicKey, value *big.Int) (*Ciphertext, error) {
+	c, err := Encrypt(publicKey, value)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Ciphertext{publicKey.Fingerprint(), c}, nil
+}
+
+// Decrypts a Ciphertext using a matching set of public & private keys
+func DecryptCiphertext(publicKey *PublicKey, privateKey *PrivateKey, ciphertext *Ciphertext) (*big.Int, error) {
+	if err := checkKeyID(publicKey.Fingerprint(), ciphertext); err != nil {
+		return nil, err
+	}
+
+	return Decrypt(publicKey, privateKey, ciphertext.Value)
+}
+
+// =============================================================================
+// Homomorphic Operations
+// =============================================================================
+
+func AddCiphertexts(publicKey *PublicKey, lhs, rhs *Ciphertext) (*Ciphertext, error) {
+	keyID := publicKey.Fingerprint()
+	if err := checkKeyID(keyID, lhs, rhs); err != nil {
+		return nil, err
+	}
+
+	return &Ciphertext{keyID, AddEncrypted(publicKey, lhs.Value, rhs.Value)}, nil
+}
+
+func AddCiphertextWithPlain(publicKey *PublicKey, encrypted *Ciphertext, plain *big.Int) (*Ciphertext, error) {
+	keyID := publicKey.Fingerprint()
+	if err := checkKeyID(keyID, encrypted); err != nil {
+		return nil, err
+	}
+
+	return &Ciphertext{keyID, AddEncryptedWithPlain(publicKey, encrypted.Value, plain)}, nil
+}
+
+func SubCiphertexts(publicKey *PublicKey, lhs, rhs *Ciphertext) (*Ciphertext, error) {
+	keyID := publicKey.Fingerprint()
+	if err := checkKeyID(keyID, lhs, rhs); err != nil {
+		return nil, err
+	}
+
+	value, err := Sub(publicKey, lhs.Value, rhs.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Ciphertext{keyID, value}, nil
+}
+
+func MulCiphertextPlain(publicKey *PublicKey, encrypted *Ciphertext, plain *big.Int) (*Ciphertext, error) {
+	keyID := publicKey.Fingerprint()
+	if err := checkKeyID(keyID, encrypted); err != nil {
+		return nil, err
+	}
+
+	return &Ciphertext{keyID, MulPlain(publicKey, encrypted.Value, plain)}, nil
+}
+
+func RerandomizeCiphertext(publicKey *PublicKey, encrypted *Ciphertext) (*Ciphertext, error) {
+	keyID := publicKey.Fingerprint()
+	if err := checkKeyID(keyID, encrypted); err != nil {
+		return nil, err
+	}
+
+	value, err := Rerandomize(publicKey, encrypted.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Ciphertext{keyID, value}, nil
+}
+
+// =============================================================================
+// Marshalling
+// =============================================================================
+
+func (c Ciphertext) String() string {
+	if c.Value == nil {
+		return ""
+	}
+
+	if c.KeyID == "" {
+		return c.Value.String()
+	}
+
+	return c.KeyID + ":" + c.Value.String()
+}
+
+func (c Ciphertext) MarshalText() ([]byte, error) {
+	if c.Value == nil {
+		return nil, fmt.Errorf("ciphertext is missing a value")
+	}
+
+	return []byte(c.String()), nil
+}
+
+func (c *Ciphertext) UnmarshalText(data []byte) error {
+	keyID, valueText, found := strings.Cut(string(data), ":")
+	if !found {
+		keyID, valueText = "", keyID
+	}
+
+	value, ok := new(big.Int).SetString(valueText, 10)
+	if !ok {
+		return fmt.Errorf("failed to parse ciphertext value")
+	}
+
+	c.KeyID = keyID
+	c.Value = value
+
+	return nil
+}
+
+// =============================================================================
+// Helpers
+// =============================================================================
+
+// Ensures every ciphertext is bound to keyID
+func checkKeyID(keyID string, ciphertexts ...*Ciphertext) error {
+	for _, c := range ciphertexts {
+		if c == nil || c.Value == nil {
+			return fmt.Errorf("ciphertext is missing a value")
+		}
+
+		if c.KeyID != keyID {
+			return &KeyMismatchError{keyID, c.KeyID}
+		}
+	}
+
+	return nil
+}
+
+// =============================================================================
+// Errors
+// =============================================================================
+
+type KeyMismatchError struct {
+	ExpectedKeyID string
+	ActualKeyID   string
+}
+
+func (e *KeyMismatchError) Error() string {
+	if e.ActualKeyID == "" {
+		return fmt.Sprintf("ciphertext is not bound to a key! expected key %s", e.ExpectedKeyID)
+	}
+
+	return fmt.Sprintf("ciphertext was encrypted with key %s instead of %s", e.ActualKeyID, e.ExpectedKeyID)
+}
@@ -0,0 +1,162 @@
+// Damgård–Jurik generalisation of the Paillier Cryptosystem.
+// For a parameter s >= 1, ciphertexts are computed modulo n^(s+1) and plaintexts can be up to n^s,
+// using the same keys as Paillier. s = 1 is identical to Paillier.
+// This follows the scheme in "A Generalisation, a Simplification and Some Applications of
+// Paillier's Probabilistic Public-Key System" by Ivan Damgård & Mads Jurik (2001).
+
+package paillier
+
+import (
+	"crypto/rand"
+	"errors"
+	"io"
+	"math/big"
+)
+
+// =============================================================================
+// Operations
+// =============================================================================
+
+// Returns n^s, the size of the plaintext space for parameter s
+func PlaintextSpaceDJ(publicKey *PublicKey, s int) *big.Int {
+	return new(big.Int).Exp(publicKey.N, big.NewInt(int64(s)), nil)
+}
+
+// Returns n^(s+1), the modulus of ciphertexts for parameter s
+func CiphertextSpaceDJ(publicKey *PublicKey, s int) *big.Int {
+	return new(big.Int).Exp(publicKey.N, big.NewInt(int64(s+1)), nil)
+}
+
+// Encrypts a given value with parameter s using the public key.
+// Returns an error if rng fails or if value does not satisfy 0 <= value < n^s
+func EncryptDJ(publicKey *PublicKey, s int, value *big.Int) (*big.Int, error) {
+	c, _, err := EncryptDJWithReader(rand.Reader, publicKey, s, value)
+	return c, err
+}
+
+// Encrypts a given value with parameter s using the public key with the random number r drawn from reader.
+// Returns the ciphertext & r, which must be kept secret.
+func EncryptDJWithReader(reader io.Reader, publicKey *PublicKey, s int, value *big.Int) (*big.Int, *big.Int, error) {
+	if s < 1 {
+		return nil, nil, errors.New("s must be at least 1")
+	}
+
+	if value.Sign() < 0 || value.Cmp(PlaintextSpaceDJ(publicKey, s)) != -1 {
+		return nil, nil, errors.New("value is too large to encrypt")
+	}
+
+	// Select random number such that 0 < r < n and gcd(r,n) = 1
+	r, err := randomUnit(reader, publicKey.N)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Compute ciphertext
+	// c = (g^m * r^(n^s)) % n^(s+1)
+	var (
+		modulus = CiphertextSpaceDJ(publicKey, s)
+		gM      = new(big.Int).Exp(publicKey.G, value, modulus)
+		rN      = new(big.Int).Exp(r, PlaintextSpaceDJ(publicKey, s), modulus)
+		c       = new(big.Int).Mod(new(big.Int).Mul(gM, rN), modulus)
+	)
+
+	return c, r, nil
+}
+
+// Decrypts a given value with parameter s using a matching set of public & private keys.
+// Returns an error if value does not satisfy 0 < value < n^(s+1)
+func DecryptDJ(publicKey *PublicKey, privateKey *PrivateKey, s int, value *big.Int) (*big.Int, error) {
+	if s < 1 {
+		return nil, errors.New("s must be at least 1")
+	}
+
+	modulus := CiphertextSpaceDJ(publicKey, s)
+	if value.Sign() <= 0 || value.Cmp(modulus) != -1 {
+		return nil, errors.New("value is out of range")
+	}
+
+	plaintextSpace := PlaintextSpaceDJ(publicKey, s)
+
+	lambdaInverse := new(big.Int).ModInverse(privateKey.Lambda, plaintextSpace)
+	if lambdaInverse == nil {
+		return nil, errors.New("private key Lambda is not invertible")
+	}
+
+	// c^lambda = (1 + n)^(m * lambda) % n^(s+1), since r^(n^s * lambda) = 1
+	// m = log_(1+n)(c^lambda) * lambda^-1 % n^s
+	x := new(big.Int).Exp(value, privateKey.Lambda, modulus)
+
+	m := logOnePlusN(publicKey.N, s, x)
+	return m.Mul(m, lambdaInverse).Mod(m, plaintextSpace), nil
+}
+
+// =============================================================================
+// Homomorphic Operations
+// =============================================================================
+
+func AddEncryptedDJ(publicKey *PublicKey, s int, lhs, rhs *big.Int) *big.Int {
+	// m1 + m2 = m1 * m2 % n^(s+1)
+	return new(big.Int).Mod(new(big.Int).Mul(lhs, rhs), CiphertextSpaceDJ(publicKey, s))
+}
+
+func AddEncryptedWithPlainDJ(publicKey *PublicKey, s int, encrypted, plain *big.Int) *big.Int {
+	// m1 + m2 = m1 * g^m2 % n^(s+1)
+	modulus := CiphertextSpaceDJ(publicKey, s)
+
+	gPowerM2 := new(big.Int).Exp(publicKey.G, plain, modulus)
+	return new(big.Int).Mod(new(big.Int).Mul(encrypted, gPowerM2), modulus)
+}
+
+// Multiplies the plaintext of a ciphertext by a plain scalar.
+// Negative scalars are reduced modulo n^s, i.e. the result encrypts m * k % n^s.
+func MulPlainDJ(publicKey *PublicKey, s int, encrypted, plain *big.Int) *big.Int {
+	// m1 * k = m1^k % n^(s+1)
+	k := new(big.Int).Mod(plain, PlaintextSpaceDJ(publicKey, s))
+	return new(big.Int).Exp(encrypted, k, CiphertextSpaceDJ(publicKey, s))
+}
+
+// =============================================================================
+// Helpers
+// =============================================================================
+
+// Computes i from a = (1 + n)^i % n^(s+1), with the algorithm in section 3 of the paper.
+// i is recovered modulo n, n^2, ..., n^s in turn, where each step only requires L(a % n^(j+1)) = (a % n^(j+1) - 1) / n
+// and the binomial expansion of (1 + n)^i.
+func logOnePlusN(n *big.Int, s int, a *big.Int) *big.Int {
+	one := new(big.Int).SetInt64(1)
+
+	i := new(big.Int)
+	for j := 1; j <= s; j++ {
+		var (
+			nJ      = new(big.Int).Exp(n, big.NewInt(int64(j)), nil)
+			nJPlus1 = new(big.Int).Mul(nJ, n)
+		)
+
+		// t1 = L(a % n^(j+1))
+		t1 := new(big.Int).Mod(a, nJPlus1)
+		t1.Sub(t1, one).Div(t1, n)
+
+		// t1 = t1 - sum(binomial(i, k) * n^(k-1)) % n^j for 2 <= k <= j
+		t2 := new(big.Int).Set(i)
+		nK := new(big.Int).Set(one)
+		kFactorial := new(big.Int).Set(one)
+
+		for k := 2; k <= j; k++ {
+			i.Sub(i, one)
+			t2.Mul(t2, i).Mod(t2, nJ)
+
+			nK.Mul(nK, n)
+			kFactorial.Mul(kFactorial, big.NewInt(int64(k)))
+
+			// k! is invertible since k <= s is smaller than the prime factors of n
+			term := new(big.Int).Mul(t2, nK)
+			term.Mul(term, new(big.Int).ModInverse(kFactorial, nJ)).Mod(term, nJ)
+
+			t1.Sub(t1, term)
+		}
+
+		i = t1.Mod(t1, nJ)
+	}
+
+	return i
+}
@@ -0,0 +1,97 @@
+package paillier
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+)
+
+// =============================================================================
+// DRBG
+// =============================================================================
+
+// DRBG is a deterministic random bit generator that implements io.Reader.
+// It follows HMAC_DRBG with SHA-256 from NIST SP 800-90A, without reseeding or personalisation.
+// The same seed always produces the same stream of bytes, which allows randomised operations
+// to be reproduced, e.g. by every endorsing peer of a transaction or by tests with fixed vectors.
+//
+// The output is only as unpredictable as the seed, which must contain at least 32 bytes of secret entropy
+// for cryptographic use. A DRBG is not safe for concurrent use.
+type DRBG struct {
+	k []byte
+	v []byte
+}
+
+// Maximum number of bytes produced by a single Read, as specified by SP 800-90A
+const maxDRBGRequest = 1 << 16
+
+// Creates a DRBG seeded with seed
+func NewDRBG(seed []byte) (*DRBG, error) {
+	if len(seed) == 0 {
+		return nil, errors.New("seed must not be empty")
+	}
+
+	d := &DRBG{
+		k: make([]byte, sha256.Size),
+		v: make([]byte, sha256.Size),
+	}
+
+	// K = 0x00 00 ... 00
+	// V = 0x01 01 ... 01
+	for i := range d.v {
+		d.v[i] = 0x01
+	}
+
+	d.update(seed)
+
+	return d, nil
+}
+
+// Fills p with deterministic pseudo-random bytes. It never returns an error.
+func (d *DRBG) Read(p []byte) (int, error) {
+	for n := 0; n < len(p); {
+		end := n + maxDRBGRequest
+		if end > len(p) {
+			end = len(p)
+		}
+
+		d.generate(p[n:end])
+		n = end
+	}
+
+	return len(p), nil
+}
+
+// Fills out with V = HMAC(K, V) repeatedly, then updates the internal state
+func (d *DRBG) generate(out []byte) {
+	for n := 0; n < len(out); {
+		d.v = d.hmac(d.k, d.v)
+		n += copy(out[n:], d.v)
+	}
+
+	d.update(nil)
+}
+
+// K = HMAC(K, V || 0x00 || data)
+// V = HMAC(K, V)
+// If data is not empty, this is repeated with 0x01 instead of 0x00
+func (d *DRBG) update(data []byte) {
+	d.k = d.hmac(d.k, d.v, []byte{0x00}, data)
+	d.v = d.hmac(d.k, d.v)
+
+	if len(data) == 0 {
+		return
+	}
+
+	d.k = d.hmac(d.k, d.v, []byte{0x01}, data)
+	d.v = d.hmac(d.k, d.v)
+}
+
+func (d *DRBG) hmac(key []byte, data ...[]byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	for _, b := range data {
+		mac.Write(b)
+	}
+
+	return mac.Sum(nil)
+}
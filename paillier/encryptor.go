@@ -0,0 +1,132 @@
+package paillier
+
+import (
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"sync"
+)
+
+// =============================================================================
+// Encryptor
+// =============================================================================
+
+// Encryptor encrypts values under a single public key using a pool of precomputed randomness.
+// Computing r^n % n^2 is the most expensive step of encryption, so background goroutines
+// keep the pool filled and encryption is reduced to a single multiplication.
+// An Encryptor is safe for concurrent use and must be closed to stop its goroutines.
+type Encryptor struct {
+	publicKey *PublicKey
+	pool      chan precomputedRandomness
+	stop      chan struct{}
+	stopOnce  sync.Once
+	wg        sync.WaitGroup
+}
+
+// Random number r with r^n % n^2
+type precomputedRandomness struct {
+	r  *big.Int
+	rN *big.Int
+}
+
+// Creates an Encryptor for publicKey that keeps up to poolSize precomputed values,
+// filled by numWorkers goroutines.
+func NewEncryptor(publicKey *PublicKey, poolSize, numWorkers int) (*Encryptor, error) {
+	if publicKey == nil {
+		return nil, errors.New("public key is required")
+	}
+
+	if poolSize < 1 || numWorkers < 1 {
+		return nil, errors.New("poolSize & numWorkers must be at least 1")
+	}
+
+	e := &Encryptor{
+		publicKey: publicKey,
+		pool:      make(chan precomputedRandomness, poolSize),
+		stop:      make(chan struct{}),
+	}
+
+	for i := 0; i < numWorkers; i++ {
+		e.wg.Add(1)
+		go e.fill()
+	}
+
+	return e, nil
+}
+
+// Encrypts a given value using the Encryptor's public key.
+// Returns an error if rng fails or if value does not satisfy 0 <= value < N
+func (e *Encryptor) Encrypt(value *big.Int) (*big.Int, error) {
+	c, _, err := e.EncryptWithRandomness(value)
+	return c, err
+}
+
+// Encrypts a given value and returns the random number r used alongside the ciphertext.
+// If the pool is empty, r^n % n^2 is computed inline instead of waiting for the workers.
+func (e *Encryptor) EncryptWithRandomness(value *big.Int) (*big.Int, *big.Int, error) {
+	if value.Sign() < 0 || value.Cmp(e.publicKey.N) != -1 {
+		return nil, nil, errors.New("value is too large to encrypt")
+	}
+
+	var randomness precomputedRandomness
+
+	select {
+	case randomness = <-e.pool:
+	default:
+		var err error
+		if randomness, err = e.precompute(); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// c = (g^m * r^n) % n^2
+	// Since g = n + 1, g^m % n^2 = (1 + m * n) % n^2 which avoids an exponentiation
+	var gM *big.Int
+	if new(big.Int).Sub(e.publicKey.G, e.publicKey.N).Cmp(big.NewInt(1)) == 0 {
+		gM = new(big.Int).Mul(value, e.publicKey.N)
+		gM.Add(gM, big.NewInt(1)).Mod(gM, e.publicKey.NSquare)
+	} else {
+		gM = new(big.Int).Exp(e.publicKey.G, value, e.publicKey.NSquare)
+	}
+
+	c := new(big.Int).Mod(new(big.Int).Mul(gM, randomness.rN), e.publicKey.NSquare)
+
+	return c, randomness.r, nil
+}
+
+// Stops the background goroutines & waits for them to exit.
+// The Encryptor can still be used afterwards, but every encryption is computed inline.
+func (e *Encryptor) Close() {
+	e.stopOnce.Do(func() {
+		close(e.stop)
+	})
+	e.wg.Wait()
+}
+
+// Continuously precomputes randomness until the pool is full or the Encryptor is closed
+func (e *Encryptor) fill() {
+	defer e.wg.Done()
+
+	for {
+		randomness, err := e.precompute()
+		if err != nil {
+			return
+		}
+
+		select {
+		case e.pool <- randomness:
+		case <-e.stop:
+			return
+		}
+	}
+}
+
+// Selects a random number such that 0 < r < n and gcd(r, n) = 1, and computes r^n % n^2
+func (e *Encryptor) precompute() (precomputedRandomness, error) {
+	r, err := randomUnit(rand.Reader, e.publicKey.N)
+	if err != nil {
+		return precomputedRandomness{}, err
+	}
+
+	return precomputedRandomness{r, new(big.Int).Exp(r, e.publicKey.N, e.publicKey.NSquare)}, nil
+}
@@ -0,0 +1,217 @@
+package paillier
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	KeystoreVersion = 1
+	KeystoreKDF     = "scrypt"
+	KeystoreCipher  = "aes-256-gcm"
+)
+
+// Default scrypt parameters, as recommended for interactive logins.
+// Deriving a key uses 128 * N * R bytes of memory, i.e. 32MB.
+// Keystores are always written with these parameters, so larger parameters read from a keystore are rejected
+// instead of spending unbounded CPU & memory on a crafted keystore.
+const (
+	defaultScryptN = 1 << 15
+	defaultScryptR = 8
+	defaultScryptP = 1
+)
+
+// =============================================================================
+// Operations
+// =============================================================================
+
+// Encrypts a private key with a key derived from passphrase.
+// The private key is encrypted with AES-256-GCM under a key derived with scrypt from passphrase & a random salt.
+// The header (everything except the ciphertext) is authenticated, so it cannot be modified without detection.
+func LockPrivateKey(publicKey *PublicKey, privateKey *PrivateKey, passphrase []byte) (*Keystore, error) {
+	if len(passphrase) == 0 {
+		return nil, errors.New("passphrase must not be empty")
+	}
+
+	salt := make([]byte, 32)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	keystore := &Keystore{
+		KeystoreHeader: KeystoreHeader{
+			Version:              KeystoreVersion,
+			KDF:                  KeystoreKDF,
+			KDFParams:            ScryptParams{defaultScryptN, defaultScryptR, defaultScryptP, salt},
+			Cipher:               KeystoreCipher,
+			PublicKeyFingerprint: publicKey.Fingerprint(),
+		},
+	}
+
+	aead, err := keystore.aead(passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	keystore.Nonce = make([]byte, aead.NonceSize())
+	if _, err := rand.Read(keystore.Nonce); err != nil {
+		return nil, err
+	}
+
+	plaintext, err := SerializeToJSON(privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	additionalData, err := json.Marshal(keystore.KeystoreHeader)
+	if err != nil {
+		return nil, err
+	}
+
+	keystore.Ciphertext = aead.Seal(nil, keystore.Nonce, plaintext, additionalData)
+
+	return keystore, nil
+}
+
+// Decrypts the private key in a keystore with passphrase.
+// Returns an error if the passphrase is incorrect or the keystore has been modified.
+func UnlockPrivateKey(keystore *Keystore, passphrase []byte) (*PrivateKey, error) {
+	if keystore.Version != KeystoreVersion {
+		return nil, fmt.Errorf("unsupported keystore version %d", keystore.Version)
+	}
+
+	if keystore.KDF != KeystoreKDF || keystore.Cipher != KeystoreCipher {
+		return nil, fmt.Errorf("unsupported keystore algorithms %s & %s", keystore.KDF, keystore.Cipher)
+	}
+
+	if err := keystore.KDFParams.validate(); err != nil {
+		return nil, err
+	}
+
+	aead, err := keystore.aead(passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(keystore.Nonce) != aead.NonceSize() {
+		return nil, errors.New("keystore nonce is invalid")
+	}
+
+	additionalData, err := json.Marshal(keystore.KeystoreHeader)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := aead.Open(nil, keystore.Nonce, keystore.Ciphertext, additionalData)
+	if err != nil {
+		return nil, errors.New("unable to unlock keystore! passphrase is incorrect or keystore is corrupted")
+	}
+
+	return DeserialiseFromJSON[PrivateKey](plaintext)
+}
+
+// Writes a keystore to a file as JSON, readable only by the current user
+func SaveKeystore(path string, keystore *Keystore) error {
+	data, err := json.MarshalIndent(keystore, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0600)
+}
+
+// Reads a keystore from a JSON file
+func LoadKeystore(path string) (*Keystore, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return DeserialiseFromJSON[Keystore](data)
+}
+
+// =============================================================================
+// Keystore
+// =============================================================================
+
+// Versioned header of a keystore, describing how to derive the key & decrypt the private key
+type KeystoreHeader struct {
+	Version              int          `json:"Version"`
+	KDF                  string       `json:"KDF"`
+	KDFParams            ScryptParams `json:"KDFParams"`
+	Cipher               string       `json:"Cipher"`
+	PublicKeyFingerprint string       `json:"PublicKeyFingerprint"`
+}
+
+type ScryptParams struct {
+	N    int    `json:"N"`
+	R    int    `json:"R"`
+	P    int    `json:"P"`
+	Salt []byte `json:"Salt"`
+}
+
+// Ensures the parameters are no larger than the defaults used to write keystores
+func (p ScryptParams) validate() error {
+	if p.N > defaultScryptN || p.R > defaultScryptR || p.P > defaultScryptP {
+		return fmt.Errorf("keystore scrypt parameters must not exceed N=%d, R=%d & P=%d", defaultScryptN, defaultScryptR, defaultScryptP)
+	}
+
+	return nil
+}
+
+// Password-protected private key
+type Keystore struct {
+	KeystoreHeader
+	Nonce      []byte `json:"Nonce"`
+	Ciphertext []byte `json:"Ciphertext"`
+}
+
+func (k Keystore) IsEqual(other interface{}) bool {
+	otherObj, ok := other.(Keystore)
+	if !ok {
+		return false
+	}
+
+	if k.Version != otherObj.Version || k.KDF != otherObj.KDF || k.Cipher != otherObj.Cipher {
+		return false
+	}
+
+	if k.KDFParams.N != otherObj.KDFParams.N || k.KDFParams.R != otherObj.KDFParams.R || k.KDFParams.P != otherObj.KDFParams.P {
+		return false
+	}
+
+	if !bytes.Equal(k.KDFParams.Salt, otherObj.KDFParams.Salt) || k.PublicKeyFingerprint != otherObj.PublicKeyFingerprint {
+		return false
+	}
+
+	if !bytes.Equal(k.Nonce, otherObj.Nonce) || !bytes.Equal(k.Ciphertext, otherObj.Ciphertext) {
+		return false
+	}
+
+	return true
+}
+
+// Derives the AES-256-GCM cipher from passphrase with the keystore's scrypt parameters
+func (k Keystore) aead(passphrase []byte) (cipher.AEAD, error) {
+	params := k.KDFParams
+
+	key, err := scrypt.Key(passphrase, params.Salt, params.N, params.R, params.P, 32)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
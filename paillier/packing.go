@@ -0,0 +1,132 @@
+package paillier
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// =============================================================================
+// Packing
+// =============================================================================
+
+// Packing encodes a count for each of NumSlots candidates into a single plaintext.
+// The count of candidate i is stored in slot i as count_i * Base^i, so a vote for
+// candidate i encrypts Base^i and adding encrypted votes adds the counts of each slot.
+// Base must be greater than the largest possible count, i.e. the maximum number of voters,
+// so that a slot never overflows into the next one.
+type Packing struct {
+	Base     *big.Int
+	NumSlots int
+}
+
+// Creates a Packing for numSlots candidates that can count up to maxVoters votes per candidate.
+// Returns an error if the packed counts do not fit into the plaintext space of publicKey.
+func NewPacking(publicKey *PublicKey, maxVoters int64, numSlots int) (*Packing, error) {
+	if maxVoters < 1 {
+		return nil, errors.New("maxVoters must be at least 1")
+	}
+
+	packing := &Packing{new(big.Int).Add(big.NewInt(maxVoters), big.NewInt(1)), numSlots}
+	if err := packing.Validate(publicKey); err != nil {
+		return nil, err
+	}
+
+	return packing, nil
+}
+
+// Checks that the packing is well-formed and that Base^NumSlots <= N,
+// such that every packed value can be encrypted with publicKey
+func (p Packing) Validate(publicKey *PublicKey) error {
+	return p.validate(publicKey.N)
+}
+
+// Checks that the packing is well-formed and that Base^NumSlots <= n^s,
+// such that every packed value can be encrypted with publicKey & Damgård–Jurik parameter s.
+// This allows more slots or a larger base than Validate without a larger key.
+func (p Packing) ValidateDJ(publicKey *PublicKey, s int) error {
+	return p.validate(PlaintextSpaceDJ(publicKey, s))
+}
+
+// Checks that the packing is well-formed and that every packed value is smaller than plaintextSpace
+func (p Packing) validate(plaintextSpace *big.Int) error {
+	if p.Base == nil || p.Base.Cmp(big.NewInt(2)) == -1 {
+		return errors.New("packing base must be at least 2")
+	}
+
+	if p.NumSlots < 1 {
+		return errors.New("packing must have at least 1 slot")
+	}
+
+	if p.Capacity().Cmp(plaintextSpace) == 1 {
+		return fmt.Errorf("%d slots of base %s do not fit into the public key", p.NumSlots, p.Base)
+	}
+
+	return nil
+}
+
+// Returns Base^NumSlots, the smallest value that cannot be packed
+func (p Packing) Capacity() *big.Int {
+	return new(big.Int).Exp(p.Base, big.NewInt(int64(p.NumSlots)), nil)
+}
+
+// Returns the value of a single vote for the candidate in slot index, i.e. Base^index
+func (p Packing) Slot(index int) (*big.Int, error) {
+	if index < 0 || index >= p.NumSlots {
+		return nil, fmt.Errorf("slot %d is out of range", index)
+	}
+
+	return new(big.Int).Exp(p.Base, big.NewInt(int64(index)), nil), nil
+}
+
+// Returns the value of a single vote for each slot, in order
+func (p Packing) Slots() []*big.Int {
+	slots := make([]*big.Int, p.NumSlots)
+
+	slot := big.NewInt(1)
+	for i := range slots {
+		slots[i] = new(big.Int).Set(slot)
+		slot.Mul(slot, p.Base)
+	}
+
+	return slots
+}
+
+// Packs a count for each slot into a single value.
+// Each count must satisfy 0 <= count < Base.
+func (p Packing) Encode(counts []*big.Int) (*big.Int, error) {
+	if len(counts) != p.NumSlots {
+		return nil, fmt.Errorf("%d counts are required but %d were provided", p.NumSlots, len(counts))
+	}
+
+	packed := new(big.Int)
+
+	// Horner's method from the most significant slot
+	for i := len(counts) - 1; i >= 0; i-- {
+		if counts[i].Sign() < 0 || counts[i].Cmp(p.Base) != -1 {
+			return nil, fmt.Errorf("count of slot %d is out of range", i)
+		}
+
+		packed.Mul(packed, p.Base).Add(packed, counts[i])
+	}
+
+	return packed, nil
+}
+
+// Unpacks a value into the count of each slot.
+// Returns an error if the value does not satisfy 0 <= packed < Base^NumSlots.
+func (p Packing) Decode(packed *big.Int) ([]*big.Int, error) {
+	if packed.Sign() < 0 || packed.Cmp(p.Capacity()) != -1 {
+		return nil, errors.New("packed value is out of range")
+	}
+
+	counts := make([]*big.Int, p.NumSlots)
+
+	remainder := new(big.Int).Set(packed)
+	for i := range counts {
+		counts[i] = new(big.Int)
+		remainder.DivMod(remainder, p.Base, counts[i])
+	}
+
+	return counts, nil
+}
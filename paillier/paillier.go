@@ -8,12 +8,14 @@ package paillier
 
 import (
 	"crypto/rand"
+	"encoding/hex"
 	"errors"
+	"io"
 	"math/big"
 )
 
 type ITYPES interface {
-	PublicKey | PrivateKey
+	PublicKey | PrivateKey | ThresholdPublicKey | KeyShare | PartialDecryption | BinaryProof | SumProof | MembershipProof | KnowledgeProof | DecryptionProof | Keystore
 
 	IsEqual(other interface{}) bool
 }
@@ -26,6 +28,12 @@ type ITYPES interface {
 // This follows the key generation algorithm, which can be found here:
 // https://en.wikipedia.org/wiki/Paillier_cryptosystem
 func GenerateKeys(length int) (*PublicKey, *PrivateKey, error) {
+	return GenerateKeysWithReader(rand.Reader, length)
+}
+
+// Generates a pair of public and private keys with primes drawn from reader.
+// The same keys are generated for the same stream of bytes, e.g. from a DRBG with a fixed seed.
+func GenerateKeysWithReader(reader io.Reader, length int) (*PublicKey, *PrivateKey, error) {
 
 	if length < 16 {
 		return nil, nil, errors.New("length must be greater than 16")
@@ -34,13 +42,20 @@ func GenerateKeys(length int) (*PublicKey, *PrivateKey, error) {
 	var err error
 
 	// Generate prime numbers p & q based on given length
-	p, err := rand.Prime(rand.Reader, length)
+	p, err := randomPrime(reader, length)
 	if err != nil {
 		return nil, nil, err
 	}
-	q, err := rand.Prime(rand.Reader, length)
-	if err != nil {
-		return nil, nil, err
+	// p & q must be distinct, otherwise n is a perfect square that can be trivially factorised
+	var q *big.Int
+	for {
+		if q, err = randomPrime(reader, length); err != nil {
+			return nil, nil, err
+		}
+
+		if q.Cmp(p) != 0 {
+			break
+		}
 	}
 
 	one := new(big.Int).SetInt64(1)
@@ -67,7 +82,7 @@ func GenerateKeys(length int) (*PublicKey, *PrivateKey, error) {
 
 	var (
 		newPublicKey  = &PublicKey{n, nSquared, g, int64(length)}
-		newPrivateKey = &PrivateKey{lambda, mu, int64(length)}
+		newPrivateKey = &PrivateKey{lambda, mu, int64(length), p, q}
 	)
 
 	return newPublicKey, newPrivateKey, nil
@@ -76,28 +91,29 @@ func GenerateKeys(length int) (*PublicKey, *PrivateKey, error) {
 // Encrypts a given value using the public key.
 // Returns an error if rng fails or if value does not satisfy 0 <= value < N
 func Encrypt(publicKey *PublicKey, value *big.Int) (*big.Int, error) {
-	if value.Cmp(publicKey.N) != -1 {
-		return nil, errors.New("value is too large to encrypt")
-	}
-
-	one := new(big.Int).SetInt64(1)
-	length := new(big.Int).SetInt64(publicKey.Length)
+	c, _, err := EncryptWithRandomness(publicKey, value)
+	return c, err
+}
 
-	// Select random number such that 0 < r < n and gcd(r,n) = 1
-	// We keep trying until we get a valid number
-	// This step can be expensive due to this infinite loop
-	// TODO: Add timeout?
-	var err error
-	var r *big.Int
+// Encrypts a given value using the public key and returns the random number r used alongside the ciphertext.
+// r is required to prove properties of the ciphertext & must be kept secret.
+// Returns an error if rng fails or if value does not satisfy 0 <= value < N
+func EncryptWithRandomness(publicKey *PublicKey, value *big.Int) (*big.Int, *big.Int, error) {
+	return EncryptWithReader(rand.Reader, publicKey, value)
+}
 
-	for {
-		if r, err = rand.Int(rand.Reader, length); err != nil {
-			return nil, err
-		}
+// Encrypts a given value using the public key with the random number r drawn from reader.
+// Returns the ciphertext & r, which is required to prove properties of the ciphertext & must be kept secret.
+// Returns an error if reader fails or if value does not satisfy 0 <= value < N
+func EncryptWithReader(reader io.Reader, publicKey *PublicKey, value *big.Int) (*big.Int, *big.Int, error) {
+	if value.Sign() < 0 || value.Cmp(publicKey.N) != -1 {
+		return nil, nil, errors.New("value is too large to encrypt")
+	}
 
-		if gdc := new(big.Int).GCD(nil, nil, r, publicKey.N); gdc.Cmp(one) == 0 {
-			break
-		}
+	// Select random number such that 0 < r < n and gcd(r,n) = 1
+	r, err := randomUnit(reader, publicKey.N)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	// Compute ciphertext
@@ -108,16 +124,55 @@ func Encrypt(publicKey *PublicKey, value *big.Int) (*big.Int, error) {
 		c  = new(big.Int).Mod(new(big.Int).Mul(gM, rN), publicKey.NSquare)
 	)
 
-	return c, nil
+	return c, r, nil
+}
+
+// Generates a prime of the given bit length from reader, with the top two bits set.
+// This mirrors crypto/rand.Prime, which ignores custom readers in recent Go releases.
+func randomPrime(reader io.Reader, length int) (*big.Int, error) {
+	bits := uint(length % 8)
+	if bits == 0 {
+		bits = 8
+	}
+
+	data := make([]byte, (length+7)/8)
+	p := new(big.Int)
+
+	for {
+		if _, err := io.ReadFull(reader, data); err != nil {
+			return nil, err
+		}
+
+		// Clear the excess bits of the first byte, then set the top two bits so that p * q has 2 * length bits
+		data[0] &= uint8(int(1<<bits) - 1)
+		if bits >= 2 {
+			data[0] |= 3 << (bits - 2)
+		} else {
+			data[0] |= 1
+			data[1] |= 0x80
+		}
+
+		// Even numbers are never prime
+		data[len(data)-1] |= 1
+
+		if p.SetBytes(data); p.ProbablyPrime(20) {
+			return p, nil
+		}
+	}
 }
 
-// Encrypts a given value using a matching set of public & private keys.
+// Decrypts a given value using a matching set of public & private keys.
+// The faster CRT decryption is used if the private key contains the prime factors p & q.
 // Returns an error value if value >= n^2
 func Decrypt(publicKey *PublicKey, privateKey *PrivateKey, value *big.Int) (*big.Int, error) {
 	if value.Cmp(publicKey.NSquare) != -1 {
 		return nil, errors.New("value is too large to decrypt")
 	}
 
+	if privateKey.P != nil && privateKey.Q != nil {
+		return decryptCRT(publicKey, privateKey, value)
+	}
+
 	// Compute variables to decrypt
 	// L(x) = (x - 1)/n
 	// m 	= L * mu % n
@@ -134,13 +189,93 @@ func Decrypt(publicKey *PublicKey, privateKey *PrivateKey, value *big.Int) (*big
 	return m, nil
 }
 
+// Decrypts a given value modulo p^2 & q^2 separately and combines the results with the
+// Chinese Remainder Theorem. The exponents & moduli are half the size of those in Decrypt,
+// which makes this roughly 4 times faster.
+// With g = n + 1, h_p & h_q reduce to the inverses of q mod p & p mod q, which CRT needs anyway,
+// so no exponentiations are needed besides c^(p-1) & c^(q-1).
+// This follows the decryption algorithm in section 7 of Paillier's paper.
+func decryptCRT(publicKey *PublicKey, privateKey *PrivateKey, value *big.Int) (*big.Int, error) {
+	var (
+		p = privateKey.P
+		q = privateKey.Q
+	)
+
+	if new(big.Int).Mul(p, q).Cmp(publicKey.N) != 0 {
+		return nil, errors.New("private key factors do not match public key")
+	}
+
+	qInverse := new(big.Int).ModInverse(q, p)
+	pInverse := new(big.Int).ModInverse(p, q)
+	if qInverse == nil || pInverse == nil {
+		return nil, errors.New("private key factors must be distinct primes")
+	}
+
+	// g^(p-1) = 1 + (p-1) * n % p^2, hence L_p(g^(p-1) % p^2) = (p-1) * q = -q % p
+	// h_p = -q^-1 % p
+	// h_q = -p^-1 % q
+	hP := new(big.Int).Sub(p, qInverse)
+	hQ := new(big.Int).Sub(q, pInverse)
+
+	if new(big.Int).Add(publicKey.N, big.NewInt(1)).Cmp(publicKey.G) != 0 {
+		var err error
+		if hP, err = primeH(publicKey, p); err != nil {
+			return nil, err
+		}
+		if hQ, err = primeH(publicKey, q); err != nil {
+			return nil, err
+		}
+	}
+
+	// m_p = L_p(c^(p-1) % p^2) * h_p % p
+	// m_q = L_q(c^(q-1) % q^2) * h_q % q
+	mP := decryptModPrime(value, p, hP)
+	mQ := decryptModPrime(value, q, hQ)
+
+	// m = m_q + q * ((m_p - m_q) * q^-1 % p)
+	m := new(big.Int).Sub(mP, mQ)
+	m.Mul(m, qInverse).Mod(m, p)
+	m.Mul(m, q).Add(m, mQ)
+
+	return m, nil
+}
+
+// Decrypts a given value modulo a prime factor of n
+// L_p(x) = (x - 1)/p
+// m_p    = L_p(c^(p-1) % p^2) * h_p % p
+func decryptModPrime(value, prime, h *big.Int) *big.Int {
+	m := primeL(new(big.Int).Mod(value, new(big.Int).Mul(prime, prime)), prime)
+
+	return m.Mul(m, h).Mod(m, prime)
+}
+
+// Computes h_p = L_p(g^(p-1) % p^2)^-1 % p for keys where g is not n + 1
+func primeH(publicKey *PublicKey, prime *big.Int) (*big.Int, error) {
+	h := new(big.Int).ModInverse(primeL(new(big.Int).Mod(publicKey.G, new(big.Int).Mul(prime, prime)), prime), prime)
+	if h == nil {
+		return nil, errors.New("private key factors do not match public key")
+	}
+
+	return h, nil
+}
+
+// Computes L_p(x^(p-1) % p^2)
+func primeL(x, prime *big.Int) *big.Int {
+	one := new(big.Int).SetInt64(1)
+
+	primeMinus1 := new(big.Int).Sub(prime, one)
+
+	x = new(big.Int).Exp(x, primeMinus1, new(big.Int).Mul(prime, prime))
+	return x.Div(x.Sub(x, one), prime)
+}
+
 // =============================================================================
 // Homomorphic Operations
 // =============================================================================
 
 func AddEncryptedWithPlain(publicKey *PublicKey, encrypted, plain *big.Int) *big.Int {
 	// m1 + m2 = m1 * g^m2 % n^2
-	gPowerM2 := new(big.Int).Exp(publicKey.G, plain, nil)
+	gPowerM2 := new(big.Int).Exp(publicKey.G, plain, publicKey.NSquare)
 	return new(big.Int).Mod(new(big.Int).Mul(encrypted, gPowerM2), publicKey.NSquare)
 }
 
@@ -149,6 +284,50 @@ func AddEncrypted(publicKey *PublicKey, lhs, rhs *big.Int) *big.Int {
 	return new(big.Int).Mod(new(big.Int).Mul(lhs, rhs), publicKey.NSquare)
 }
 
+// Multiplies the plaintext of a ciphertext by a plain scalar.
+// Negative scalars are reduced modulo n, i.e. the result encrypts m * k % n.
+func MulPlain(publicKey *PublicKey, encrypted, plain *big.Int) *big.Int {
+	// m1 * k = m1^k % n^2
+	k := new(big.Int).Mod(plain, publicKey.N)
+	return new(big.Int).Exp(encrypted, k, publicKey.NSquare)
+}
+
+// Negates the plaintext of a ciphertext, i.e. the result encrypts -m % n.
+// Returns an error if the ciphertext is not invertible modulo n^2.
+func Negate(publicKey *PublicKey, encrypted *big.Int) (*big.Int, error) {
+	// -m1 = m1^-1 % n^2
+	inverse := new(big.Int).ModInverse(encrypted, publicKey.NSquare)
+	if inverse == nil {
+		return nil, errors.New("ciphertext is not invertible")
+	}
+
+	return inverse, nil
+}
+
+// Subtracts the plaintext of rhs from lhs, i.e. the result encrypts (m1 - m2) % n.
+// Returns an error if rhs is not invertible modulo n^2.
+func Sub(publicKey *PublicKey, lhs, rhs *big.Int) (*big.Int, error) {
+	// m1 - m2 = m1 * m2^-1 % n^2
+	negated, err := Negate(publicKey, rhs)
+	if err != nil {
+		return nil, err
+	}
+
+	return AddEncrypted(publicKey, lhs, negated), nil
+}
+
+// Multiplies a ciphertext by a fresh r^n % n^2, which produces a new ciphertext of the same plaintext.
+// The result cannot be linked to the original ciphertext without the private key.
+func Rerandomize(publicKey *PublicKey, encrypted *big.Int) (*big.Int, error) {
+	r, err := randomUnit(rand.Reader, publicKey.N)
+	if err != nil {
+		return nil, err
+	}
+
+	rN := new(big.Int).Exp(r, publicKey.N, publicKey.NSquare)
+	return AddEncrypted(publicKey, encrypted, rN), nil
+}
+
 // =============================================================================
 // Public Key
 // =============================================================================
@@ -185,14 +364,23 @@ func (k PublicKey) IsEqual(other interface{}) bool {
 	return true
 }
 
+// Returns a hex encoded SHA-256 hash of N & G that identifies the public key
+func (k PublicKey) Fingerprint() string {
+	return hex.EncodeToString(hashValues(k.N, k.G))
+}
+
 // =============================================================================
 // Private Key
 // =============================================================================
 
+// P & Q are optional for compatibility with keys that only contain Lambda & Mu.
+// They enable faster CRT decryption when present.
 type PrivateKey struct {
 	Lambda *big.Int `json:"Lambda"`
 	Mu     *big.Int `json:"Mu"`
 	Length int64    `json:"Length"`
+	P      *big.Int `json:"P,omitempty"`
+	Q      *big.Int `json:"Q,omitempty"`
 }
 
 func (k PrivateKey) IsEqual(other interface{}) bool {
@@ -213,5 +401,31 @@ func (k PrivateKey) IsEqual(other interface{}) bool {
 		return false
 	}
 
+	if !isEqualOptional(k.P, otherObj.P) || !isEqualOptional(k.Q, otherObj.Q) {
+		return false
+	}
+
+	return true
+}
+
+func isEqualOptional(lhs, rhs *big.Int) bool {
+	if lhs == nil || rhs == nil {
+		return lhs == rhs
+	}
+
+	return lhs.Cmp(rhs) == 0
+}
+
+func isEqualSlice(lhs, rhs []*big.Int) bool {
+	if len(lhs) != len(rhs) {
+		return false
+	}
+
+	for i := range lhs {
+		if !isEqualOptional(lhs[i], rhs[i]) {
+			return false
+		}
+	}
+
 	return true
 }
@@ -0,0 +1,942 @@
+package paillier_test
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	paillier "github.com/direnbharwani/evote-capstone/paillier"
+
+	"github.com/stretchr/testify/require"
+)
+
+// =============================================================================
+// Threshold Tests
+// =============================================================================
+
+func TestThresholdDecryption(t *testing.T) {
+	publicKey, shares, err := paillier.GenerateThresholdKeys(64, 3, 5)
+	require.NoError(t, err)
+
+	plain := big.NewInt(42)
+	encrypted, err := paillier.Encrypt(&publicKey.PublicKey, plain)
+	require.NoError(t, err)
+
+	t.Run("successfully combine threshold partial decryptions", func(t *testing.T) {
+		partials := []*paillier.PartialDecryption{}
+		for _, i := range []int{4, 0, 2} {
+			partial, err := paillier.PartialDecrypt(publicKey, shares[i], encrypted)
+			require.NoError(t, err)
+
+			partials = append(partials, partial)
+		}
+
+		result, err := paillier.CombinePartialDecryptions(publicKey, encrypted, partials)
+		require.NoError(t, err)
+		require.Equal(t, 0, plain.Cmp(result))
+	})
+
+	t.Run("fail to combine fewer than threshold partial decryptions", func(t *testing.T) {
+		partials := []*paillier.PartialDecryption{}
+		for _, i := range []int{1, 3} {
+			partial, err := paillier.PartialDecrypt(publicKey, shares[i], encrypted)
+			require.NoError(t, err)
+
+			partials = append(partials, partial)
+		}
+
+		_, err := paillier.CombinePartialDecryptions(publicKey, encrypted, partials)
+		require.EqualError(t, err, "3 partial decryptions are required but only 2 were provided")
+	})
+
+	t.Run("fail to combine duplicate partial decryptions", func(t *testing.T) {
+		partial, err := paillier.PartialDecrypt(publicKey, shares[0], encrypted)
+		require.NoError(t, err)
+
+		_, err = paillier.CombinePartialDecryptions(publicKey, encrypted, []*paillier.PartialDecryption{partial, partial, partial})
+		require.EqualError(t, err, "3 partial decryptions with distinct indices are required but only 1 were provided")
+	})
+
+	t.Run("successfully skip missing, duplicate & out of range partial decryptions", func(t *testing.T) {
+		partials := []*paillier.PartialDecryption{}
+		for _, i := range []int{1, 1, 3, 4} {
+			partial, err := paillier.PartialDecrypt(publicKey, shares[i], encrypted)
+			require.NoError(t, err)
+
+			partials = append(partials, partial)
+		}
+
+		outOfRange := *partials[0]
+		outOfRange.Index = publicKey.NumShares + 1
+		partials = append([]*paillier.PartialDecryption{nil, &outOfRange}, partials...)
+
+		result, err := paillier.CombinePartialDecryptions(publicKey, encrypted, partials)
+		require.NoError(t, err)
+		require.Equal(t, 0, plain.Cmp(result))
+	})
+
+	t.Run("fail to combine a forged partial decryption", func(t *testing.T) {
+		partials := []*paillier.PartialDecryption{}
+		for _, i := range []int{0, 1, 2} {
+			partial, err := paillier.PartialDecrypt(publicKey, shares[i], encrypted)
+			require.NoError(t, err)
+
+			partials = append(partials, partial)
+		}
+
+		// A trustee shifts the plaintext by multiplying their partial decryption
+		partials[1].Value.Mul(partials[1].Value, publicKey.G).Mod(partials[1].Value, publicKey.NSquare)
+		require.False(t, paillier.VerifyPartialDecryption(publicKey, encrypted, partials[1]))
+
+		_, err := paillier.CombinePartialDecryptions(publicKey, encrypted, partials)
+		require.EqualError(t, err, "partial decryption 2 has an invalid proof")
+	})
+
+	t.Run("successfully validate generated threshold public key", func(t *testing.T) {
+		require.NoError(t, paillier.ValidateThresholdPublicKey(publicKey))
+	})
+
+	t.Run("fail to validate malformed threshold public keys", func(t *testing.T) {
+		threshold := *publicKey
+		threshold.Threshold = threshold.NumShares + 1
+		require.EqualError(t, paillier.ValidateThresholdPublicKey(&threshold), "threshold public key must satisfy 1 <= Threshold <= NumShares")
+
+		delta := *publicKey
+		delta.Delta = new(big.Int).Add(publicKey.Delta, big.NewInt(1))
+		require.EqualError(t, paillier.ValidateThresholdPublicKey(&delta), "threshold public key Delta must equal the factorial of NumShares")
+
+		missing := *publicKey
+		missing.VerificationKeys = publicKey.VerificationKeys[1:]
+		require.EqualError(t, paillier.ValidateThresholdPublicKey(&missing), "threshold public key must have a verification key for every share")
+
+		outOfRange := *publicKey
+		outOfRange.VerificationKeys = append([]*big.Int{publicKey.NSquare}, publicKey.VerificationKeys[1:]...)
+		require.EqualError(t, paillier.ValidateThresholdPublicKey(&outOfRange), "threshold public key verification keys must be in Z*_{n^2}")
+	})
+
+	t.Run("fail to verify a partial decryption of another ciphertext", func(t *testing.T) {
+		other, err := paillier.Encrypt(&publicKey.PublicKey, big.NewInt(7))
+		require.NoError(t, err)
+
+		partial, err := paillier.PartialDecrypt(publicKey, shares[0], encrypted)
+		require.NoError(t, err)
+
+		require.True(t, paillier.VerifyPartialDecryption(publicKey, encrypted, partial))
+		require.False(t, paillier.VerifyPartialDecryption(publicKey, other, partial))
+	})
+}
+
+// =============================================================================
+// Proof Tests
+// =============================================================================
+
+func TestBinaryProof(t *testing.T) {
+	publicKey, _, err := paillier.GenerateKeys(64)
+	require.NoError(t, err)
+
+	t.Run("successfully verify proofs for 0 and 1", func(t *testing.T) {
+		for _, value := range []int64{0, 1} {
+			encrypted, randomness, err := paillier.EncryptWithRandomness(publicKey, big.NewInt(value))
+			require.NoError(t, err)
+
+			proof, err := paillier.ProveBinary(publicKey, encrypted, big.NewInt(value), randomness)
+			require.NoError(t, err)
+			require.True(t, paillier.VerifyBinary(publicKey, encrypted, proof))
+		}
+	})
+
+	t.Run("fail to verify proof for a different ciphertext", func(t *testing.T) {
+		encrypted, randomness, err := paillier.EncryptWithRandomness(publicKey, big.NewInt(1))
+		require.NoError(t, err)
+
+		proof, err := paillier.ProveBinary(publicKey, encrypted, big.NewInt(1), randomness)
+		require.NoError(t, err)
+
+		// Adding votes to a well-formed ciphertext must invalidate the proof
+		tampered := paillier.AddEncryptedWithPlain(publicKey, encrypted, big.NewInt(1000))
+		require.False(t, paillier.VerifyBinary(publicKey, tampered, proof))
+	})
+
+	t.Run("fail to verify forged proof with a branch challenge outside of the challenge range", func(t *testing.T) {
+		encrypted, err := paillier.Encrypt(publicKey, big.NewInt(1000))
+		require.NoError(t, err)
+
+		u := []*big.Int{
+			encrypted,
+			paillier.AddEncryptedWithPlain(publicKey, encrypted, new(big.Int).Sub(publicKey.N, big.NewInt(1))),
+		}
+		a, e, z := forgeORProof(t, publicKey, u, func(commitments []*big.Int) []*big.Int {
+			return []*big.Int{publicKey.N, encrypted, commitments[0], commitments[1]}
+		})
+
+		proof := &paillier.BinaryProof{A0: a[0], A1: a[1], E0: e[0], E1: e[1], Z0: z[0], Z1: z[1]}
+		require.False(t, paillier.VerifyBinary(publicKey, encrypted, proof))
+	})
+
+	t.Run("fail to prove values other than 0 or 1", func(t *testing.T) {
+		encrypted, randomness, err := paillier.EncryptWithRandomness(publicKey, big.NewInt(2))
+		require.NoError(t, err)
+
+		_, err = paillier.ProveBinary(publicKey, encrypted, big.NewInt(2), randomness)
+		require.EqualError(t, err, "value must be either 0 or 1")
+	})
+
+	t.Run("successfully shorten challenges below the prime factors of small keys", func(t *testing.T) {
+		encrypted, randomness, err := paillier.EncryptWithRandomness(publicKey, big.NewInt(1))
+		require.NoError(t, err)
+
+		proof, err := paillier.ProveBinary(publicKey, encrypted, big.NewInt(1), randomness)
+		require.NoError(t, err)
+
+		// The primes of a 64 bit key have 64 bits, so the challenges have at most 63 bits
+		require.LessOrEqual(t, proof.E0.BitLen(), 63)
+		require.LessOrEqual(t, proof.E1.BitLen(), 63)
+	})
+}
+
+func TestSumProof(t *testing.T) {
+	publicKey, _, err := paillier.GenerateKeys(64)
+	require.NoError(t, err)
+
+	ciphertexts := []*big.Int{}
+	randomness := []*big.Int{}
+	for _, value := range []int64{0, 1, 0} {
+		encrypted, r, err := paillier.EncryptWithRandomness(publicKey, big.NewInt(value))
+		require.NoError(t, err)
+
+		ciphertexts = append(ciphertexts, encrypted)
+		randomness = append(randomness, r)
+	}
+
+	t.Run("successfully verify sum equals one", func(t *testing.T) {
+		proof, err := paillier.ProveSumEqualsOne(publicKey, ciphertexts, randomness)
+		require.NoError(t, err)
+		require.True(t, paillier.VerifySumEqualsOne(publicKey, ciphertexts, proof))
+	})
+
+	t.Run("fail to verify incorrect sum", func(t *testing.T) {
+		proof, err := paillier.ProveSum(publicKey, ciphertexts, randomness, big.NewInt(2))
+		require.NoError(t, err)
+		require.False(t, paillier.VerifySum(publicKey, ciphertexts, big.NewInt(2), proof))
+	})
+}
+
+func TestKnowledgeProof(t *testing.T) {
+	publicKey, _, err := paillier.GenerateKeys(64)
+	require.NoError(t, err)
+
+	encrypted, randomness, err := paillier.EncryptWithRandomness(publicKey, big.NewInt(5))
+	require.NoError(t, err)
+
+	proof, err := paillier.ProvePlaintextKnowledge(publicKey, encrypted, big.NewInt(5), randomness, "ballot1")
+	require.NoError(t, err)
+
+	t.Run("successfully verify knowledge of plaintext", func(t *testing.T) {
+		require.True(t, paillier.VerifyPlaintextKnowledge(publicKey, encrypted, "ballot1", proof))
+	})
+
+	t.Run("fail to verify copied ciphertext under a different context", func(t *testing.T) {
+		require.False(t, paillier.VerifyPlaintextKnowledge(publicKey, encrypted, "ballot2", proof))
+	})
+
+	t.Run("fail to verify proof for a different ciphertext", func(t *testing.T) {
+		// Rerandomising a copied ciphertext must not allow the proof to be reused
+		rerandomized, err := paillier.Rerandomize(publicKey, encrypted)
+		require.NoError(t, err)
+		require.False(t, paillier.VerifyPlaintextKnowledge(publicKey, rerandomized, "ballot1", proof))
+	})
+
+	t.Run("fail to verify proof with incorrect randomness", func(t *testing.T) {
+		forged, err := paillier.ProvePlaintextKnowledge(publicKey, encrypted, big.NewInt(5), big.NewInt(2), "ballot1")
+		require.NoError(t, err)
+		require.False(t, paillier.VerifyPlaintextKnowledge(publicKey, encrypted, "ballot1", forged))
+	})
+}
+
+func TestDecryptionProof(t *testing.T) {
+	publicKey, privateKey, err := paillier.GenerateKeys(64)
+	require.NoError(t, err)
+
+	encrypted, err := paillier.Encrypt(publicKey, big.NewInt(7))
+	require.NoError(t, err)
+
+	plaintext, proof, err := paillier.ProveDecryption(publicKey, privateKey, encrypted)
+	require.NoError(t, err)
+
+	t.Run("successfully verify decryption", func(t *testing.T) {
+		require.Equal(t, int64(7), plaintext.Int64())
+		require.True(t, paillier.VerifyDecryption(publicKey, encrypted, plaintext, proof))
+	})
+
+	t.Run("fail to verify incorrect plaintext", func(t *testing.T) {
+		require.False(t, paillier.VerifyDecryption(publicKey, encrypted, big.NewInt(8), proof))
+	})
+}
+
+// =============================================================================
+// Decryption Tests
+// =============================================================================
+
+func TestDecrypt(t *testing.T) {
+	publicKey, privateKey, err := paillier.GenerateKeys(128)
+	require.NoError(t, err)
+
+	encrypted, err := paillier.Encrypt(publicKey, big.NewInt(123456))
+	require.NoError(t, err)
+
+	t.Run("successfully decrypt with prime factors", func(t *testing.T) {
+		result, err := paillier.Decrypt(publicKey, privateKey, encrypted)
+		require.NoError(t, err)
+		require.Equal(t, int64(123456), result.Int64())
+	})
+
+	t.Run("successfully decrypt with key without prime factors", func(t *testing.T) {
+		data, err := json.Marshal(map[string]interface{}{
+			"Lambda": privateKey.Lambda,
+			"Mu":     privateKey.Mu,
+			"Length": privateKey.Length,
+		})
+		require.NoError(t, err)
+
+		legacyKey, err := paillier.DeserialiseFromJSON[paillier.PrivateKey](data)
+		require.NoError(t, err)
+		require.Nil(t, legacyKey.P)
+
+		result, err := paillier.Decrypt(publicKey, legacyKey, encrypted)
+		require.NoError(t, err)
+		require.Equal(t, int64(123456), result.Int64())
+	})
+
+	t.Run("successfully decrypt with prime factors when g is not n + 1", func(t *testing.T) {
+		// g = (n + 1)^2 = 1 + 2n % n^2
+		otherKey := *publicKey
+		otherKey.G = new(big.Int).Add(new(big.Int).Lsh(publicKey.N, 1), big.NewInt(1))
+
+		otherEncrypted, err := paillier.Encrypt(&otherKey, big.NewInt(123456))
+		require.NoError(t, err)
+
+		result, err := paillier.Decrypt(&otherKey, privateKey, otherEncrypted)
+		require.NoError(t, err)
+		require.Equal(t, int64(123456), result.Int64())
+	})
+}
+
+// =============================================================================
+// Encryptor Tests
+// =============================================================================
+
+func TestEncryptor(t *testing.T) {
+	publicKey, privateKey, err := paillier.GenerateKeys(64)
+	require.NoError(t, err)
+
+	encryptor, err := paillier.NewEncryptor(publicKey, 8, 2)
+	require.NoError(t, err)
+	defer encryptor.Close()
+
+	t.Run("successfully encrypt with precomputed randomness", func(t *testing.T) {
+		for i := int64(0); i < 16; i++ {
+			encrypted, err := encryptor.Encrypt(big.NewInt(i))
+			require.NoError(t, err)
+
+			result, err := paillier.Decrypt(publicKey, privateKey, encrypted)
+			require.NoError(t, err)
+			require.Equal(t, i, result.Int64())
+		}
+	})
+
+	t.Run("successfully encrypt after closing", func(t *testing.T) {
+		encryptor.Close()
+
+		encrypted, err := encryptor.Encrypt(big.NewInt(1))
+		require.NoError(t, err)
+
+		result, err := paillier.Decrypt(publicKey, privateKey, encrypted)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), result.Int64())
+	})
+}
+
+// =============================================================================
+// Validation Tests
+// =============================================================================
+
+func TestValidateKeys(t *testing.T) {
+	publicKey, privateKey, err := paillier.GenerateKeys(64)
+	require.NoError(t, err)
+
+	t.Run("successfully validate generated keys", func(t *testing.T) {
+		require.NoError(t, paillier.ValidatePublicKey(publicKey))
+		require.NoError(t, paillier.ValidatePrivateKey(publicKey, privateKey))
+	})
+
+	t.Run("fail to validate public key with incorrect NSquare", func(t *testing.T) {
+		invalidKey := *publicKey
+		invalidKey.NSquare = new(big.Int).Add(publicKey.NSquare, big.NewInt(1))
+
+		require.EqualError(t, paillier.ValidatePublicKey(&invalidKey), "public key NSquare must equal N * N")
+	})
+
+	t.Run("fail to validate mismatched key pair", func(t *testing.T) {
+		_, otherPrivateKey, err := paillier.GenerateKeys(64)
+		require.NoError(t, err)
+
+		require.Error(t, paillier.ValidatePrivateKey(publicKey, otherPrivateKey))
+	})
+
+	t.Run("fail to validate ciphertext outside Z*_{n^2}", func(t *testing.T) {
+		require.Error(t, paillier.ValidateCiphertext(publicKey, publicKey.NSquare))
+		require.Error(t, paillier.ValidateCiphertext(publicKey, publicKey.N))
+	})
+}
+
+// =============================================================================
+// Homomorphic Operation Tests
+// =============================================================================
+
+func TestHomomorphicOperations(t *testing.T) {
+	publicKey, privateKey, err := paillier.GenerateKeys(64)
+	require.NoError(t, err)
+
+	lhs, err := paillier.Encrypt(publicKey, big.NewInt(10))
+	require.NoError(t, err)
+	rhs, err := paillier.Encrypt(publicKey, big.NewInt(3))
+	require.NoError(t, err)
+
+	decrypt := func(encrypted *big.Int) *big.Int {
+		result, err := paillier.Decrypt(publicKey, privateKey, encrypted)
+		require.NoError(t, err)
+
+		return result
+	}
+
+	t.Run("successfully multiply by plain scalar", func(t *testing.T) {
+		require.Equal(t, int64(50), decrypt(paillier.MulPlain(publicKey, lhs, big.NewInt(5))).Int64())
+	})
+
+	t.Run("successfully subtract and negate", func(t *testing.T) {
+		difference, err := paillier.Sub(publicKey, lhs, rhs)
+		require.NoError(t, err)
+		require.Equal(t, int64(7), decrypt(difference).Int64())
+
+		negated, err := paillier.Negate(publicKey, rhs)
+		require.NoError(t, err)
+
+		expected := new(big.Int).Sub(publicKey.N, big.NewInt(3))
+		require.Equal(t, 0, expected.Cmp(decrypt(negated)))
+	})
+
+	t.Run("successfully rerandomize", func(t *testing.T) {
+		rerandomized, err := paillier.Rerandomize(publicKey, lhs)
+		require.NoError(t, err)
+
+		require.NotEqual(t, 0, lhs.Cmp(rerandomized))
+		require.Equal(t, int64(10), decrypt(rerandomized).Int64())
+	})
+}
+
+// =============================================================================
+// Ciphertext Tests
+// =============================================================================
+
+func TestCiphertext(t *testing.T) {
+	publicKey, privateKey, err := paillier.GenerateKeys(64)
+	require.NoError(t, err)
+	otherPublicKey, _, err := paillier.GenerateKeys(64)
+	require.NoError(t, err)
+
+	lhs, err := paillier.EncryptCiphertext(publicKey, big.NewInt(2))
+	require.NoError(t, err)
+	rhs, err := paillier.EncryptCiphertext(publicKey, big.NewInt(3))
+	require.NoError(t, err)
+
+	t.Run("successfully add ciphertexts with the same key", func(t *testing.T) {
+		sum, err := paillier.AddCiphertexts(publicKey, lhs, rhs)
+		require.NoError(t, err)
+
+		result, err := paillier.DecryptCiphertext(publicKey, privateKey, sum)
+		require.NoError(t, err)
+		require.Equal(t, int64(5), result.Int64())
+	})
+
+	t.Run("fail to add ciphertexts with different keys", func(t *testing.T) {
+		other, err := paillier.EncryptCiphertext(otherPublicKey, big.NewInt(3))
+		require.NoError(t, err)
+
+		_, err = paillier.AddCiphertexts(publicKey, lhs, other)
+		require.EqualError(t, err, (&paillier.KeyMismatchError{publicKey.Fingerprint(), otherPublicKey.Fingerprint()}).Error())
+	})
+
+	t.Run("successfully marshal and unmarshal JSON", func(t *testing.T) {
+		data, err := json.Marshal(lhs)
+		require.NoError(t, err)
+
+		var result paillier.Ciphertext
+		require.NoError(t, json.Unmarshal(data, &result))
+		require.Equal(t, lhs.KeyID, result.KeyID)
+		require.Equal(t, 0, lhs.Value.Cmp(result.Value))
+	})
+}
+
+// =============================================================================
+// Keystore Tests
+// =============================================================================
+
+func TestKeystore(t *testing.T) {
+	publicKey, privateKey, err := paillier.GenerateKeys(64)
+	require.NoError(t, err)
+
+	keystore, err := paillier.LockPrivateKey(publicKey, privateKey, []byte("correct horse battery staple"))
+	require.NoError(t, err)
+
+	t.Run("successfully save, load and unlock keystore", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "keystore.json")
+		require.NoError(t, paillier.SaveKeystore(path, keystore))
+
+		loadedKeystore, err := paillier.LoadKeystore(path)
+		require.NoError(t, err)
+		require.True(t, keystore.IsEqual(*loadedKeystore))
+
+		result, err := paillier.UnlockPrivateKey(loadedKeystore, []byte("correct horse battery staple"))
+		require.NoError(t, err)
+		require.True(t, privateKey.IsEqual(*result))
+	})
+
+	t.Run("fail to unlock keystore with incorrect passphrase", func(t *testing.T) {
+		_, err := paillier.UnlockPrivateKey(keystore, []byte("incorrect"))
+		require.EqualError(t, err, "unable to unlock keystore! passphrase is incorrect or keystore is corrupted")
+	})
+
+	t.Run("fail to unlock keystore with excessive scrypt parameters", func(t *testing.T) {
+		modifiedKeystore := *keystore
+		modifiedKeystore.KDFParams.N = 1 << 30
+
+		_, err := paillier.UnlockPrivateKey(&modifiedKeystore, []byte("correct horse battery staple"))
+		require.EqualError(t, err, "keystore scrypt parameters must not exceed N=32768, R=8 & P=1")
+	})
+
+	t.Run("fail to unlock keystore with modified header", func(t *testing.T) {
+		modifiedKeystore := *keystore
+		modifiedKeystore.PublicKeyFingerprint = "modified"
+
+		_, err := paillier.UnlockPrivateKey(&modifiedKeystore, []byte("correct horse battery staple"))
+		require.Error(t, err)
+	})
+}
+
+// =============================================================================
+// Packing Tests
+// =============================================================================
+
+func TestPacking(t *testing.T) {
+	publicKey, privateKey, err := paillier.GenerateKeys(64)
+	require.NoError(t, err)
+
+	packing, err := paillier.NewPacking(publicKey, 100, 4)
+	require.NoError(t, err)
+
+	t.Run("successfully encode and decode counts", func(t *testing.T) {
+		counts := []*big.Int{big.NewInt(3), big.NewInt(0), big.NewInt(100), big.NewInt(42)}
+
+		packed, err := packing.Encode(counts)
+		require.NoError(t, err)
+
+		decoded, err := packing.Decode(packed)
+		require.NoError(t, err)
+		require.Equal(t, counts, decoded)
+	})
+
+	t.Run("successfully tally encrypted packed votes", func(t *testing.T) {
+		votes := []int{0, 2, 2, 3, 2}
+
+		tally, err := paillier.Encrypt(publicKey, big.NewInt(0))
+		require.NoError(t, err)
+
+		for _, index := range votes {
+			slot, err := packing.Slot(index)
+			require.NoError(t, err)
+
+			vote, randomness, err := paillier.EncryptWithRandomness(publicKey, slot)
+			require.NoError(t, err)
+
+			proof, err := paillier.ProveMembership(publicKey, vote, slot, randomness, packing.Slots())
+			require.NoError(t, err)
+			require.True(t, paillier.VerifyMembership(publicKey, vote, packing.Slots(), proof))
+
+			tally = paillier.AddEncrypted(publicKey, tally, vote)
+		}
+
+		packed, err := paillier.Decrypt(publicKey, privateKey, tally)
+		require.NoError(t, err)
+
+		counts, err := packing.Decode(packed)
+		require.NoError(t, err)
+		require.Equal(t, []*big.Int{big.NewInt(1), big.NewInt(0), big.NewInt(3), big.NewInt(1)}, counts)
+	})
+
+	t.Run("fail to prove membership of a value that is not allowed", func(t *testing.T) {
+		vote, randomness, err := paillier.EncryptWithRandomness(publicKey, big.NewInt(2))
+		require.NoError(t, err)
+
+		_, err = paillier.ProveMembership(publicKey, vote, big.NewInt(2), randomness, packing.Slots())
+		require.Error(t, err)
+
+		// A proof for the allowed values {0, 2} does not verify for the slots {1, 101, ...}
+		proof, err := paillier.ProveMembership(publicKey, vote, big.NewInt(2), randomness, []*big.Int{big.NewInt(0), big.NewInt(2)})
+		require.NoError(t, err)
+		require.False(t, paillier.VerifyMembership(publicKey, vote, packing.Slots()[:2], proof))
+	})
+
+	t.Run("fail to verify forged proof with a branch challenge outside of the challenge range", func(t *testing.T) {
+		// 1000 votes for the first candidate
+		vote, err := paillier.Encrypt(publicKey, big.NewInt(1000))
+		require.NoError(t, err)
+
+		// u_k = c * g^-m_k
+		u := []*big.Int{}
+		for _, slot := range packing.Slots() {
+			negated := new(big.Int).Sub(publicKey.N, slot)
+			u = append(u, paillier.AddEncryptedWithPlain(publicKey, vote, negated.Mod(negated, publicKey.N)))
+		}
+
+		a, e, z := forgeORProof(t, publicKey, u, func(commitments []*big.Int) []*big.Int {
+			transcript := append([]*big.Int{publicKey.N, vote}, packing.Slots()...)
+			return append(transcript, commitments...)
+		})
+
+		proof := &paillier.MembershipProof{A: a, E: e, Z: z}
+		require.False(t, paillier.VerifyMembership(publicKey, vote, packing.Slots(), proof))
+	})
+
+	t.Run("fail to create packing that does not fit into the public key", func(t *testing.T) {
+		_, err := paillier.NewPacking(publicKey, 100, 64)
+		require.Error(t, err)
+	})
+
+	t.Run("fail to encode count that overflows its slot", func(t *testing.T) {
+		_, err := packing.Encode([]*big.Int{big.NewInt(101), big.NewInt(0), big.NewInt(0), big.NewInt(0)})
+		require.Error(t, err)
+	})
+}
+
+// =============================================================================
+// DRBG Tests
+// =============================================================================
+
+func TestDRBG(t *testing.T) {
+	t.Run("successfully generate NIST HMAC_DRBG test vector", func(t *testing.T) {
+		// SHA-256 test vector from NIST CAVP without prediction resistance, personalisation or additional input.
+		// The seed is EntropyInput || Nonce and the second 1024 bits generated are returned.
+		seed, _ := hex.DecodeString("ca851911349384bffe89de1cbdc46e6831e44d34a4fb935ee285dd14b71a7488" + "659ba96c601dc69fc902940805ec0ca8")
+		expected := "e528e9abf2dece54d47c7e75e5fe302149f817ea9fb4bee6f4199697d04d5b89" +
+			"d54fbb978a15b5c443c9ec21036d2460b6f73ebad0dc2aba6e624abf07745bc1" +
+			"07694bb7547bb0995f70de25d6b29e2d3011bb19d27676c07162c8b5ccde0668" +
+			"961df86803482cb37ed6d5c0bb8d50cf1f50d476aa0458bdaba806f48be9dcb8"
+
+		drbg, err := paillier.NewDRBG(seed)
+		require.NoError(t, err)
+
+		output := make([]byte, 128)
+		_, err = drbg.Read(output)
+		require.NoError(t, err)
+		_, err = drbg.Read(output)
+		require.NoError(t, err)
+
+		require.Equal(t, expected, hex.EncodeToString(output))
+	})
+
+	t.Run("successfully generate identical keys & ciphertexts from the same seed", func(t *testing.T) {
+		generate := func() (*paillier.PublicKey, *big.Int, *paillier.BinaryProof) {
+			drbg, err := paillier.NewDRBG([]byte("fixed seed"))
+			require.NoError(t, err)
+
+			publicKey, _, err := paillier.GenerateKeysWithReader(drbg, 64)
+			require.NoError(t, err)
+
+			encrypted, randomness, err := paillier.EncryptWithReader(drbg, publicKey, big.NewInt(1))
+			require.NoError(t, err)
+
+			proof, err := paillier.ProveBinaryWithReader(drbg, publicKey, encrypted, big.NewInt(1), randomness)
+			require.NoError(t, err)
+
+			return publicKey, encrypted, proof
+		}
+
+		publicKey, encrypted, proof := generate()
+		otherPublicKey, otherEncrypted, otherProof := generate()
+
+		require.True(t, publicKey.IsEqual(*otherPublicKey))
+		require.Equal(t, 0, encrypted.Cmp(otherEncrypted))
+		require.True(t, proof.IsEqual(*otherProof))
+		require.True(t, paillier.VerifyBinary(publicKey, encrypted, proof))
+	})
+
+	t.Run("successfully sample randomness from all of Z*_n", func(t *testing.T) {
+		publicKey, privateKey, err := paillier.GenerateKeys(64)
+		require.NoError(t, err)
+
+		// Randomness used to be sampled below Length, i.e. from a tiny range that could be brute forced
+		_, randomness, err := paillier.EncryptWithRandomness(publicKey, big.NewInt(1))
+		require.NoError(t, err)
+		require.Greater(t, randomness.BitLen(), int(privateKey.Length))
+	})
+
+	t.Run("fail to create DRBG without a seed", func(t *testing.T) {
+		_, err := paillier.NewDRBG(nil)
+		require.Error(t, err)
+	})
+}
+
+// =============================================================================
+// Accumulator Tests
+// =============================================================================
+
+func TestAccumulator(t *testing.T) {
+	publicKey, privateKey, err := paillier.GenerateKeys(64)
+	require.NoError(t, err)
+
+	t.Run("successfully add ciphertexts from multiple goroutines", func(t *testing.T) {
+		accumulator, err := paillier.NewAccumulator(publicKey, 4)
+		require.NoError(t, err)
+
+		// 3 voters vote for "a" & 1 voter votes for "b" on each goroutine
+		entries := make(chan paillier.AccumulatorEntry)
+		errs := make(chan error, 10)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				for _, key := range []string{"a", "a", "b", "a"} {
+					vote, err := paillier.EncryptCiphertext(publicKey, big.NewInt(1))
+					if err != nil {
+						errs <- err
+						return
+					}
+
+					entries <- paillier.AccumulatorEntry{Key: key, Ciphertext: vote}
+				}
+			}()
+		}
+
+		go func() {
+			wg.Wait()
+			close(entries)
+			close(errs)
+		}()
+
+		require.NoError(t, accumulator.Feed(entries))
+		for err := range errs {
+			require.NoError(t, err)
+		}
+
+		totals := accumulator.Result()
+		require.Len(t, totals, 2)
+
+		a, err := paillier.DecryptCiphertext(publicKey, privateKey, totals["a"])
+		require.NoError(t, err)
+		require.Equal(t, int64(30), a.Int64())
+
+		b, err := paillier.DecryptCiphertext(publicKey, privateKey, totals["b"])
+		require.NoError(t, err)
+		require.Equal(t, int64(10), b.Int64())
+	})
+
+	t.Run("successfully compute the same total with any number of workers", func(t *testing.T) {
+		votes := []*paillier.Ciphertext{}
+		for i := 0; i < 20; i++ {
+			vote, err := paillier.EncryptCiphertext(publicKey, big.NewInt(int64(i%2)))
+			require.NoError(t, err)
+
+			votes = append(votes, vote)
+		}
+
+		totals := []*big.Int{}
+		for _, numWorkers := range []int{1, 3, 8} {
+			accumulator, err := paillier.NewAccumulator(publicKey, numWorkers)
+			require.NoError(t, err)
+
+			for _, vote := range votes {
+				require.NoError(t, accumulator.Add("a", vote))
+			}
+
+			totals = append(totals, accumulator.Result()["a"].Value)
+		}
+
+		require.Equal(t, 0, totals[0].Cmp(totals[1]))
+		require.Equal(t, 0, totals[0].Cmp(totals[2]))
+	})
+
+	t.Run("fail to add ciphertext encrypted with another key", func(t *testing.T) {
+		otherPublicKey, _, err := paillier.GenerateKeys(64)
+		require.NoError(t, err)
+
+		accumulator, err := paillier.NewAccumulator(publicKey, 2)
+		require.NoError(t, err)
+		defer accumulator.Result()
+
+		vote, err := paillier.EncryptCiphertext(otherPublicKey, big.NewInt(1))
+		require.NoError(t, err)
+
+		var mismatch *paillier.KeyMismatchError
+		require.ErrorAs(t, accumulator.Add("a", vote), &mismatch)
+	})
+
+	t.Run("fail to add ciphertext after result", func(t *testing.T) {
+		accumulator, err := paillier.NewAccumulator(publicKey, 2)
+		require.NoError(t, err)
+		accumulator.Result()
+
+		vote, err := paillier.EncryptCiphertext(publicKey, big.NewInt(1))
+		require.NoError(t, err)
+
+		require.EqualError(t, accumulator.Add("a", vote), "accumulator is closed")
+	})
+}
+
+// =============================================================================
+// Damgård–Jurik Tests
+// =============================================================================
+
+func TestDamgardJurik(t *testing.T) {
+	publicKey, privateKey, err := paillier.GenerateKeys(64)
+	require.NoError(t, err)
+
+	t.Run("successfully encrypt and decrypt values larger than N", func(t *testing.T) {
+		for _, s := range []int{1, 2, 3, 5} {
+			// n^s - 1 is the largest value that can be encrypted
+			value := new(big.Int).Sub(paillier.PlaintextSpaceDJ(publicKey, s), big.NewInt(1))
+
+			encrypted, err := paillier.EncryptDJ(publicKey, s, value)
+			require.NoError(t, err)
+
+			decrypted, err := paillier.DecryptDJ(publicKey, privateKey, s, encrypted)
+			require.NoError(t, err)
+			require.Equal(t, 0, value.Cmp(decrypted), "s = %d", s)
+		}
+	})
+
+	t.Run("successfully decrypt Paillier ciphertexts with s = 1", func(t *testing.T) {
+		encrypted, err := paillier.Encrypt(publicKey, big.NewInt(42))
+		require.NoError(t, err)
+
+		decrypted, err := paillier.DecryptDJ(publicKey, privateKey, 1, encrypted)
+		require.NoError(t, err)
+		require.Equal(t, int64(42), decrypted.Int64())
+	})
+
+	t.Run("successfully tally packed votes that exceed N", func(t *testing.T) {
+		// 5 slots of base 2^32 need 160 bits, which do not fit into a 128 bit N but fit into N^2
+		packing := paillier.Packing{Base: new(big.Int).Lsh(big.NewInt(1), 32), NumSlots: 5}
+		require.Error(t, packing.Validate(publicKey))
+		require.NoError(t, packing.ValidateDJ(publicKey, 2))
+
+		tally, err := paillier.EncryptDJ(publicKey, 2, big.NewInt(0))
+		require.NoError(t, err)
+
+		for _, index := range []int{4, 4, 0, 2} {
+			slot, err := packing.Slot(index)
+			require.NoError(t, err)
+
+			vote, err := paillier.EncryptDJ(publicKey, 2, slot)
+			require.NoError(t, err)
+
+			tally = paillier.AddEncryptedDJ(publicKey, 2, tally, vote)
+		}
+
+		packed, err := paillier.DecryptDJ(publicKey, privateKey, 2, tally)
+		require.NoError(t, err)
+
+		counts, err := packing.Decode(packed)
+		require.NoError(t, err)
+		require.Equal(t, []int64{1, 0, 1, 0, 2}, []int64{counts[0].Int64(), counts[1].Int64(), counts[2].Int64(), counts[3].Int64(), counts[4].Int64()})
+	})
+
+	t.Run("successfully multiply and add plain values", func(t *testing.T) {
+		encrypted, err := paillier.EncryptDJ(publicKey, 3, big.NewInt(7))
+		require.NoError(t, err)
+
+		result := paillier.MulPlainDJ(publicKey, 3, encrypted, big.NewInt(6))
+		result = paillier.AddEncryptedWithPlainDJ(publicKey, 3, result, big.NewInt(8))
+
+		decrypted, err := paillier.DecryptDJ(publicKey, privateKey, 3, result)
+		require.NoError(t, err)
+		require.Equal(t, int64(50), decrypted.Int64())
+	})
+
+	t.Run("fail to encrypt value larger than n^s", func(t *testing.T) {
+		_, err := paillier.EncryptDJ(publicKey, 2, paillier.PlaintextSpaceDJ(publicKey, 2))
+		require.EqualError(t, err, "value is too large to encrypt")
+
+		_, err = paillier.EncryptDJ(publicKey, 0, big.NewInt(1))
+		require.EqualError(t, err, "s must be at least 1")
+	})
+}
+
+// =============================================================================
+// Helpers
+// =============================================================================
+
+// Forges an OR proof for the statements u_k without an n-th root of any of them, by answering the first branch
+// with a challenge that is a multiple of n, which makes u_0^e_0 an n-th power. The challenges of the branches
+// sum to the Fiat-Shamir challenge modulo 2^t, but the first is far above 2^t.
+// transcript returns the values hashed into the challenge for the given commitments.
+func forgeORProof(t *testing.T, publicKey *paillier.PublicKey, u []*big.Int, transcript func(commitments []*big.Int) []*big.Int) ([]*big.Int, []*big.Int, []*big.Int) {
+	modulus := new(big.Int).Lsh(big.NewInt(1), uint(min(256, publicKey.N.BitLen()/2-1)))
+
+	commitments := make([]*big.Int, len(u))
+	challenges := make([]*big.Int, len(u))
+	responses := make([]*big.Int, len(u))
+
+	// Simulate every other branch as an honest prover simulates its fake branches
+	fakeChallenges := new(big.Int)
+	for k := 1; k < len(u); k++ {
+		challenges[k] = big.NewInt(int64(k))
+		responses[k] = big.NewInt(int64(k + 1))
+
+		uInverse := new(big.Int).ModInverse(new(big.Int).Exp(u[k], challenges[k], publicKey.NSquare), publicKey.NSquare)
+		require.NotNil(t, uInverse)
+
+		commitments[k] = new(big.Int).Exp(responses[k], publicKey.N, publicKey.NSquare)
+		commitments[k].Mul(commitments[k], uInverse).Mod(commitments[k], publicKey.NSquare)
+
+		fakeChallenges.Add(fakeChallenges, challenges[k])
+	}
+
+	rho := big.NewInt(2)
+	commitments[0] = new(big.Int).Exp(rho, publicKey.N, publicKey.NSquare)
+
+	challenge := new(big.Int).SetBytes(hashValues(transcript(commitments)...))
+	challenge.Mod(challenge, modulus)
+
+	// e_0 = n * k with n * k = e - sum(e_fake) % 2^t, so z_0 = rho * u_0^k % n
+	k := new(big.Int).Sub(challenge, fakeChallenges)
+	k.Mul(k, new(big.Int).ModInverse(publicKey.N, modulus)).Mod(k, modulus)
+
+	challenges[0] = new(big.Int).Mul(publicKey.N, k)
+	responses[0] = new(big.Int).Exp(u[0], k, publicKey.N)
+	responses[0].Mul(responses[0], rho).Mod(responses[0], publicKey.N)
+
+	return commitments, challenges, responses
+}
+
+// Computes the SHA-256 hash of the length-prefixed values, as hashed into the Fiat-Shamir challenge of proofs
+func hashValues(values ...*big.Int) []byte {
+	hash := sha256.New()
+
+	for _, v := range values {
+		var length [8]byte
+		binary.BigEndian.PutUint64(length[:], uint64(len(v.Bytes())))
+
+		hash.Write(length[:])
+		hash.Write(v.Bytes())
+	}
+
+	return hash.Sum(nil)
+}
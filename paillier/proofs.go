@@ -0,0 +1,762 @@
+// Non-interactive zero-knowledge proofs for Paillier ciphertexts.
+// The proofs are sigma protocols proving knowledge of an n-th root modulo n^2,
+// or of both the plaintext & n-th root for knowledge proofs,
+// made non-interactive with the Fiat-Shamir heuristic (SHA-256).
+// A ciphertext c encrypts m iff c * g^-m is an n-th power, i.e. c * g^-m = r^n % n^2.
+
+package paillier
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math/big"
+)
+
+// Maximum bit length of the Fiat-Shamir challenge, which is the length of the hash
+const challengeLength = 256
+
+// =============================================================================
+// Binary Proofs
+// =============================================================================
+
+// Generates a disjunctive (OR) proof that ciphertext encrypts either 0 or 1.
+// value & randomness must be the plaintext & random number r used to encrypt ciphertext.
+// The proof does not reveal which of the two values is encrypted.
+func ProveBinary(publicKey *PublicKey, ciphertext, value, randomness *big.Int) (*BinaryProof, error) {
+	return ProveBinaryWithReader(rand.Reader, publicKey, ciphertext, value, randomness)
+}
+
+// Generates a proof that ciphertext encrypts either 0 or 1, with the random values of the proof drawn from reader
+func ProveBinaryWithReader(reader io.Reader, publicKey *PublicKey, ciphertext, value, randomness *big.Int) (*BinaryProof, error) {
+	if value.Sign() != 0 && value.Cmp(big.NewInt(1)) != 0 {
+		return nil, errors.New("value must be either 0 or 1")
+	}
+
+	// u_k = c * g^-k for k in {0, 1}
+	// The real branch is the one where u_k = r^n
+	u, err := binaryStatements(publicKey, ciphertext)
+	if err != nil {
+		return nil, err
+	}
+
+	actual := int(value.Int64())
+	fake := 1 - actual
+
+	// Simulate the fake branch by choosing its challenge & response first
+	// a_fake = z_fake^n * u_fake^-e_fake % n^2
+	fakeChallenge, err := rand.Int(reader, challengeModulus(publicKey))
+	if err != nil {
+		return nil, err
+	}
+
+	fakeResponse, err := randomUnit(reader, publicKey.N)
+	if err != nil {
+		return nil, err
+	}
+
+	uInverse := new(big.Int).ModInverse(new(big.Int).Exp(u[fake], fakeChallenge, publicKey.NSquare), publicKey.NSquare)
+	if uInverse == nil {
+		return nil, errors.New("ciphertext is not invertible")
+	}
+
+	fakeCommitment := new(big.Int).Exp(fakeResponse, publicKey.N, publicKey.NSquare)
+	fakeCommitment.Mul(fakeCommitment, uInverse).Mod(fakeCommitment, publicKey.NSquare)
+
+	// Commit to the real branch
+	// a_real = rho^n % n^2
+	rho, err := randomUnit(reader, publicKey.N)
+	if err != nil {
+		return nil, err
+	}
+
+	realCommitment := new(big.Int).Exp(rho, publicKey.N, publicKey.NSquare)
+
+	commitments := [2]*big.Int{}
+	commitments[actual] = realCommitment
+	commitments[fake] = fakeCommitment
+
+	// e_real = e - e_fake % 2^t
+	// z_real = rho * r^e_real % n
+	challenge := proofChallenge(publicKey, publicKey.N, ciphertext, commitments[0], commitments[1])
+
+	realChallenge := new(big.Int).Sub(challenge, fakeChallenge)
+	realChallenge.Mod(realChallenge, challengeModulus(publicKey))
+
+	realResponse := new(big.Int).Exp(randomness, realChallenge, publicKey.N)
+	realResponse.Mul(realResponse, rho).Mod(realResponse, publicKey.N)
+
+	challenges := [2]*big.Int{}
+	challenges[actual] = realChallenge
+	challenges[fake] = fakeChallenge
+
+	responses := [2]*big.Int{}
+	responses[actual] = realResponse
+	responses[fake] = fakeResponse
+
+	return &BinaryProof{
+		A0: commitments[0], A1: commitments[1],
+		E0: challenges[0], E1: challenges[1],
+		Z0: responses[0], Z1: responses[1],
+	}, nil
+}
+
+// Verifies that proof shows ciphertext encrypts either 0 or 1
+func VerifyBinary(publicKey *PublicKey, ciphertext *big.Int, proof *BinaryProof) bool {
+	if proof == nil || proof.A0 == nil || proof.A1 == nil || proof.E0 == nil || proof.E1 == nil || proof.Z0 == nil || proof.Z1 == nil {
+		return false
+	}
+
+	u, err := binaryStatements(publicKey, ciphertext)
+	if err != nil {
+		return false
+	}
+
+	if !inChallengeRange(publicKey, proof.E0) || !inChallengeRange(publicKey, proof.E1) {
+		return false
+	}
+
+	// e_0 + e_1 = e or e + 2^t, as the prover reduces e_real % 2^t & both challenges are in [0, 2^t)
+	challenge := proofChallenge(publicKey, publicKey.N, ciphertext, proof.A0, proof.A1)
+	wrapped := new(big.Int).Add(challenge, challengeModulus(publicKey))
+
+	sum := new(big.Int).Add(proof.E0, proof.E1)
+	if sum.Cmp(challenge) != 0 && sum.Cmp(wrapped) != 0 {
+		return false
+	}
+
+	// z_k^n = a_k * u_k^e_k % n^2 for k in {0, 1}
+	return verifyNthRoot(publicKey, u[0], proof.A0, proof.E0, proof.Z0) &&
+		verifyNthRoot(publicKey, u[1], proof.A1, proof.E1, proof.Z1)
+}
+
+// =============================================================================
+// Sum Proofs
+// =============================================================================
+
+// Generates a proof that the plaintexts of ciphertexts sum to 1.
+// randomness must contain the random number r used to encrypt each ciphertext, in the same order.
+func ProveSumEqualsOne(publicKey *PublicKey, ciphertexts, randomness []*big.Int) (*SumProof, error) {
+	return ProveSum(publicKey, ciphertexts, randomness, big.NewInt(1))
+}
+
+// Verifies that proof shows the plaintexts of ciphertexts sum to 1
+func VerifySumEqualsOne(publicKey *PublicKey, ciphertexts []*big.Int, proof *SumProof) bool {
+	return VerifySum(publicKey, ciphertexts, big.NewInt(1), proof)
+}
+
+// Generates a proof that the plaintexts of ciphertexts sum to sum.
+// randomness must contain the random number r used to encrypt each ciphertext, in the same order.
+func ProveSum(publicKey *PublicKey, ciphertexts, randomness []*big.Int, sum *big.Int) (*SumProof, error) {
+	return ProveSumWithReader(rand.Reader, publicKey, ciphertexts, randomness, sum)
+}
+
+// Generates a proof that the plaintexts of ciphertexts sum to sum, with the random values of the proof drawn from reader
+func ProveSumWithReader(reader io.Reader, publicKey *PublicKey, ciphertexts, randomness []*big.Int, sum *big.Int) (*SumProof, error) {
+	if len(ciphertexts) == 0 || len(ciphertexts) != len(randomness) {
+		return nil, errors.New("each ciphertext must have a matching randomness")
+	}
+
+	// The product of the ciphertexts encrypts the sum with randomness R = prod(r_i) % n
+	r := new(big.Int).SetInt64(1)
+	for i := range randomness {
+		r.Mul(r, randomness[i]).Mod(r, publicKey.N)
+	}
+
+	// Ensure the ciphertexts are valid before generating the proof
+	if _, err := sumStatement(publicKey, ciphertexts, sum); err != nil {
+		return nil, err
+	}
+
+	// a = rho^n % n^2
+	// z = rho * R^e % n
+	rho, err := randomUnit(reader, publicKey.N)
+	if err != nil {
+		return nil, err
+	}
+
+	commitment := new(big.Int).Exp(rho, publicKey.N, publicKey.NSquare)
+	challenge := proofChallenge(publicKey, append([]*big.Int{publicKey.N, sum, commitment}, ciphertexts...)...)
+
+	response := new(big.Int).Exp(r, challenge, publicKey.N)
+	response.Mul(response, rho).Mod(response, publicKey.N)
+
+	return &SumProof{A: commitment, Z: response}, nil
+}
+
+// Verifies that proof shows the plaintexts of ciphertexts sum to sum
+func VerifySum(publicKey *PublicKey, ciphertexts []*big.Int, sum *big.Int, proof *SumProof) bool {
+	if proof == nil || proof.A == nil || proof.Z == nil || len(ciphertexts) == 0 {
+		return false
+	}
+
+	u, err := sumStatement(publicKey, ciphertexts, sum)
+	if err != nil {
+		return false
+	}
+
+	challenge := proofChallenge(publicKey, append([]*big.Int{publicKey.N, sum, proof.A}, ciphertexts...)...)
+
+	return verifyNthRoot(publicKey, u, proof.A, challenge, proof.Z)
+}
+
+// =============================================================================
+// Membership Proofs
+// =============================================================================
+
+// Generates a disjunctive (OR) proof that ciphertext encrypts one of the values in allowed.
+// value & randomness must be the plaintext & random number r used to encrypt ciphertext.
+// The proof does not reveal which of the allowed values is encrypted.
+func ProveMembership(publicKey *PublicKey, ciphertext, value, randomness *big.Int, allowed []*big.Int) (*MembershipProof, error) {
+	return ProveMembershipWithReader(rand.Reader, publicKey, ciphertext, value, randomness, allowed)
+}
+
+// Generates a proof that ciphertext encrypts one of the values in allowed, with the random values of the proof drawn from reader
+func ProveMembershipWithReader(reader io.Reader, publicKey *PublicKey, ciphertext, value, randomness *big.Int, allowed []*big.Int) (*MembershipProof, error) {
+	actual := -1
+	for k := range allowed {
+		if allowed[k].Cmp(value) == 0 {
+			actual = k
+			break
+		}
+	}
+
+	if actual == -1 {
+		return nil, errors.New("value must be one of the allowed values")
+	}
+
+	// u_k = c * g^-m_k for each allowed m_k
+	// The real branch is the one where u_k = r^n
+	u, err := membershipStatements(publicKey, ciphertext, allowed)
+	if err != nil {
+		return nil, err
+	}
+
+	commitments := make([]*big.Int, len(allowed))
+	challenges := make([]*big.Int, len(allowed))
+	responses := make([]*big.Int, len(allowed))
+
+	// Simulate every fake branch by choosing its challenge & response first
+	// a_k = z_k^n * u_k^-e_k % n^2
+	fakeChallenges := new(big.Int)
+	for k := range allowed {
+		if k == actual {
+			continue
+		}
+
+		if challenges[k], err = rand.Int(reader, challengeModulus(publicKey)); err != nil {
+			return nil, err
+		}
+
+		if responses[k], err = randomUnit(reader, publicKey.N); err != nil {
+			return nil, err
+		}
+
+		uInverse := new(big.Int).ModInverse(new(big.Int).Exp(u[k], challenges[k], publicKey.NSquare), publicKey.NSquare)
+		if uInverse == nil {
+			return nil, errors.New("ciphertext is not invertible")
+		}
+
+		commitments[k] = new(big.Int).Exp(responses[k], publicKey.N, publicKey.NSquare)
+		commitments[k].Mul(commitments[k], uInverse).Mod(commitments[k], publicKey.NSquare)
+
+		fakeChallenges.Add(fakeChallenges, challenges[k])
+	}
+
+	// Commit to the real branch
+	// a_real = rho^n % n^2
+	rho, err := randomUnit(reader, publicKey.N)
+	if err != nil {
+		return nil, err
+	}
+
+	commitments[actual] = new(big.Int).Exp(rho, publicKey.N, publicKey.NSquare)
+
+	// e_real = e - sum(e_fake) % 2^t
+	// z_real = rho * r^e_real % n
+	challenge := proofChallenge(publicKey, membershipTranscript(publicKey, ciphertext, allowed, commitments)...)
+
+	challenges[actual] = new(big.Int).Sub(challenge, fakeChallenges)
+	challenges[actual].Mod(challenges[actual], challengeModulus(publicKey))
+
+	responses[actual] = new(big.Int).Exp(randomness, challenges[actual], publicKey.N)
+	responses[actual].Mul(responses[actual], rho).Mod(responses[actual], publicKey.N)
+
+	return &MembershipProof{A: commitments, E: challenges, Z: responses}, nil
+}
+
+// Verifies that proof shows ciphertext encrypts one of the values in allowed
+func VerifyMembership(publicKey *PublicKey, ciphertext *big.Int, allowed []*big.Int, proof *MembershipProof) bool {
+	if proof == nil || len(allowed) == 0 {
+		return false
+	}
+
+	if len(proof.A) != len(allowed) || len(proof.E) != len(allowed) || len(proof.Z) != len(allowed) {
+		return false
+	}
+
+	for k := range allowed {
+		if proof.A[k] == nil || proof.E[k] == nil || proof.Z[k] == nil {
+			return false
+		}
+
+		if !inChallengeRange(publicKey, proof.E[k]) {
+			return false
+		}
+	}
+
+	u, err := membershipStatements(publicKey, ciphertext, allowed)
+	if err != nil {
+		return false
+	}
+
+	// sum(e_k) = e % 2^t
+	challenge := proofChallenge(publicKey, membershipTranscript(publicKey, ciphertext, allowed, proof.A)...)
+
+	sum := new(big.Int)
+	for k := range proof.E {
+		sum.Add(sum, proof.E[k])
+	}
+
+	if sum.Mod(sum, challengeModulus(publicKey)).Cmp(challenge) != 0 {
+		return false
+	}
+
+	// z_k^n = a_k * u_k^e_k % n^2 for every branch
+	for k := range allowed {
+		if !verifyNthRoot(publicKey, u[k], proof.A[k], proof.E[k], proof.Z[k]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// =============================================================================
+// Knowledge Proofs
+// =============================================================================
+
+// Generates a proof of knowledge of the plaintext & randomness of ciphertext, bound to context.
+// value & randomness must be the plaintext & random number r used to encrypt ciphertext.
+// context should identify where the ciphertext is submitted, e.g. the ballot ID, so that a ciphertext
+// copied from another voter together with its proof is rejected under a different context.
+func ProvePlaintextKnowledge(publicKey *PublicKey, ciphertext, value, randomness *big.Int, context string) (*KnowledgeProof, error) {
+	return ProvePlaintextKnowledgeWithReader(rand.Reader, publicKey, ciphertext, value, randomness, context)
+}
+
+// Generates a proof of knowledge of the plaintext & randomness of ciphertext bound to context,
+// with the random values of the proof drawn from reader
+func ProvePlaintextKnowledgeWithReader(reader io.Reader, publicKey *PublicKey, ciphertext, value, randomness *big.Int, context string) (*KnowledgeProof, error) {
+	if value.Sign() < 0 || value.Cmp(publicKey.N) != -1 {
+		return nil, errors.New("value must satisfy 0 <= m < N")
+	}
+
+	if ciphertext.Sign() <= 0 || ciphertext.Cmp(publicKey.NSquare) != -1 {
+		return nil, errors.New("ciphertext is out of range")
+	}
+
+	// a = g^x * s^n % n^2
+	x, err := rand.Int(reader, publicKey.N)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := randomUnit(reader, publicKey.N)
+	if err != nil {
+		return nil, err
+	}
+
+	commitment := new(big.Int).Exp(publicKey.G, x, publicKey.NSquare)
+	commitment.Mul(commitment, new(big.Int).Exp(s, publicKey.N, publicKey.NSquare)).Mod(commitment, publicKey.NSquare)
+
+	challenge := proofChallenge(publicKey, knowledgeTranscript(publicKey, ciphertext, commitment, context)...)
+
+	// z_m = x + e * m % n
+	// z_r = s * r^e % n, as g^((x + e * m) / n) = 1 % n since g = n + 1
+	plaintextResponse := new(big.Int).Mul(challenge, value)
+	plaintextResponse.Add(plaintextResponse, x).Mod(plaintextResponse, publicKey.N)
+
+	randomnessResponse := new(big.Int).Exp(randomness, challenge, publicKey.N)
+	randomnessResponse.Mul(randomnessResponse, s).Mod(randomnessResponse, publicKey.N)
+
+	return &KnowledgeProof{A: commitment, ZM: plaintextResponse, ZR: randomnessResponse}, nil
+}
+
+// Verifies that proof shows knowledge of the plaintext & randomness of ciphertext under context
+func VerifyPlaintextKnowledge(publicKey *PublicKey, ciphertext *big.Int, context string, proof *KnowledgeProof) bool {
+	if proof == nil || proof.A == nil || proof.ZM == nil || proof.ZR == nil {
+		return false
+	}
+
+	if ciphertext.Sign() <= 0 || ciphertext.Cmp(publicKey.NSquare) != -1 {
+		return false
+	}
+
+	if proof.ZM.Sign() < 0 || proof.ZM.Cmp(publicKey.N) != -1 {
+		return false
+	}
+
+	if proof.ZR.Sign() <= 0 || proof.ZR.Cmp(publicKey.N) != -1 {
+		return false
+	}
+
+	challenge := proofChallenge(publicKey, knowledgeTranscript(publicKey, ciphertext, proof.A, context)...)
+
+	// g^z_m * z_r^n = a * c^e % n^2
+	lhs := new(big.Int).Exp(publicKey.G, proof.ZM, publicKey.NSquare)
+	lhs.Mul(lhs, new(big.Int).Exp(proof.ZR, publicKey.N, publicKey.NSquare)).Mod(lhs, publicKey.NSquare)
+
+	rhs := new(big.Int).Exp(ciphertext, challenge, publicKey.NSquare)
+	rhs.Mul(rhs, proof.A).Mod(rhs, publicKey.NSquare)
+
+	return lhs.Cmp(rhs) == 0
+}
+
+// =============================================================================
+// Decryption Proofs
+// =============================================================================
+
+// Decrypts a ciphertext and generates a proof that the plaintext is the correct decryption.
+// The proof can be verified with only the public key.
+func ProveDecryption(publicKey *PublicKey, privateKey *PrivateKey, ciphertext *big.Int) (*big.Int, *DecryptionProof, error) {
+	plaintext, err := Decrypt(publicKey, privateKey, ciphertext)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	u, err := sumStatement(publicKey, []*big.Int{ciphertext}, plaintext)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Recover the randomness of the ciphertext with the private key
+	// u = r^n % n^2, hence r = u^(n^-1 % lambda) % n
+	nInverse := new(big.Int).ModInverse(publicKey.N, privateKey.Lambda)
+	if nInverse == nil {
+		return nil, nil, errors.New("private key is invalid")
+	}
+
+	r := new(big.Int).Exp(new(big.Int).Mod(u, publicKey.N), nInverse, publicKey.N)
+
+	// a = rho^n % n^2
+	// z = rho * r^e % n
+	rho, err := randomUnit(rand.Reader, publicKey.N)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	commitment := new(big.Int).Exp(rho, publicKey.N, publicKey.NSquare)
+	challenge := proofChallenge(publicKey, publicKey.N, ciphertext, plaintext, commitment)
+
+	response := new(big.Int).Exp(r, challenge, publicKey.N)
+	response.Mul(response, rho).Mod(response, publicKey.N)
+
+	return plaintext, &DecryptionProof{A: commitment, Z: response}, nil
+}
+
+// Verifies that proof shows plaintext is the decryption of ciphertext
+func VerifyDecryption(publicKey *PublicKey, ciphertext, plaintext *big.Int, proof *DecryptionProof) bool {
+	if proof == nil || proof.A == nil || proof.Z == nil {
+		return false
+	}
+
+	if plaintext.Sign() < 0 || plaintext.Cmp(publicKey.N) != -1 {
+		return false
+	}
+
+	u, err := sumStatement(publicKey, []*big.Int{ciphertext}, plaintext)
+	if err != nil {
+		return false
+	}
+
+	challenge := proofChallenge(publicKey, publicKey.N, ciphertext, plaintext, proof.A)
+
+	return verifyNthRoot(publicKey, u, proof.A, challenge, proof.Z)
+}
+
+// =============================================================================
+// Helpers
+// =============================================================================
+
+// Computes u_k = c * g^-k % n^2 for k in {0, 1}
+func binaryStatements(publicKey *PublicKey, ciphertext *big.Int) ([2]*big.Int, error) {
+	if ciphertext.Sign() <= 0 || ciphertext.Cmp(publicKey.NSquare) != -1 {
+		return [2]*big.Int{}, errors.New("ciphertext is out of range")
+	}
+
+	gInverse := new(big.Int).ModInverse(publicKey.G, publicKey.NSquare)
+	if gInverse == nil {
+		return [2]*big.Int{}, errors.New("public key is invalid")
+	}
+
+	return [2]*big.Int{
+		new(big.Int).Set(ciphertext),
+		new(big.Int).Mod(new(big.Int).Mul(ciphertext, gInverse), publicKey.NSquare),
+	}, nil
+}
+
+// Computes u_k = c * g^-m_k % n^2 for each m_k in allowed
+func membershipStatements(publicKey *PublicKey, ciphertext *big.Int, allowed []*big.Int) ([]*big.Int, error) {
+	u := make([]*big.Int, len(allowed))
+
+	for k := range allowed {
+		if allowed[k] == nil || allowed[k].Sign() < 0 || allowed[k].Cmp(publicKey.N) != -1 {
+			return nil, errors.New("allowed values must satisfy 0 <= m < N")
+		}
+
+		statement, err := sumStatement(publicKey, []*big.Int{ciphertext}, allowed[k])
+		if err != nil {
+			return nil, err
+		}
+
+		u[k] = statement
+	}
+
+	return u, nil
+}
+
+// Collects the values hashed into the challenge of a membership proof
+func membershipTranscript(publicKey *PublicKey, ciphertext *big.Int, allowed, commitments []*big.Int) []*big.Int {
+	transcript := []*big.Int{publicKey.N, ciphertext}
+	transcript = append(transcript, allowed...)
+
+	return append(transcript, commitments...)
+}
+
+// Collects the values hashed into the challenge of a knowledge proof.
+// The context is hashed first so that contexts with leading zero bytes remain distinct.
+func knowledgeTranscript(publicKey *PublicKey, ciphertext, commitment *big.Int, context string) []*big.Int {
+	contextHash := sha256.Sum256([]byte(context))
+
+	return []*big.Int{new(big.Int).SetBytes(contextHash[:]), publicKey.N, ciphertext, commitment}
+}
+
+// Computes u = prod(c_i) * g^-sum % n^2
+func sumStatement(publicKey *PublicKey, ciphertexts []*big.Int, sum *big.Int) (*big.Int, error) {
+	product := new(big.Int).SetInt64(1)
+	for _, c := range ciphertexts {
+		if c.Sign() <= 0 || c.Cmp(publicKey.NSquare) != -1 {
+			return nil, errors.New("ciphertext is out of range")
+		}
+
+		product.Mul(product, c).Mod(product, publicKey.NSquare)
+	}
+
+	gSum := new(big.Int).Exp(publicKey.G, sum, publicKey.NSquare)
+	gSumInverse := new(big.Int).ModInverse(gSum, publicKey.NSquare)
+	if gSumInverse == nil {
+		return nil, errors.New("public key is invalid")
+	}
+
+	return product.Mul(product, gSumInverse).Mod(product, publicKey.NSquare), nil
+}
+
+// Checks z^n = a * u^e % n^2
+func verifyNthRoot(publicKey *PublicKey, u, a, e, z *big.Int) bool {
+	if z.Sign() <= 0 || z.Cmp(publicKey.N) != -1 {
+		return false
+	}
+
+	lhs := new(big.Int).Exp(z, publicKey.N, publicKey.NSquare)
+
+	rhs := new(big.Int).Exp(u, e, publicKey.NSquare)
+	rhs.Mul(rhs, a).Mod(rhs, publicKey.NSquare)
+
+	return lhs.Cmp(rhs) == 0
+}
+
+// Hashes the given values into a Fiat-Shamir challenge in [0, 2^t)
+func hashChallenge(values ...*big.Int) *big.Int {
+	return new(big.Int).SetBytes(hashValues(values...))
+}
+
+// Hashes the given values into a Fiat-Shamir challenge in [0, 2^t), where t is given by challengeBits
+func proofChallenge(publicKey *PublicKey, values ...*big.Int) *big.Int {
+	challenge := hashChallenge(values...)
+
+	return challenge.Mod(challenge, challengeModulus(publicKey))
+}
+
+// Computes the SHA-256 hash of the given values.
+// Each value is length-prefixed to avoid ambiguous encodings.
+func hashValues(values ...*big.Int) []byte {
+	hash := sha256.New()
+
+	for _, v := range values {
+		data := v.Bytes()
+
+		var length [8]byte
+		binary.BigEndian.PutUint64(length[:], uint64(len(data)))
+
+		hash.Write(length[:])
+		hash.Write(data)
+	}
+
+	return hash.Sum(nil)
+}
+
+// Returns true if the challenge of a branch of an OR proof is in [0, 2^t).
+// Unbounded challenges forge the proofs, as a challenge that is a multiple of n makes any u_k^e_k an n-th power.
+func inChallengeRange(publicKey *PublicKey, challenge *big.Int) bool {
+	return challenge.Sign() >= 0 && challenge.Cmp(challengeModulus(publicKey)) == -1
+}
+
+func challengeModulus(publicKey *PublicKey) *big.Int {
+	return new(big.Int).Lsh(big.NewInt(1), uint(challengeBits(publicKey)))
+}
+
+// Returns the bit length t of the challenge of proofs under publicKey.
+// The proofs are only sound if 2^t is smaller than the prime factors of N, otherwise a prover can answer
+// two challenges that differ by a multiple of a prime factor without knowing the n-th root.
+// The primes are the same length, so the smaller one has at least half the bits of N less one.
+func challengeBits(publicKey *PublicKey) int {
+	return max(1, min(challengeLength, publicKey.N.BitLen()/2-1))
+}
+
+// Selects a random number such that 0 < r < n and gcd(r, n) = 1
+func randomUnit(reader io.Reader, n *big.Int) (*big.Int, error) {
+	one := new(big.Int).SetInt64(1)
+
+	for {
+		r, err := rand.Int(reader, n)
+		if err != nil {
+			return nil, err
+		}
+
+		if r.Sign() > 0 && new(big.Int).GCD(nil, nil, r, n).Cmp(one) == 0 {
+			return r, nil
+		}
+	}
+}
+
+// =============================================================================
+// Binary Proof
+// =============================================================================
+
+// Proof that a ciphertext encrypts either 0 or 1.
+// A, E & Z are the commitment, challenge & response of each branch.
+type BinaryProof struct {
+	A0 *big.Int `json:"A0"`
+	A1 *big.Int `json:"A1"`
+	E0 *big.Int `json:"E0"`
+	E1 *big.Int `json:"E1"`
+	Z0 *big.Int `json:"Z0"`
+	Z1 *big.Int `json:"Z1"`
+}
+
+func (p BinaryProof) IsEqual(other interface{}) bool {
+	otherObj, ok := other.(BinaryProof)
+	if !ok {
+		return false
+	}
+
+	if p.A0.Cmp(otherObj.A0) != 0 || p.A1.Cmp(otherObj.A1) != 0 {
+		return false
+	}
+
+	if p.E0.Cmp(otherObj.E0) != 0 || p.E1.Cmp(otherObj.E1) != 0 {
+		return false
+	}
+
+	if p.Z0.Cmp(otherObj.Z0) != 0 || p.Z1.Cmp(otherObj.Z1) != 0 {
+		return false
+	}
+
+	return true
+}
+
+// =============================================================================
+// Sum Proof
+// =============================================================================
+
+// Proof that a set of ciphertexts encrypt plaintexts with a known sum.
+// A & Z are the commitment & response. The challenge is recomputed when verifying.
+type SumProof struct {
+	A *big.Int `json:"A"`
+	Z *big.Int `json:"Z"`
+}
+
+func (p SumProof) IsEqual(other interface{}) bool {
+	otherObj, ok := other.(SumProof)
+	if !ok {
+		return false
+	}
+
+	if p.A.Cmp(otherObj.A) != 0 || p.Z.Cmp(otherObj.Z) != 0 {
+		return false
+	}
+
+	return true
+}
+
+// =============================================================================
+// Membership Proof
+// =============================================================================
+
+// Proof that a ciphertext encrypts one of a set of allowed values.
+// A, E & Z hold the commitment, challenge & response of each branch, in the order of the allowed values.
+type MembershipProof struct {
+	A []*big.Int `json:"A"`
+	E []*big.Int `json:"E"`
+	Z []*big.Int `json:"Z"`
+}
+
+func (p MembershipProof) IsEqual(other interface{}) bool {
+	otherObj, ok := other.(MembershipProof)
+	if !ok {
+		return false
+	}
+
+	return isEqualSlice(p.A, otherObj.A) && isEqualSlice(p.E, otherObj.E) && isEqualSlice(p.Z, otherObj.Z)
+}
+
+// =============================================================================
+// Knowledge Proof
+// =============================================================================
+
+// Proof of knowledge of the plaintext & randomness of a ciphertext, bound to a context.
+// A is the commitment, ZM & ZR are the responses for the plaintext & randomness. The challenge is recomputed when verifying.
+type KnowledgeProof struct {
+	A  *big.Int `json:"A"`
+	ZM *big.Int `json:"ZM"`
+	ZR *big.Int `json:"ZR"`
+}
+
+func (p KnowledgeProof) IsEqual(other interface{}) bool {
+	otherObj, ok := other.(KnowledgeProof)
+	if !ok {
+		return false
+	}
+
+	if p.A.Cmp(otherObj.A) != 0 || p.ZM.Cmp(otherObj.ZM) != 0 || p.ZR.Cmp(otherObj.ZR) != 0 {
+		return false
+	}
+
+	return true
+}
+
+// =============================================================================
+// Decryption Proof
+// =============================================================================
+
+// Proof that a plaintext is the decryption of a ciphertext.
+// A & Z are the commitment & response. The challenge is recomputed when verifying.
+type DecryptionProof struct {
+	A *big.Int `json:"A"`
+	Z *big.Int `json:"Z"`
+}
+
+func (p DecryptionProof) IsEqual(other interface{}) bool {
+	otherObj, ok := other.(DecryptionProof)
+	if !ok {
+		return false
+	}
+
+	if p.A.Cmp(otherObj.A) != 0 || p.Z.Cmp(otherObj.Z) != 0 {
+		return false
+	}
+
+	return true
+}
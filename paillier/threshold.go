@@ -0,0 +1,501 @@
+// Threshold variant of the Paillier Cryptosystem.
+// This follows the scheme described by Damgård & Jurik (2001) for s = 1,
+// which itself is based on Shoup's threshold RSA signatures (2000).
+// The decryption exponent is split into shares with Shamir secret sharing
+// such that any t of the l shareholders can decrypt a ciphertext together,
+// while fewer than t shareholders learn nothing about the plaintext.
+// Each partial decryption carries a proof that it was computed with the share of its index,
+// checked against the share's verification key before partial decryptions are combined.
+
+package paillier
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+)
+
+// =============================================================================
+// Operations
+// =============================================================================
+
+// Generates a threshold public key and numShares key shares, of which any threshold
+// shares are required to decrypt a ciphertext.
+// p & q are generated as safe primes (p = 2p' + 1) which is required by the scheme.
+// Generating safe primes is considerably slower than GenerateKeys for large lengths.
+func GenerateThresholdKeys(length, threshold, numShares int) (*ThresholdPublicKey, []*KeyShare, error) {
+	if length < 16 {
+		return nil, nil, errors.New("length must be greater than 16")
+	}
+
+	if threshold < 1 || numShares < threshold {
+		return nil, nil, errors.New("threshold must satisfy 1 <= threshold <= numShares")
+	}
+
+	// Generate distinct safe primes p = 2p' + 1 & q = 2q' + 1
+	p, pPrime, err := generateSafePrime(length)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var q, qPrime *big.Int
+	for {
+		if q, qPrime, err = generateSafePrime(length); err != nil {
+			return nil, nil, err
+		}
+
+		if q.Cmp(p) != 0 {
+			break
+		}
+	}
+
+	// The shareholders can only decrypt if delta is invertible mod n,
+	// which is guaranteed as long as both primes are larger than numShares
+	if p.Cmp(big.NewInt(int64(numShares))) <= 0 || q.Cmp(big.NewInt(int64(numShares))) <= 0 {
+		return nil, nil, errors.New("length is too small for the number of shares")
+	}
+
+	one := new(big.Int).SetInt64(1)
+
+	// Compute public key variables as per GenerateKeys
+	var (
+		n        = new(big.Int).Mul(p, q)
+		nSquared = new(big.Int).Mul(n, n)
+		g        = new(big.Int).Add(n, one)
+	)
+
+	// Compute the secret decryption exponent d such that
+	// d = 0 mod m and d = 1 mod n, where m = p' * q'
+	var (
+		m    = new(big.Int).Mul(pPrime, qPrime)
+		nm   = new(big.Int).Mul(n, m)
+		mInv = new(big.Int).ModInverse(m, n)
+		d    = new(big.Int).Mod(new(big.Int).Mul(m, mInv), nm)
+	)
+
+	// Share d using a random polynomial f of degree threshold - 1 over Z_nm, where f(0) = d
+	coefficients := []*big.Int{d}
+	for i := 1; i < threshold; i++ {
+		a, err := rand.Int(rand.Reader, nm)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		coefficients = append(coefficients, a)
+	}
+
+	shares := []*KeyShare{}
+	for i := 1; i <= numShares; i++ {
+		x := big.NewInt(int64(i))
+
+		// Evaluate f(i) using Horner's method
+		s := new(big.Int)
+		for j := len(coefficients) - 1; j >= 0; j-- {
+			s.Mul(s, x)
+			s.Add(s, coefficients[j])
+			s.Mod(s, nm)
+		}
+
+		shares = append(shares, &KeyShare{int64(i), s, int64(length)})
+	}
+
+	delta := factorial(int64(numShares))
+
+	// The verification base v is a random square, which generates the squares of Z*_n^2 with high probability.
+	// The verification key of share i is v_i = v^(delta * s_i) % n^2
+	r, err := randomUnit(rand.Reader, nSquared)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	v := new(big.Int).Exp(r, big.NewInt(2), nSquared)
+
+	verificationKeys := []*big.Int{}
+	for _, share := range shares {
+		exponent := new(big.Int).Mul(delta, share.Share)
+		verificationKeys = append(verificationKeys, new(big.Int).Exp(v, exponent, nSquared))
+	}
+
+	publicKey := &ThresholdPublicKey{
+		PublicKey:        PublicKey{n, nSquared, g, int64(length)},
+		Threshold:        int64(threshold),
+		NumShares:        int64(numShares),
+		Delta:            delta,
+		VerificationBase: v,
+		VerificationKeys: verificationKeys,
+	}
+
+	return publicKey, shares, nil
+}
+
+// Computes a shareholder's partial decryption of a ciphertext, with a proof that it was computed with share.
+// c_i = c^(2 * delta * s_i) % n^2
+func PartialDecrypt(publicKey *ThresholdPublicKey, share *KeyShare, value *big.Int) (*PartialDecryption, error) {
+	return PartialDecryptWithReader(rand.Reader, publicKey, share, value)
+}
+
+// Computes a shareholder's partial decryption of a ciphertext, with the random values of the proof drawn from reader
+func PartialDecryptWithReader(reader io.Reader, publicKey *ThresholdPublicKey, share *KeyShare, value *big.Int) (*PartialDecryption, error) {
+	if value.Sign() <= 0 || value.Cmp(publicKey.NSquare) != -1 {
+		return nil, errors.New("value is out of range")
+	}
+
+	if share.Index < 1 || share.Index > publicKey.NumShares {
+		return nil, fmt.Errorf("key share %d is out of range", share.Index)
+	}
+
+	if err := publicKey.checkVerificationKeys(); err != nil {
+		return nil, err
+	}
+
+	// x = delta * s_i, such that c_i = c^(2x) & v_i = v^x
+	x := new(big.Int).Mul(publicKey.Delta, share.Share)
+	partial := new(big.Int).Exp(value, new(big.Int).Lsh(x, 1), publicKey.NSquare)
+
+	// Prove log_(c^4)(c_i^2) = log_v(v_i) = x
+	// a = c^(4r) % n^2, b = v^r % n^2
+	// z = r + e * x
+	// r is larger than e * x by 2^t, which hides x statistically
+	bound := new(big.Int).Lsh(big.NewInt(1), uint(publicKey.NSquare.BitLen()+publicKey.Delta.BitLen()+2*challengeLength))
+	r, err := rand.Int(reader, bound)
+	if err != nil {
+		return nil, err
+	}
+
+	base, verificationKey, partialSquared := partialStatements(publicKey, share.Index, value, partial)
+
+	a := new(big.Int).Exp(base, r, publicKey.NSquare)
+	b := new(big.Int).Exp(publicKey.VerificationBase, r, publicKey.NSquare)
+	challenge := partialChallenge(publicKey, publicKey.N, publicKey.VerificationBase, base, verificationKey, partialSquared, a, b)
+
+	response := new(big.Int).Mul(challenge, x)
+	response.Add(response, r)
+
+	return &PartialDecryption{
+		Index: share.Index,
+		Value: partial,
+		Proof: &PartialDecryptionProof{E: challenge, Z: response},
+	}, nil
+}
+
+// Verifies that partial is the partial decryption of value computed with the key share of its index,
+// against the verification key of that share
+func VerifyPartialDecryption(publicKey *ThresholdPublicKey, value *big.Int, partial *PartialDecryption) bool {
+	if partial == nil || partial.Value == nil || partial.Proof == nil || partial.Proof.E == nil || partial.Proof.Z == nil {
+		return false
+	}
+
+	if publicKey.checkVerificationKeys() != nil || partial.Index < 1 || partial.Index > publicKey.NumShares {
+		return false
+	}
+
+	if value.Sign() <= 0 || value.Cmp(publicKey.NSquare) != -1 {
+		return false
+	}
+
+	if partial.Value.Sign() <= 0 || partial.Value.Cmp(publicKey.NSquare) != -1 || partial.Proof.Z.Sign() < 0 {
+		return false
+	}
+
+	base, verificationKey, partialSquared := partialStatements(publicKey, partial.Index, value, partial.Value)
+
+	// a = c^(4z) * c_i^(-2e) % n^2, b = v^z * v_i^-e % n^2
+	partialInverse := new(big.Int).ModInverse(new(big.Int).Exp(partialSquared, partial.Proof.E, publicKey.NSquare), publicKey.NSquare)
+	verificationInverse := new(big.Int).ModInverse(new(big.Int).Exp(verificationKey, partial.Proof.E, publicKey.NSquare), publicKey.NSquare)
+	if partialInverse == nil || verificationInverse == nil {
+		return false
+	}
+
+	a := new(big.Int).Exp(base, partial.Proof.Z, publicKey.NSquare)
+	a.Mul(a, partialInverse).Mod(a, publicKey.NSquare)
+
+	b := new(big.Int).Exp(publicKey.VerificationBase, partial.Proof.Z, publicKey.NSquare)
+	b.Mul(b, verificationInverse).Mod(b, publicKey.NSquare)
+
+	challenge := partialChallenge(publicKey, publicKey.N, publicKey.VerificationBase, base, verificationKey, partialSquared, a, b)
+
+	return challenge.Cmp(partial.Proof.E) == 0
+}
+
+// Combines the partial decryptions of value into its plaintext.
+// At least Threshold partial decryptions with distinct indices must be provided.
+// Missing, out of range & duplicate partial decryptions are skipped, and the first Threshold partial decryptions
+// with distinct indices are used. Each of them must have a valid proof.
+func CombinePartialDecryptions(publicKey *ThresholdPublicKey, value *big.Int, partials []*PartialDecryption) (*big.Int, error) {
+	if int64(len(partials)) < publicKey.Threshold {
+		return nil, fmt.Errorf("%d partial decryptions are required but only %d were provided", publicKey.Threshold, len(partials))
+	}
+
+	selected := []*PartialDecryption{}
+	seen := map[int64]bool{}
+	for _, partial := range partials {
+		if int64(len(selected)) == publicKey.Threshold {
+			break
+		}
+
+		if partial == nil || partial.Index < 1 || partial.Index > publicKey.NumShares || seen[partial.Index] {
+			continue
+		}
+		seen[partial.Index] = true
+
+		if !VerifyPartialDecryption(publicKey, value, partial) {
+			return nil, fmt.Errorf("partial decryption %d has an invalid proof", partial.Index)
+		}
+
+		selected = append(selected, partial)
+	}
+
+	if int64(len(selected)) < publicKey.Threshold {
+		return nil, fmt.Errorf("%d partial decryptions with distinct indices are required but only %d were provided", publicKey.Threshold, len(selected))
+	}
+	partials = selected
+
+	// Combine partial decryptions with the integer Lagrange coefficients
+	// c' = prod(c_i^(2 * lambda_i)) % n^2
+	//    = c^(4 * delta^2 * d) % n^2
+	two := big.NewInt(2)
+	combined := new(big.Int).SetInt64(1)
+
+	for _, partial := range partials {
+		lambda := lagrangeCoefficient(publicKey.Delta, partial.Index, partials)
+		exponent := new(big.Int).Mul(two, lambda)
+
+		base := partial.Value
+		if exponent.Sign() < 0 {
+			if base = new(big.Int).ModInverse(partial.Value, publicKey.NSquare); base == nil {
+				return nil, fmt.Errorf("partial decryption %d is not invertible", partial.Index)
+			}
+			exponent.Neg(exponent)
+		}
+
+		combined.Mul(combined, new(big.Int).Exp(base, exponent, publicKey.NSquare))
+		combined.Mod(combined, publicKey.NSquare)
+	}
+
+	// Since d = 0 mod m and d = 1 mod n, c' = (1 + n)^(4 * delta^2 * m) % n^2
+	// m = L(c') * (4 * delta^2)^-1 % n
+	one := new(big.Int).SetInt64(1)
+
+	fourDeltaSquared := new(big.Int).Mul(publicKey.Delta, publicKey.Delta)
+	fourDeltaSquared.Mul(fourDeltaSquared, big.NewInt(4))
+
+	inverse := new(big.Int).ModInverse(fourDeltaSquared, publicKey.N)
+	if inverse == nil {
+		return nil, errors.New("public key is not valid for threshold decryption")
+	}
+
+	var (
+		l = new(big.Int).Div(new(big.Int).Sub(combined, one), publicKey.N)
+		m = new(big.Int).Mod(new(big.Int).Mul(l, inverse), publicKey.N)
+	)
+
+	return m, nil
+}
+
+// =============================================================================
+// Helpers
+// =============================================================================
+
+// Generates a safe prime p = 2p' + 1 of the given bit length.
+// Returns both p & p'
+func generateSafePrime(length int) (*big.Int, *big.Int, error) {
+	one := new(big.Int).SetInt64(1)
+
+	for {
+		pPrime, err := rand.Prime(rand.Reader, length-1)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		p := new(big.Int).Lsh(pPrime, 1)
+		p.Add(p, one)
+
+		if p.BitLen() == length && p.ProbablyPrime(20) {
+			return p, pPrime, nil
+		}
+	}
+}
+
+// Computes the integer Lagrange coefficient for interpolating at 0
+// lambda_i = delta * prod(j / (j - i)) for all j != i in the set of partials
+func lagrangeCoefficient(delta *big.Int, index int64, partials []*PartialDecryption) *big.Int {
+	numerator := new(big.Int).Set(delta)
+	denominator := new(big.Int).SetInt64(1)
+
+	for _, partial := range partials {
+		if partial.Index == index {
+			continue
+		}
+
+		numerator.Mul(numerator, big.NewInt(partial.Index))
+		denominator.Mul(denominator, big.NewInt(partial.Index-index))
+	}
+
+	// delta is divisible by the denominator, so the result is always an integer
+	return numerator.Quo(numerator, denominator)
+}
+
+// Computes the statements of the proof of a partial decryption: c^4, v_i & c_i^2 % n^2
+func partialStatements(publicKey *ThresholdPublicKey, index int64, value, partial *big.Int) (*big.Int, *big.Int, *big.Int) {
+	base := new(big.Int).Exp(value, big.NewInt(4), publicKey.NSquare)
+	partialSquared := new(big.Int).Exp(partial, big.NewInt(2), publicKey.NSquare)
+
+	return base, publicKey.VerificationKeys[index-1], partialSquared
+}
+
+// Hashes the given values into the challenge of a partial decryption proof.
+// The proof is over the squares of Z*_n^2, whose order n * p' * q' has prime factors one bit shorter than p & q,
+// so the challenge is one bit shorter than for the proofs of the embedded PublicKey.
+func partialChallenge(publicKey *ThresholdPublicKey, values ...*big.Int) *big.Int {
+	challenge := hashChallenge(values...)
+
+	return challenge.Mod(challenge, new(big.Int).Rsh(challengeModulus(&publicKey.PublicKey), 1))
+}
+
+func factorial(n int64) *big.Int {
+	return new(big.Int).MulRange(1, n)
+}
+
+// =============================================================================
+// Threshold Public Key
+// =============================================================================
+
+// Public key for threshold decryption.
+// The embedded PublicKey is used for encryption & homomorphic operations as normal.
+// VerificationKeys holds the verification key of each share, in the order of their indices.
+type ThresholdPublicKey struct {
+	PublicKey
+	Threshold        int64      `json:"Threshold"`
+	NumShares        int64      `json:"NumShares"`
+	Delta            *big.Int   `json:"Delta"`
+	VerificationBase *big.Int   `json:"VerificationBase"`
+	VerificationKeys []*big.Int `json:"VerificationKeys"`
+}
+
+func (k ThresholdPublicKey) IsEqual(other interface{}) bool {
+	otherObj, ok := other.(ThresholdPublicKey)
+	if !ok {
+		return false
+	}
+
+	if !k.PublicKey.IsEqual(otherObj.PublicKey) {
+		return false
+	}
+
+	if k.Threshold != otherObj.Threshold || k.NumShares != otherObj.NumShares {
+		return false
+	}
+
+	if k.Delta.Cmp(otherObj.Delta) != 0 {
+		return false
+	}
+
+	if k.VerificationBase.Cmp(otherObj.VerificationBase) != 0 || !isEqualSlice(k.VerificationKeys, otherObj.VerificationKeys) {
+		return false
+	}
+
+	return true
+}
+
+// Ensures the key has a verification key for every share.
+// Keys generated before partial decryptions were proven have none, and must be generated again.
+func (k ThresholdPublicKey) checkVerificationKeys() error {
+	if k.VerificationBase == nil || int64(len(k.VerificationKeys)) != k.NumShares {
+		return errors.New("threshold public key has no verification keys")
+	}
+
+	for _, verificationKey := range k.VerificationKeys {
+		if verificationKey == nil {
+			return errors.New("threshold public key has no verification keys")
+		}
+	}
+
+	return nil
+}
+
+// =============================================================================
+// Key Share
+// =============================================================================
+
+// Secret key share held by a single shareholder (trustee).
+// Index starts from 1.
+type KeyShare struct {
+	Index  int64    `json:"Index"`
+	Share  *big.Int `json:"Share"`
+	Length int64    `json:"Length"`
+}
+
+func (k KeyShare) IsEqual(other interface{}) bool {
+	otherObj, ok := other.(KeyShare)
+	if !ok {
+		return false
+	}
+
+	if k.Index != otherObj.Index || k.Length != otherObj.Length {
+		return false
+	}
+
+	if k.Share.Cmp(otherObj.Share) != 0 {
+		return false
+	}
+
+	return true
+}
+
+// =============================================================================
+// Partial Decryption
+// =============================================================================
+
+// Partial decryption of a ciphertext computed with the key share of the same index.
+type PartialDecryption struct {
+	Index int64                   `json:"Index"`
+	Value *big.Int                `json:"Value"`
+	Proof *PartialDecryptionProof `json:"Proof"`
+}
+
+func (p PartialDecryption) IsEqual(other interface{}) bool {
+	otherObj, ok := other.(PartialDecryption)
+	if !ok {
+		return false
+	}
+
+	if p.Index != otherObj.Index {
+		return false
+	}
+
+	if p.Value.Cmp(otherObj.Value) != 0 {
+		return false
+	}
+
+	if (p.Proof == nil) != (otherObj.Proof == nil) {
+		return false
+	}
+
+	return p.Proof == nil || p.Proof.IsEqual(*otherObj.Proof)
+}
+
+// =============================================================================
+// Partial Decryption Proof
+// =============================================================================
+
+// Proof that a partial decryption was computed with the key share of its index.
+// E & Z are the challenge & response. The commitments are recomputed when verifying.
+type PartialDecryptionProof struct {
+	E *big.Int `json:"E"`
+	Z *big.Int `json:"Z"`
+}
+
+func (p PartialDecryptionProof) IsEqual(other interface{}) bool {
+	otherObj, ok := other.(PartialDecryptionProof)
+	if !ok {
+		return false
+	}
+
+	if p.E.Cmp(otherObj.E) != 0 || p.Z.Cmp(otherObj.Z) != 0 {
+		return false
+	}
+
+	return true
+}
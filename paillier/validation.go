@@ -0,0 +1,173 @@
+package paillier
+
+import (
+	"crypto/rand"
+	"errors"
+	"math/big"
+)
+
+// =============================================================================
+// Validation
+// =============================================================================
+
+// Checks that a public key is well-formed.
+// This does not prove that N is the product of two primes, only that the key is consistent.
+func ValidatePublicKey(publicKey *PublicKey) error {
+	if publicKey == nil || publicKey.N == nil || publicKey.NSquare == nil || publicKey.G == nil {
+		return errors.New("public key is missing values")
+	}
+
+	if publicKey.N.Cmp(big.NewInt(1)) != 1 || publicKey.N.Bit(0) == 0 {
+		return errors.New("public key N must be an odd number greater than 1")
+	}
+
+	if new(big.Int).Mul(publicKey.N, publicKey.N).Cmp(publicKey.NSquare) != 0 {
+		return errors.New("public key NSquare must equal N * N")
+	}
+
+	if new(big.Int).Add(publicKey.N, big.NewInt(1)).Cmp(publicKey.G) != 0 {
+		return errors.New("public key G must equal N + 1")
+	}
+
+	// N is the product of two primes of Length bits each
+	if bitLength := int64(publicKey.N.BitLen()); bitLength != 2*publicKey.Length && bitLength != 2*publicKey.Length-1 {
+		return errors.New("public key Length does not match the size of N")
+	}
+
+	// N = p * p is insecure as it can be factorised trivially
+	if root := new(big.Int).Sqrt(publicKey.N); new(big.Int).Mul(root, root).Cmp(publicKey.N) == 0 {
+		return errors.New("public key N must not be a perfect square")
+	}
+
+	return nil
+}
+
+// Checks that a private key is well-formed and matches the public key.
+// A random value is encrypted & decrypted to ensure the pair is usable.
+func ValidatePrivateKey(publicKey *PublicKey, privateKey *PrivateKey) error {
+	if err := ValidatePublicKey(publicKey); err != nil {
+		return err
+	}
+
+	if privateKey == nil || privateKey.Lambda == nil || privateKey.Mu == nil {
+		return errors.New("private key is missing values")
+	}
+
+	if privateKey.Length != publicKey.Length {
+		return errors.New("private key Length does not match public key")
+	}
+
+	if privateKey.Lambda.Sign() != 1 || privateKey.Mu.Sign() != 1 || privateKey.Mu.Cmp(publicKey.N) != -1 {
+		return errors.New("private key Lambda & Mu must be positive and Mu must be less than N")
+	}
+
+	if (privateKey.P == nil) != (privateKey.Q == nil) {
+		return errors.New("private key must contain both P & Q or neither")
+	}
+
+	if privateKey.P != nil {
+		if privateKey.P.Cmp(privateKey.Q) == 0 {
+			return errors.New("private key P & Q must be distinct")
+		}
+
+		if new(big.Int).Mul(privateKey.P, privateKey.Q).Cmp(publicKey.N) != 0 {
+			return errors.New("private key P * Q must equal N")
+		}
+	}
+
+	// mu = L(g^lambda % n^2)^-1 % n, which ensures g decrypts to 1
+	one := new(big.Int).SetInt64(1)
+
+	x := new(big.Int).Exp(publicKey.G, privateKey.Lambda, publicKey.NSquare)
+	l := new(big.Int).Div(new(big.Int).Sub(x, one), publicKey.N)
+	if new(big.Int).Mod(new(big.Int).Mul(l, privateKey.Mu), publicKey.N).Cmp(one) != 0 {
+		return errors.New("private key Mu does not match Lambda")
+	}
+
+	// Round trip a random value through both decryption paths
+	value, err := rand.Int(rand.Reader, publicKey.N)
+	if err != nil {
+		return err
+	}
+
+	encrypted, err := Encrypt(publicKey, value)
+	if err != nil {
+		return err
+	}
+
+	keys := []*PrivateKey{{Lambda: privateKey.Lambda, Mu: privateKey.Mu, Length: privateKey.Length}}
+	if privateKey.P != nil {
+		keys = append(keys, privateKey)
+	}
+
+	for _, key := range keys {
+		decrypted, err := Decrypt(publicKey, key, encrypted)
+		if err != nil {
+			return err
+		}
+
+		if decrypted.Cmp(value) != 0 {
+			return errors.New("private key does not decrypt values encrypted with public key")
+		}
+	}
+
+	return nil
+}
+
+// Checks that a threshold public key is well-formed, so that partial decryptions combine into the plaintext.
+// This does not prove that the verification keys belong to the shares, which is checked by the proofs of
+// partial decryptions.
+func ValidateThresholdPublicKey(publicKey *ThresholdPublicKey) error {
+	if publicKey == nil {
+		return errors.New("threshold public key is missing values")
+	}
+
+	if err := ValidatePublicKey(&publicKey.PublicKey); err != nil {
+		return err
+	}
+
+	if publicKey.Threshold < 1 || publicKey.NumShares < publicKey.Threshold {
+		return errors.New("threshold public key must satisfy 1 <= Threshold <= NumShares")
+	}
+
+	// Checked before Delta, so that computing NumShares! is bounded by the size of the key
+	if int64(len(publicKey.VerificationKeys)) != publicKey.NumShares {
+		return errors.New("threshold public key must have a verification key for every share")
+	}
+
+	// Delta = NumShares!
+	delta := big.NewInt(1)
+	for i := int64(2); i <= publicKey.NumShares; i++ {
+		delta.Mul(delta, big.NewInt(i))
+	}
+
+	if publicKey.Delta == nil || publicKey.Delta.Cmp(delta) != 0 {
+		return errors.New("threshold public key Delta must equal the factorial of NumShares")
+	}
+
+	// v & every v_i must be in Z*_{n^2}
+	for _, value := range append([]*big.Int{publicKey.VerificationBase}, publicKey.VerificationKeys...) {
+		if err := ValidateCiphertext(&publicKey.PublicKey, value); err != nil {
+			return errors.New("threshold public key verification keys must be in Z*_{n^2}")
+		}
+	}
+
+	return nil
+}
+
+// Checks that a ciphertext is an element of Z*_{n^2}, i.e. 0 < c < n^2 and gcd(c, n) = 1
+func ValidateCiphertext(publicKey *PublicKey, ciphertext *big.Int) error {
+	if ciphertext == nil {
+		return errors.New("ciphertext is missing")
+	}
+
+	if ciphertext.Sign() != 1 || ciphertext.Cmp(publicKey.NSquare) != -1 {
+		return errors.New("ciphertext must satisfy 0 < c < N^2")
+	}
+
+	if new(big.Int).GCD(nil, nil, ciphertext, publicKey.N).Cmp(big.NewInt(1)) != 0 {
+		return errors.New("ciphertext must be coprime with N")
+	}
+
+	return nil
+}
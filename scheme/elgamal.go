@@ -0,0 +1,163 @@
+package scheme
+
+import (
+	"math/big"
+
+	elgamal "github.com/direnbharwani/evote-capstone/elgamal"
+)
+
+// Default upper bound of plaintexts that can be decrypted with exponential ElGamal.
+// This covers tallies of up to ~1M voters, with a lookup table of ~1000 entries.
+const DefaultMaxPlaintext = 1 << 20
+
+// =============================================================================
+// ElGamal
+// =============================================================================
+
+// ElGamal implements HomomorphicScheme with exponential ElGamal over the prime-order subgroup of a safe prime.
+// Keys & ciphertexts are *elgamal.PublicKey, *elgamal.PrivateKey & *elgamal.Ciphertext.
+// Only plaintexts up to MaxPlaintext can be decrypted, as decryption requires a discrete logarithm.
+type ElGamal struct {
+	MaxPlaintext int64
+}
+
+func (ElGamal) Name() string {
+	return ElGamalName
+}
+
+func (ElGamal) GenerateKeys(length int) (PublicKey, PrivateKey, error) {
+	publicKey, privateKey, err := elgamal.GenerateKeys(length)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return publicKey, privateKey, nil
+}
+
+func (s ElGamal) Encrypt(publicKey PublicKey, value *big.Int) (Ciphertext, error) {
+	pk, err := s.publicKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := elgamal.Encrypt(pk, value)
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func (s ElGamal) Decrypt(publicKey PublicKey, privateKey PrivateKey, ciphertext Ciphertext) (*big.Int, error) {
+	pk, err := s.publicKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	sk, ok := privateKey.(*elgamal.PrivateKey)
+	if !ok {
+		return nil, &KeyTypeError{ElGamalName, privateKey}
+	}
+
+	c, err := s.ciphertext(ciphertext)
+	if err != nil {
+		return nil, err
+	}
+
+	return elgamal.Decrypt(pk, sk, c, s.MaxPlaintext)
+}
+
+func (s ElGamal) Add(publicKey PublicKey, lhs, rhs Ciphertext) (Ciphertext, error) {
+	pk, err := s.publicKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	lhsValue, err := s.ciphertext(lhs)
+	if err != nil {
+		return nil, err
+	}
+
+	rhsValue, err := s.ciphertext(rhs)
+	if err != nil {
+		return nil, err
+	}
+
+	return elgamal.AddEncrypted(pk, lhsValue, rhsValue), nil
+}
+
+func (s ElGamal) EncodePublicKey(publicKey PublicKey) (string, error) {
+	pk, err := s.publicKey(publicKey)
+	if err != nil {
+		return "", err
+	}
+
+	return elgamal.Base64Encode(pk)
+}
+
+func (ElGamal) DecodePublicKey(data string) (PublicKey, error) {
+	pk, err := elgamal.Base64Decode[elgamal.PublicKey](data)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = elgamal.ValidatePublicKey(pk); err != nil {
+		return nil, err
+	}
+
+	return pk, nil
+}
+
+func (ElGamal) EncodePrivateKey(privateKey PrivateKey) (string, error) {
+	sk, ok := privateKey.(*elgamal.PrivateKey)
+	if !ok {
+		return "", &KeyTypeError{ElGamalName, privateKey}
+	}
+
+	return elgamal.Base64Encode(sk)
+}
+
+func (ElGamal) DecodePrivateKey(data string) (PrivateKey, error) {
+	sk, err := elgamal.Base64Decode[elgamal.PrivateKey](data)
+	if err != nil {
+		return nil, err
+	}
+
+	return sk, nil
+}
+
+func (s ElGamal) EncodeCiphertext(ciphertext Ciphertext) (string, error) {
+	c, err := s.ciphertext(ciphertext)
+	if err != nil {
+		return "", err
+	}
+
+	return elgamal.Base64Encode(c)
+}
+
+func (ElGamal) DecodeCiphertext(data string) (Ciphertext, error) {
+	c, err := elgamal.Base64Decode[elgamal.Ciphertext](data)
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func (ElGamal) publicKey(publicKey PublicKey) (*elgamal.PublicKey, error) {
+	pk, ok := publicKey.(*elgamal.PublicKey)
+	if !ok {
+		return nil, &KeyTypeError{ElGamalName, publicKey}
+	}
+
+	return pk, nil
+}
+
+func (ElGamal) ciphertext(ciphertext Ciphertext) (*elgamal.Ciphertext, error) {
+	c, ok := ciphertext.(*elgamal.Ciphertext)
+	if !ok {
+		return nil, &KeyTypeError{ElGamalName, ciphertext}
+	}
+
+	return c, nil
+}
@@ -0,0 +1,157 @@
+package scheme
+
+import (
+	"errors"
+	"math/big"
+
+	paillier "github.com/direnbharwani/evote-capstone/paillier"
+)
+
+// =============================================================================
+// Paillier
+// =============================================================================
+
+// Paillier implements HomomorphicScheme with *paillier.PublicKey, *paillier.PrivateKey & *big.Int ciphertexts.
+// Ciphertexts are encoded in base 10, which matches the counts stored in ballots.
+type Paillier struct{}
+
+func (Paillier) Name() string {
+	return PaillierName
+}
+
+func (Paillier) GenerateKeys(length int) (PublicKey, PrivateKey, error) {
+	publicKey, privateKey, err := paillier.GenerateKeys(length)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return publicKey, privateKey, nil
+}
+
+func (s Paillier) Encrypt(publicKey PublicKey, value *big.Int) (Ciphertext, error) {
+	pk, err := s.publicKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := paillier.Encrypt(pk, value)
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func (s Paillier) Decrypt(publicKey PublicKey, privateKey PrivateKey, ciphertext Ciphertext) (*big.Int, error) {
+	pk, err := s.publicKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	sk, ok := privateKey.(*paillier.PrivateKey)
+	if !ok {
+		return nil, &KeyTypeError{PaillierName, privateKey}
+	}
+
+	c, err := s.ciphertext(ciphertext)
+	if err != nil {
+		return nil, err
+	}
+
+	return paillier.Decrypt(pk, sk, c)
+}
+
+func (s Paillier) Add(publicKey PublicKey, lhs, rhs Ciphertext) (Ciphertext, error) {
+	pk, err := s.publicKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	lhsValue, err := s.ciphertext(lhs)
+	if err != nil {
+		return nil, err
+	}
+
+	rhsValue, err := s.ciphertext(rhs)
+	if err != nil {
+		return nil, err
+	}
+
+	return paillier.AddEncrypted(pk, lhsValue, rhsValue), nil
+}
+
+func (s Paillier) EncodePublicKey(publicKey PublicKey) (string, error) {
+	pk, err := s.publicKey(publicKey)
+	if err != nil {
+		return "", err
+	}
+
+	return paillier.Base64Encode(pk)
+}
+
+func (Paillier) DecodePublicKey(data string) (PublicKey, error) {
+	pk, err := paillier.Base64Decode[paillier.PublicKey](data)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = paillier.ValidatePublicKey(pk); err != nil {
+		return nil, err
+	}
+
+	return pk, nil
+}
+
+func (Paillier) EncodePrivateKey(privateKey PrivateKey) (string, error) {
+	sk, ok := privateKey.(*paillier.PrivateKey)
+	if !ok {
+		return "", &KeyTypeError{PaillierName, privateKey}
+	}
+
+	return paillier.Base64Encode(sk)
+}
+
+func (Paillier) DecodePrivateKey(data string) (PrivateKey, error) {
+	sk, err := paillier.Base64Decode[paillier.PrivateKey](data)
+	if err != nil {
+		return nil, err
+	}
+
+	return sk, nil
+}
+
+func (s Paillier) EncodeCiphertext(ciphertext Ciphertext) (string, error) {
+	c, err := s.ciphertext(ciphertext)
+	if err != nil {
+		return "", err
+	}
+
+	return c.String(), nil
+}
+
+func (Paillier) DecodeCiphertext(data string) (Ciphertext, error) {
+	c, ok := new(big.Int).SetString(data, 10)
+	if !ok {
+		return nil, errors.New("failed to parse ciphertext")
+	}
+
+	return c, nil
+}
+
+func (Paillier) publicKey(publicKey PublicKey) (*paillier.PublicKey, error) {
+	pk, ok := publicKey.(*paillier.PublicKey)
+	if !ok {
+		return nil, &KeyTypeError{PaillierName, publicKey}
+	}
+
+	return pk, nil
+}
+
+func (Paillier) ciphertext(ciphertext Ciphertext) (*big.Int, error) {
+	c, ok := ciphertext.(*big.Int)
+	if !ok {
+		return nil, &KeyTypeError{PaillierName, ciphertext}
+	}
+
+	return c, nil
+}
@@ -0,0 +1,66 @@
+// Common interface over the additively homomorphic cryptosystems used to encrypt counts.
+// Keys & ciphertexts are opaque to callers, which only rely on the operations of the scheme
+// and its string encodings to store them as assets.
+
+package scheme
+
+import (
+	"fmt"
+	"math/big"
+)
+
+const (
+	PaillierName = "paillier"
+	ElGamalName  = "elgamal"
+)
+
+// Keys & ciphertexts of a scheme. Each scheme only accepts the concrete types it created.
+type (
+	PublicKey  interface{ Fingerprint() string }
+	PrivateKey any
+	Ciphertext any
+)
+
+// HomomorphicScheme is an additively homomorphic public key cryptosystem,
+// i.e. adding two ciphertexts produces an encryption of the sum of their plaintexts.
+type HomomorphicScheme interface {
+	Name() string
+
+	GenerateKeys(length int) (PublicKey, PrivateKey, error)
+	Encrypt(publicKey PublicKey, value *big.Int) (Ciphertext, error)
+	Decrypt(publicKey PublicKey, privateKey PrivateKey, ciphertext Ciphertext) (*big.Int, error)
+	Add(publicKey PublicKey, lhs, rhs Ciphertext) (Ciphertext, error)
+
+	EncodePublicKey(publicKey PublicKey) (string, error)
+	DecodePublicKey(data string) (PublicKey, error)
+	EncodePrivateKey(privateKey PrivateKey) (string, error)
+	DecodePrivateKey(data string) (PrivateKey, error)
+	EncodeCiphertext(ciphertext Ciphertext) (string, error)
+	DecodeCiphertext(data string) (Ciphertext, error)
+}
+
+// Returns the scheme with the given name.
+// An empty name refers to Paillier, the scheme used before schemes were recorded.
+func Get(name string) (HomomorphicScheme, error) {
+	switch name {
+	case "", PaillierName:
+		return Paillier{}, nil
+	case ElGamalName:
+		return ElGamal{MaxPlaintext: DefaultMaxPlaintext}, nil
+	default:
+		return nil, fmt.Errorf("unsupported homomorphic scheme %s", name)
+	}
+}
+
+// =============================================================================
+// Errors
+// =============================================================================
+
+type KeyTypeError struct {
+	Scheme string
+	Value  any
+}
+
+func (e *KeyTypeError) Error() string {
+	return fmt.Sprintf("%T cannot be used with the %s scheme", e.Value, e.Scheme)
+}
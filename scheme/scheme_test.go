@@ -0,0 +1,129 @@
+package scheme_test
+
+import (
+	"math/big"
+	"testing"
+
+	scheme "github.com/direnbharwani/evote-capstone/scheme"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHomomorphicScheme(t *testing.T) {
+	for _, name := range []string{scheme.PaillierName, scheme.ElGamalName} {
+		s, err := scheme.Get(name)
+		require.NoError(t, err)
+
+		publicKey, privateKey, err := s.GenerateKeys(64)
+		require.NoError(t, err)
+
+		t.Run("successfully add encrypted values with "+name, func(t *testing.T) {
+			lhs, err := s.Encrypt(publicKey, big.NewInt(12))
+			require.NoError(t, err)
+			rhs, err := s.Encrypt(publicKey, big.NewInt(30))
+			require.NoError(t, err)
+
+			sum, err := s.Add(publicKey, lhs, rhs)
+			require.NoError(t, err)
+
+			decrypted, err := s.Decrypt(publicKey, privateKey, sum)
+			require.NoError(t, err)
+			require.Equal(t, int64(42), decrypted.Int64())
+		})
+
+		t.Run("successfully encode and decode with "+name, func(t *testing.T) {
+			encrypted, err := s.Encrypt(publicKey, big.NewInt(7))
+			require.NoError(t, err)
+
+			publicKeyData, err := s.EncodePublicKey(publicKey)
+			require.NoError(t, err)
+			privateKeyData, err := s.EncodePrivateKey(privateKey)
+			require.NoError(t, err)
+			encryptedData, err := s.EncodeCiphertext(encrypted)
+			require.NoError(t, err)
+
+			decodedPublicKey, err := s.DecodePublicKey(publicKeyData)
+			require.NoError(t, err)
+			require.Equal(t, publicKey.Fingerprint(), decodedPublicKey.Fingerprint())
+
+			decodedPrivateKey, err := s.DecodePrivateKey(privateKeyData)
+			require.NoError(t, err)
+			decodedEncrypted, err := s.DecodeCiphertext(encryptedData)
+			require.NoError(t, err)
+
+			decrypted, err := s.Decrypt(decodedPublicKey, decodedPrivateKey, decodedEncrypted)
+			require.NoError(t, err)
+			require.Equal(t, int64(7), decrypted.Int64())
+		})
+
+		t.Run("fail to generate keys that are too short with "+name, func(t *testing.T) {
+			publicKey, privateKey, err := s.GenerateKeys(8)
+			require.Error(t, err)
+
+			// require.Nil would accept typed nil pointers, which do not equal nil inside the interfaces
+			require.True(t, publicKey == nil)
+			require.True(t, privateKey == nil)
+		})
+	}
+
+	t.Run("fail to use keys from another scheme", func(t *testing.T) {
+		paillierScheme, _ := scheme.Get(scheme.PaillierName)
+		elgamalScheme, _ := scheme.Get(scheme.ElGamalName)
+
+		publicKey, _, err := paillierScheme.GenerateKeys(64)
+		require.NoError(t, err)
+
+		_, err = elgamalScheme.Encrypt(publicKey, big.NewInt(1))
+		require.Error(t, err)
+	})
+
+	t.Run("fail to get unsupported scheme", func(t *testing.T) {
+		_, err := scheme.Get("rsa")
+		require.EqualError(t, err, "unsupported homomorphic scheme rsa")
+	})
+}
+
+// Compares the cost of tallying 100 votes with each scheme at comparable security levels.
+// The size of a single encrypted count is reported as bytes/ciphertext.
+func BenchmarkTally(b *testing.B) {
+	for _, name := range []string{scheme.PaillierName, scheme.ElGamalName} {
+		s, _ := scheme.Get(name)
+
+		// Paillier's length is the size of each prime factor of N, while ElGamal's is the size of P.
+		// Both result in a 1024 bit modulus, which keeps safe prime generation fast.
+		length := 512
+		if name == scheme.ElGamalName {
+			length = 1024
+		}
+
+		publicKey, privateKey, err := s.GenerateKeys(length)
+		require.NoError(b, err)
+
+		votes := []scheme.Ciphertext{}
+		for i := 0; i < 100; i++ {
+			vote, err := s.Encrypt(publicKey, big.NewInt(int64(i%2)))
+			require.NoError(b, err)
+
+			votes = append(votes, vote)
+		}
+
+		encoded, err := s.EncodeCiphertext(votes[0])
+		require.NoError(b, err)
+
+		b.Run(name, func(b *testing.B) {
+			b.ReportMetric(float64(len(encoded)), "bytes/ciphertext")
+
+			for i := 0; i < b.N; i++ {
+				tally := votes[0]
+				for _, vote := range votes[1:] {
+					if tally, err = s.Add(publicKey, tally, vote); err != nil {
+						b.Fatal(err)
+					}
+				}
+
+				if _, err = s.Decrypt(publicKey, privateKey, tally); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}